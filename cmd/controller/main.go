@@ -21,6 +21,8 @@ import (
 	"github.com/n3wscott/sources/pkg/reconciler/cronjobsource"
 	"github.com/n3wscott/sources/pkg/reconciler/jobsource"
 	"github.com/n3wscott/sources/pkg/reconciler/servicesource"
+	"github.com/n3wscott/sources/pkg/reconciler/sourceinstance"
+	"github.com/n3wscott/sources/pkg/reconciler/sourcequota"
 
 	// This defines the shared main for injected controllers.
 	"knative.dev/pkg/injection/sharedmain"
@@ -31,5 +33,7 @@ func main() {
 		jobsource.NewController,
 		cronjobsource.NewController,
 		servicesource.NewController,
+		sourceinstance.NewController,
+		sourcequota.NewController,
 	)
 }
@@ -9,8 +9,7 @@ import (
 	"cloud.google.com/go/firestore"
 	cloudevents "github.com/cloudevents/sdk-go"
 	"github.com/kelseyhightower/envconfig"
-	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
-	ceclient "github.com/n3wscott/sources/pkg/cloudeventclient"
+	"github.com/n3wscott/sources/pkg/sourcesdk"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
@@ -21,10 +20,6 @@ const (
 )
 
 type envConfig struct {
-	// Source options
-	Sink         string                    `envconfig:"K_SINK" required:"true"`
-	OutputFormat v1alpha1.OutputFormatType `envconfig:"K_OUTPUT_FORMAT" required:"true"`
-
 	// Database credentials
 	ServiceAccountCreds []byte `envconfig:"GOOGLE_APPLICATION_CREDS_JSON" required:"true"`
 
@@ -36,8 +31,7 @@ type envConfig struct {
 	since time.Duration // parsed version of above
 }
 
-func (env *envConfig) replayEvents(dbclient *firestore.Client, ceclient cloudevents.Client) error {
-	ctx := context.Background()
+func (env *envConfig) replayEvents(ctx context.Context, dbclient *firestore.Client, sender sourcesdk.Sender) error {
 	ticker := time.NewTicker(replaySpeed)
 	defer ticker.Stop()
 
@@ -62,20 +56,24 @@ func (env *envConfig) replayEvents(dbclient *firestore.Client, ceclient cloudeve
 			return fmt.Errorf("Could not read from database, failed to iterate: %v", err)
 		}
 
-		// Generate cloud event w/o parsing the doc at all
+		// Generate cloud event w/o parsing the doc at all. The sender sets
+		// the source from K_CE_SOURCE.
 		event := cloudevents.Event{
 			Context: cloudevents.EventContextV02{
-				Type:   "dev.knative.eventing.n3wscott.sources.demos.event-replay",
-				Source: *cloudevents.ParseURLRef("http://example.com/"),
+				Type: "dev.knative.eventing.n3wscott.sources.demos.event-replay",
 			}.AsV02(),
 			Data: doc.Data(),
 		}
 
-		if _, err := ceclient.Send(ctx, event); err != nil {
+		if _, err := sender.Send(ctx, event); err != nil {
 			return err
 		}
 
-		<-ticker.C
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
 
 	return nil
@@ -92,19 +90,17 @@ func main() {
 	}
 	env.since = since
 
-	ceclient, err := ceclient.New(env.OutputFormat, env.Sink)
-	if err != nil {
-		log.Fatal("Could not create CloudEvents client: ", err)
-	}
-
-	dbclient, err := firestore.NewClient(context.Background(), firestore.DetectProjectID, option.WithCredentialsJSON(env.ServiceAccountCreds))
-	if err != nil {
-		log.Fatal("Could not create firestore client: ", err)
-	}
+	sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+		dbclient, err := firestore.NewClient(ctx, firestore.DetectProjectID, option.WithCredentialsJSON(env.ServiceAccountCreds))
+		if err != nil {
+			return fmt.Errorf("Could not create firestore client: %v", err)
+		}
 
-	if err := env.replayEvents(dbclient, ceclient); err != nil {
-		log.Fatalf("Could not replay events: %v\n", err)
-	}
+		if err := env.replayEvents(ctx, dbclient, sender); err != nil {
+			return fmt.Errorf("Could not replay events: %v", err)
+		}
 
-	log.Println("Success; exiting now")
+		log.Println("Success; exiting now")
+		return nil
+	})
 }
@@ -18,14 +18,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"net/url"
-	"os"
-	"os/signal"
 	"path"
-	"syscall"
 
 	cloudevents "github.com/cloudevents/sdk-go"
 	cloudeventsclient "github.com/cloudevents/sdk-go/pkg/cloudevents/client"
@@ -33,22 +31,18 @@ import (
 	cloudeventshttp "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/http"
 	"github.com/google/uuid"
 	"github.com/kelseyhightower/envconfig"
-	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	moron "github.com/spencer-p/moroncloudevents"
+
+	"github.com/n3wscott/sources/pkg/sourcesdk"
 )
 
 const (
 	VERSION = "v0.0.1"
 
-	EVENT_SOURCE = "http://github.com/n3wscott/sources/cmd/demos/forwarder"
-	EVENT_TYPE   = "com.tryransom.forwarder"
+	EVENT_TYPE = "com.tryransom.forwarder"
 )
 
 type envConfig struct {
-	// Source options
-	Sink         string                    `envconfig:"K_SINK" required:"true"`
-	OutputFormat v1alpha1.OutputFormatType `envconfig:"K_OUTPUT_FORMAT" required:"true"`
-
 	// Ko deployment options
 	DataPath string `envconfig:"KO_DATA_PATH"`
 
@@ -74,12 +68,12 @@ func makeIndexHandler(dir string) http.HandlerFunc {
 	}
 }
 
-func makeImporterHandle(client cloudevents.Client) cloudeventsclient.ReceiveFull {
+func makeImporterHandle(sender sourcesdk.Sender, source string) cloudeventsclient.ReceiveFull {
 
 	return func(ctx context.Context, event cloudevents.Event, r *cloudevents.EventResponse) error {
 		log.Printf("Importing an event: %+v\n", event)
 
-		response, err := client.Send(context.Background(), event)
+		response, err := sender.Send(ctx, event)
 		if err != nil {
 			log.Printf("Failed to send event: %v\n", err)
 			return err
@@ -89,7 +83,7 @@ func makeImporterHandle(client cloudevents.Client) cloudeventsclient.ReceiveFull
 		if response == nil {
 			responseval := cloudevents.NewEvent()
 			response = &responseval
-			response.SetSource(EVENT_SOURCE)
+			response.SetSource(source)
 			response.SetType(EVENT_TYPE)
 			response.SetID(uuid.New().String())
 
@@ -104,7 +98,15 @@ func makeImporterHandle(client cloudevents.Client) cloudeventsclient.ReceiveFull
 	}
 }
 
-func convert(ctx context.Context, m transport.Message, err error) (*cloudevents.Event, error) {
+// makeConvert returns a ConvertFn that makes events of the given source
+// from form posts.
+func makeConvert(source string) cloudevents.ConvertFn {
+	return func(ctx context.Context, m transport.Message, err error) (*cloudevents.Event, error) {
+		return convert(source, m, err)
+	}
+}
+
+func convert(source string, m transport.Message, err error) (*cloudevents.Event, error) {
 	if msg, ok := m.(*cloudeventshttp.Message); ok {
 
 		vals, err := url.ParseQuery(string(msg.Body))
@@ -122,7 +124,7 @@ func convert(ctx context.Context, m transport.Message, err error) (*cloudevents.
 		data := dataslice[0]
 
 		event := cloudevents.NewEvent()
-		event.SetSource(EVENT_SOURCE)
+		event.SetSource(source)
 		event.SetType(EVENT_TYPE)
 		event.SetID(uuid.New().String())
 
@@ -145,50 +147,44 @@ func main() {
 		env.Port = "80"
 	}
 
-	svr, err := moron.NewServer(&moron.ServerConfig{
-		Port:                  env.Port,
-		CloudEventReceivePath: "/import",
-		CloudEventTargets:     []string{env.Sink},
-		ConvertFn:             convert,
-		TransportOptions: []cloudeventshttp.Option{
-			func() cloudeventshttp.Option {
-				switch env.OutputFormat {
-				case v1alpha1.OutputFormatBinary:
-					return cloudevents.WithBinaryEncoding()
-				case v1alpha1.OutputFormatStructured:
-					return cloudevents.WithStructuredEncoding()
-				default:
-					log.Fatalf("Unknown OutputFormatType: %q", env.OutputFormat)
-					return nil
-				}
-			}(),
-		},
-	})
-	if err != nil {
-		log.Fatal("Could not create server: ", err)
-	}
+	sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+		source := sourcesdk.EnvFromContext(ctx).CloudEventSource
+
+		svr, err := moron.NewServer(&moron.ServerConfig{
+			Port:                  env.Port,
+			CloudEventReceivePath: "/import",
+			ConvertFn:             makeConvert(source),
+		})
+		if err != nil {
+			return fmt.Errorf("Could not create server: %v", err)
+		}
 
-	svr.HandleCloudEvents(makeImporterHandle(svr.CloudEventClient()))
+		svr.HandleCloudEvents(makeImporterHandle(sender, source))
 
-	svr.HandleFunc("/", makeIndexHandler(env.DataPath))
+		svr.HandleFunc("/", makeIndexHandler(env.DataPath))
 
-	svr.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-	})
+		svr.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(200)
+		})
 
-	svr.HandleFunc("/versionz", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(VERSION))
-	})
+		svr.HandleFunc("/versionz", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(VERSION))
+		})
 
-	go func() {
-		log.Fatal(svr.ListenAndServe())
-	}()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- svr.ListenAndServe()
+		}()
 
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
-	<-signalChan
+		select {
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+		}
 
-	log.Println("Shutdown signal received, exiting...")
+		log.Println("Shutdown signal received, exiting...")
 
-	svr.Shutdown()
+		svr.Shutdown()
+		return nil
+	})
 }
@@ -17,11 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/n3wscott/sources/cmd/demos/salmonrun/pkg/controller"
-	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/sourcesdk"
 	moron "github.com/spencer-p/moroncloudevents"
 )
 
@@ -30,10 +32,6 @@ type Config struct {
 	Port     string `envconfig:"PORT" required:"true"`
 	DataPath string `envconfig:"KO_DATA_PATH" default:"./kodata/"`
 
-	// Sourcing options
-	Sink         string                    `envconfig:"K_SINK" required:"true"`
-	OutputFormat v1alpha1.OutputFormatType `envconfig:"K_OUTPUT_FORMAT" required:"true"`
-
 	// The role we are fulfilling: "salmon" or "bear"
 	Role string `envconfig:"SALMONRUN_ROLE" required:"true"`
 }
@@ -44,19 +42,30 @@ func main() {
 		log.Fatal("Failed to process env: ", err)
 	}
 
-	svr, err := moron.NewServer(&moron.ServerConfig{
-		Port:                  conf.Port,
-		CloudEventReceivePath: "/",
-		CloudEventTargets:     []string{conf.Sink},
-	})
-	if err != nil {
-		log.Fatal("Could not create server: ", err)
-	}
+	sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+		svr, err := moron.NewServer(&moron.ServerConfig{
+			Port:                  conf.Port,
+			CloudEventReceivePath: "/",
+		})
+		if err != nil {
+			return fmt.Errorf("Could not create server: %v", err)
+		}
 
-	err = controller.RegisterHandlers(svr, conf.Role, conf.DataPath)
-	if err != nil {
-		log.Fatal(err)
-	}
+		if err := controller.RegisterHandlers(svr, sender, conf.Role, conf.DataPath); err != nil {
+			return err
+		}
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- svr.ListenAndServe()
+		}()
 
-	log.Fatal(svr.ListenAndServe())
+		select {
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+			svr.Shutdown()
+			return nil
+		}
+	})
 }
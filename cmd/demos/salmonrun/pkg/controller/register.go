@@ -23,6 +23,8 @@ import (
 	"path"
 
 	moron "github.com/spencer-p/moroncloudevents"
+
+	"github.com/n3wscott/sources/pkg/sourcesdk"
 )
 
 func withLog(next http.Handler) http.Handler {
@@ -32,14 +34,16 @@ func withLog(next http.Handler) http.Handler {
 	})
 }
 
-func RegisterHandlers(svr *moron.Server, role, datapath string) error {
+// RegisterHandlers registers the handlers of the role with svr. Events are
+// sent with sender.
+func RegisterHandlers(svr *moron.Server, sender sourcesdk.Sender, role, datapath string) error {
 	switch role {
 	case "salmon":
 		svr.HandleCloudEvents(salmonEventReceiver)
-		svr.Handle("/websocket", withLog(makeWebSocketHandle(makeSalmonWSReceiver(svr.CloudEventClient()))))
+		svr.Handle("/websocket", withLog(makeWebSocketHandle(makeSalmonWSReceiver(sender))))
 	case "bear":
 		svr.HandleCloudEvents(bearEventReceiver)
-		svr.Handle("/websocket", withLog(makeWebSocketHandle(makeBearWSReceiver(svr.CloudEventClient()))))
+		svr.Handle("/websocket", withLog(makeWebSocketHandle(makeBearWSReceiver(sender))))
 	default:
 		return fmt.Errorf("unknown role %q", role)
 	}
@@ -17,7 +17,6 @@ limitations under the License.
 package controller
 
 const (
-	EVENT_SOURCE      = "https://github.com/n3wscott/sources/cmd/demos/salmonrun"
 	SALMON_EVENT_TYPE = "com.github.n3wscott.sources.demos.salmonrun.salmon"
 	BEAR_EVENT_TYPE   = "com.github.n3wscott.sources.demos.salmonrun.bear"
 )
@@ -25,6 +25,8 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+
+	"github.com/n3wscott/sources/pkg/sourcesdk"
 )
 
 var upgrader = websocket.Upgrader{
@@ -62,7 +64,7 @@ func makeWebSocketHandle(callback ConnectionReceiver) http.HandlerFunc {
 	}
 }
 
-func makeSalmonWSReceiver(client cloudevents.Client) ConnectionReceiver {
+func makeSalmonWSReceiver(sender sourcesdk.Sender) ConnectionReceiver {
 	return func(player Player, conn *websocket.Conn) {
 		// TODO it would be nice to have a way to cancel this
 		var msg Message
@@ -83,7 +85,6 @@ func makeSalmonWSReceiver(client cloudevents.Client) ConnectionReceiver {
 			msg.From = player
 
 			event := cloudevents.NewEvent()
-			event.SetSource(EVENT_SOURCE)
 			event.SetType(SALMON_EVENT_TYPE)
 			event.SetID(msg.Nonce)
 			if err := event.SetData(&msg); err != nil {
@@ -91,14 +92,14 @@ func makeSalmonWSReceiver(client cloudevents.Client) ConnectionReceiver {
 				continue
 			}
 
-			if _, err := client.Send(context.Background(), event); err != nil {
+			if _, err := sender.Send(context.Background(), event); err != nil {
 				log.Println("Failed to send cloud event: ", err)
 			}
 		}
 	}
 }
 
-func makeBearWSReceiver(client cloudevents.Client) ConnectionReceiver {
+func makeBearWSReceiver(sender sourcesdk.Sender) ConnectionReceiver {
 	return func(player Player, conn *websocket.Conn) {
 		timeoutchan := make(chan Message)
 		timeoutchans[player.Key()] = timeoutchan
@@ -159,7 +160,6 @@ func makeBearWSReceiver(client cloudevents.Client) ConnectionReceiver {
 			msg.From = player
 
 			event := cloudevents.NewEvent()
-			event.SetSource(EVENT_SOURCE)
 			event.SetType(BEAR_EVENT_TYPE)
 			event.SetID(msg.Nonce)
 			if err := event.SetData(&msg); err != nil {
@@ -167,7 +167,7 @@ func makeBearWSReceiver(client cloudevents.Client) ConnectionReceiver {
 				continue
 			}
 
-			if _, err := client.Send(context.Background(), event); err != nil {
+			if _, err := sender.Send(context.Background(), event); err != nil {
 				log.Println("Failed to send cloud event: ", err)
 			}
 		}
@@ -21,10 +21,12 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"knative.dev/pkg/configmap"
@@ -35,7 +37,11 @@ import (
 	"knative.dev/pkg/version"
 	"knative.dev/pkg/webhook"
 
+	apiconfig "github.com/n3wscott/sources/pkg/apis/config"
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	sourcesclientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
+	sourcesinformers "github.com/n3wscott/sources/pkg/client/informers/externalversions"
+	"github.com/n3wscott/sources/pkg/quota"
 )
 
 const (
@@ -45,6 +51,7 @@ const (
 var (
 	masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	resync     = flag.Duration("resync", 10*time.Hour, "The resync period of the informers that stores may use.")
 )
 
 type Store interface {
@@ -52,7 +59,22 @@ type Store interface {
 	ToContext(context.Context) context.Context
 }
 
-type StoreFactory func(*zap.SugaredLogger) Store
+// StoreFactory creates a Store. Informers that the Store gets from the
+// factories are started and synced before the webhook serves. The client
+// reads sources from the API server, for when the informers may lag.
+type StoreFactory func(*zap.SugaredLogger, kubeinformers.SharedInformerFactory, sourcesinformers.SharedInformerFactory, sourcesclientset.Interface) Store
+
+// quotaStore attaches a QuotaUsage to the context, which makes the Validate
+// of sources enforce the SourceQuotas of their namespace.
+type quotaStore struct {
+	usage v1alpha1.QuotaUsage
+}
+
+func (quotaStore) WatchConfigs(configmap.Watcher) {}
+
+func (s quotaStore) ToContext(ctx context.Context) context.Context {
+	return v1alpha1.WithQuotaUsage(ctx, s.usage)
+}
 
 func SharedMain(handlers map[schema.GroupVersionKind]webhook.GenericCRD, factories ...StoreFactory) {
 	flag.Parse()
@@ -83,6 +105,11 @@ func SharedMain(handlers map[schema.GroupVersionKind]webhook.GenericCRD, factori
 		logger.Fatalw("Failed to get the client set", zap.Error(err))
 	}
 
+	sourcesClient, err := sourcesclientset.NewForConfig(clusterConfig)
+	if err != nil {
+		logger.Fatalw("Failed to get the sources client set", zap.Error(err))
+	}
+
 	if err := version.CheckMinimumVersion(kubeClient.Discovery()); err != nil {
 		logger.Fatalw("Version check failed", err)
 	}
@@ -94,9 +121,11 @@ func SharedMain(handlers map[schema.GroupVersionKind]webhook.GenericCRD, factori
 	// If you want to control Defaulting or Validation, you can attach config state
 	// to the context by watching the configmap here, and then uncommenting the logic
 	// below.
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, *resync)
+	sourcesInformerFactory := sourcesinformers.NewSharedInformerFactory(sourcesClient, *resync)
 	stores := make([]Store, 0, len(factories))
 	for _, sf := range factories {
-		store := sf(logger)
+		store := sf(logger, kubeInformerFactory, sourcesInformerFactory, sourcesClient)
 		store.WatchConfigs(configMapWatcher)
 		stores = append(stores, store)
 	}
@@ -105,6 +134,19 @@ func SharedMain(handlers map[schema.GroupVersionKind]webhook.GenericCRD, factori
 		logger.Fatalw("Failed to start the ConfigMap watcher", zap.Error(err))
 	}
 
+	kubeInformerFactory.Start(ctx.Done())
+	for informerType, synced := range kubeInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			logger.Fatalf("Failed to sync the informer of %v", informerType)
+		}
+	}
+	sourcesInformerFactory.Start(ctx.Done())
+	for informerType, synced := range sourcesInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			logger.Fatalf("Failed to sync the informer of %v", informerType)
+		}
+	}
+
 	options := webhook.ControllerOptions{
 		ServiceName:                 "webhook",
 		DeploymentName:              "webhook",
@@ -143,18 +185,32 @@ func main() {
 		v1alpha1.SchemeGroupVersion.WithKind("CronJobSource"): &v1alpha1.CronJobSource{},
 		v1alpha1.SchemeGroupVersion.WithKind("ServiceSource"): &v1alpha1.ServiceSource{},
 
+		v1alpha1.SchemeGroupVersion.WithKind("ClusterSourceTemplate"): &v1alpha1.ClusterSourceTemplate{},
+		v1alpha1.SchemeGroupVersion.WithKind("SourceInstance"):        &v1alpha1.SourceInstance{},
+		v1alpha1.SchemeGroupVersion.WithKind("SourceQuota"):           &v1alpha1.SourceQuota{},
+
 		// Bind an alias of the Pod type to corev1.Pod for sidecar injection (via SetDefaults).
 		// The Knative webhook will subscribe to Pods and all subresources, which includes Bindings.
 		// We have to register a nop handler for Bindings so that all Pods don't get rejected.
 		corev1.SchemeGroupVersion.WithKind("Pod"):     &v1alpha1.SourcePod{},
 		corev1.SchemeGroupVersion.WithKind("Binding"): &v1alpha1.NOPBinding{},
 	}
-	SharedMain(handlers)
-
-	// To setup a config "Store" to track a set of configurations and persist itself to
-	// the context passed to webhook invocations, you would pass something like this to
-	// SharedMain as well:
-	// func(logger *zap.SugaredLogger) Store {
-	// 	return apiconfig.NewStore(logger.Named("config-store"))
-	// }
+
+	// The defaults of config-sources, which namespaces override by label,
+	// are attached to the context of SetDefaults. The SourceQuotas of
+	// namespaces, and what their sources use, are attached to the context
+	// of Validate.
+	SharedMain(handlers, func(logger *zap.SugaredLogger, informers kubeinformers.SharedInformerFactory, _ sourcesinformers.SharedInformerFactory, _ sourcesclientset.Interface) Store {
+		return apiconfig.NewStore(logger.Named("config-store"), informers.Core().V1().Namespaces().Lister())
+	}, func(_ *zap.SugaredLogger, _ kubeinformers.SharedInformerFactory, informers sourcesinformers.SharedInformerFactory, client sourcesclientset.Interface) Store {
+		sources := informers.Sources().V1alpha1()
+		return quotaStore{usage: &quota.Usage{
+			QuotaLister:          sources.SourceQuotas().Lister(),
+			JobSourceLister:      sources.JobSources().Lister(),
+			CronJobSourceLister:  sources.CronJobSources().Lister(),
+			ServiceSourceLister:  sources.ServiceSources().Lister(),
+			SourceInstanceLister: sources.SourceInstances().Lister(),
+			Client:               client,
+		}}
+	})
 }
@@ -0,0 +1,359 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultsConfigName is the name of the ConfigMap with the defaults of
+	// sources.
+	DefaultsConfigName = "config-sources"
+
+	// NamespaceLabelPrefix prefixes the labels of namespaces that override
+	// the keys of the config-sources ConfigMap for the sources in them, as
+	// in defaults.sources.knative.dev/output-format: structured.
+	NamespaceLabelPrefix = "defaults.sources.knative.dev/"
+
+	// CrossNamespaceSinksLabel is the label of namespaces that allows or
+	// denies sources in other namespaces to send to the sinks in them,
+	// overriding the cross-namespace-sinks key of config-sources.
+	CrossNamespaceSinksLabel = "sources.knative.dev/cross-namespace-sinks"
+
+	outputFormatKey  = "output-format"
+	specVersionKey   = "cloudevents-spec-version"
+	backoffLimitKey  = "backoff-limit"
+	restartPolicyKey = "restart-policy"
+	cpuRequestKey    = "container-cpu-request"
+	memoryRequestKey = "container-memory-request"
+	cpuLimitKey      = "container-cpu-limit"
+	memoryLimitKey   = "container-memory-limit"
+	sinkBrokerKey    = "default-sink-broker"
+
+	crossNamespaceSinksKey = "cross-namespace-sinks"
+	sinkProbeKey           = "sink-probe"
+	sinkProbeIntervalKey   = "sink-probe-interval"
+	statusWritesKey        = "status-writes"
+
+	// DefaultOutputFormat is the output format of sources unless configured
+	// otherwise.
+	DefaultOutputFormat = "binary"
+
+	// DefaultCloudEventsSpecVersion is the version of the CloudEvents spec
+	// of the events the controller sends unless configured otherwise.
+	DefaultCloudEventsSpecVersion = "0.2"
+
+	// DefaultBackoffLimit is the backoff limit of JobSources unless
+	// configured otherwise.
+	DefaultBackoffLimit = 6
+
+	// DefaultSinkProbeInterval is how long the result of a probe of a sink
+	// is used unless configured otherwise.
+	DefaultSinkProbeInterval = 5 * time.Minute
+)
+
+// SinkPolicy is whether sources may send to sinks in other namespaces.
+type SinkPolicy string
+
+const (
+	// SinkPolicyAllow lets sources send to sinks in other namespaces.
+	SinkPolicyAllow SinkPolicy = "allow"
+
+	// SinkPolicyDeny keeps sources from sending to sinks in other
+	// namespaces.
+	SinkPolicyDeny SinkPolicy = "deny"
+)
+
+// SinkProbe is how the controller probes the sinks of sources.
+type SinkProbe string
+
+const (
+	// SinkProbeNone doesn't probe sinks.
+	SinkProbeNone SinkProbe = "none"
+
+	// SinkProbeOptions sends an OPTIONS request to sinks.
+	SinkProbeOptions SinkProbe = "options"
+
+	// SinkProbeHead sends a HEAD request to sinks.
+	SinkProbeHead SinkProbe = "head"
+
+	// SinkProbeCloudEvent sends a probe CloudEvent to sinks, which has the
+	// probe extension that sinks can ignore it by.
+	SinkProbeCloudEvent SinkProbe = "cloudevent"
+)
+
+// StatusWrites is how the controller writes the status of sources.
+type StatusWrites string
+
+const (
+	// StatusWritesUpdate updates the status subresource, and retries
+	// updates that conflict with other writes.
+	StatusWritesUpdate StatusWrites = "update"
+
+	// StatusWritesPatch merge-patches the status subresource, which doesn't
+	// conflict with writes to the rest of the source.
+	StatusWritesPatch StatusWrites = "patch"
+)
+
+var (
+	sinkProbes    = []string{string(SinkProbeNone), string(SinkProbeOptions), string(SinkProbeHead), string(SinkProbeCloudEvent)}
+	outputFormats = []string{"binary", "structured"}
+	specVersions  = []string{"0.1", "0.2", "0.3"}
+)
+
+// Defaults are the defaults that the webhook and the controller fill in for
+// sources.
+type Defaults struct {
+	// OutputFormat is the spec.outputFormat of sources that don't set it.
+	OutputFormat string
+
+	// CloudEventsSpecVersion is the version of the CloudEvents spec of the
+	// events that the controller sends itself, such as those of inline
+	// CronJobSources.
+	CloudEventsSpecVersion string
+
+	// BackoffLimit is the spec.backoffLimit of JobSources that don't set it.
+	BackoffLimit int32
+
+	// RestartPolicy is the restart policy of the pods of JobSources and
+	// CronJobSources that don't set it. If empty, it is derived from the
+	// backoff limit of JobSources and is Never for CronJobSources.
+	RestartPolicy corev1.RestartPolicy
+
+	// Resources are the resource requests and limits of the containers of
+	// sources that don't set them.
+	Resources corev1.ResourceRequirements
+
+	// SinkBroker, if set, is the name of the Broker in their namespace that
+	// sources without a sink send their events to.
+	SinkBroker string
+
+	// CrossNamespaceSinks is whether sources may send to sinks in other
+	// namespaces that don't have the CrossNamespaceSinksLabel. Unlike the
+	// other fields, the labels of the namespace of a source don't override
+	// it.
+	CrossNamespaceSinks SinkPolicy
+
+	// SinkProbe is how the controller probes the sink URIs of sources. Like
+	// CrossNamespaceSinks, it can't be overridden by namespace.
+	SinkProbe SinkProbe
+
+	// SinkProbeInterval is how long the result of a probe of a sink URI is
+	// used before the sink is probed again.
+	SinkProbeInterval time.Duration
+
+	// StatusWrites is how the controller writes the status of sources. Like
+	// CrossNamespaceSinks, it can't be overridden by namespace.
+	StatusWrites StatusWrites
+}
+
+// NewDefaultsFromMap creates Defaults from the data of the config-sources
+// ConfigMap.
+func NewDefaultsFromMap(data map[string]string) (*Defaults, error) {
+	d := &Defaults{
+		OutputFormat:           DefaultOutputFormat,
+		CloudEventsSpecVersion: DefaultCloudEventsSpecVersion,
+		BackoffLimit:           DefaultBackoffLimit,
+		CrossNamespaceSinks:    SinkPolicyAllow,
+		SinkProbe:              SinkProbeNone,
+		SinkProbeInterval:      DefaultSinkProbeInterval,
+		StatusWrites:           StatusWritesUpdate,
+	}
+	if err := d.parse(data); err != nil {
+		return nil, err
+	}
+	if v, ok := data[crossNamespaceSinksKey]; ok {
+		switch policy := SinkPolicy(v); policy {
+		case SinkPolicyAllow, SinkPolicyDeny:
+			d.CrossNamespaceSinks = policy
+		default:
+			return nil, fmt.Errorf("%s must be %s or %s, got %q", crossNamespaceSinksKey, SinkPolicyAllow, SinkPolicyDeny, v)
+		}
+	}
+	if v, ok := data[sinkProbeKey]; ok {
+		if !oneOf(v, sinkProbes) {
+			return nil, fmt.Errorf("%s must be one of %v, got %q", sinkProbeKey, sinkProbes, v)
+		}
+		d.SinkProbe = SinkProbe(v)
+	}
+	if v, ok := data[sinkProbeIntervalKey]; ok {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("%s must be a duration of at least 1s, got %q", sinkProbeIntervalKey, v)
+		}
+		d.SinkProbeInterval = interval
+	}
+	if v, ok := data[statusWritesKey]; ok {
+		switch writes := StatusWrites(v); writes {
+		case StatusWritesUpdate, StatusWritesPatch:
+			d.StatusWrites = writes
+		default:
+			return nil, fmt.Errorf("%s must be %s or %s, got %q", statusWritesKey, StatusWritesUpdate, StatusWritesPatch, v)
+		}
+	}
+	return d, nil
+}
+
+// NewDefaultsFromConfigMap creates Defaults from the config-sources
+// ConfigMap.
+func NewDefaultsFromConfigMap(config *corev1.ConfigMap) (*Defaults, error) {
+	return NewDefaultsFromMap(config.Data)
+}
+
+// WithOverrides returns a copy of d with the keys that the given labels of
+// a namespace override.
+func (d *Defaults) WithOverrides(labels map[string]string) (*Defaults, error) {
+	data := make(map[string]string)
+	for k, v := range labels {
+		if strings.HasPrefix(k, NamespaceLabelPrefix) {
+			data[strings.TrimPrefix(k, NamespaceLabelPrefix)] = v
+		}
+	}
+	out := d.DeepCopy()
+	if len(data) == 0 {
+		return out, nil
+	}
+	if err := out.parse(data); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApplyResources sets the resource requests and limits of the container
+// that it doesn't set itself, the way a LimitRange does: a request that the
+// container doesn't set defaults to the limit it sets for the resource, if
+// any, and no limit defaults to less than the request for the resource.
+func (d *Defaults) ApplyResources(c *corev1.Container) {
+	res := &c.Resources
+	for name, q := range d.Resources.Requests {
+		if _, ok := res.Requests[name]; ok {
+			continue
+		}
+		if limit, ok := res.Limits[name]; ok {
+			q = limit
+		}
+		if res.Requests == nil {
+			res.Requests = make(corev1.ResourceList)
+		}
+		res.Requests[name] = q.DeepCopy()
+	}
+	for name, q := range d.Resources.Limits {
+		if _, ok := res.Limits[name]; ok {
+			continue
+		}
+		if request, ok := res.Requests[name]; ok && request.Cmp(q) > 0 {
+			// The pod would be invalid.
+			continue
+		}
+		if res.Limits == nil {
+			res.Limits = make(corev1.ResourceList)
+		}
+		res.Limits[name] = q.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of d.
+func (d *Defaults) DeepCopy() *Defaults {
+	out := *d
+	d.Resources.DeepCopyInto(&out.Resources)
+	return &out
+}
+
+// parse sets the fields of d for the keys in data.
+func (d *Defaults) parse(data map[string]string) error {
+	if v, ok := data[outputFormatKey]; ok {
+		if !oneOf(v, outputFormats) {
+			return fmt.Errorf("%s must be one of %v, got %q", outputFormatKey, outputFormats, v)
+		}
+		d.OutputFormat = v
+	}
+
+	if v, ok := data[specVersionKey]; ok {
+		if !oneOf(v, specVersions) {
+			return fmt.Errorf("%s must be one of %v, got %q", specVersionKey, specVersions, v)
+		}
+		d.CloudEventsSpecVersion = v
+	}
+
+	if v, ok := data[backoffLimitKey]; ok {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("%s must be a non-negative integer, got %q", backoffLimitKey, v)
+		}
+		d.BackoffLimit = int32(limit)
+	}
+
+	if v, ok := data[restartPolicyKey]; ok {
+		switch policy := corev1.RestartPolicy(v); policy {
+		case corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure:
+			d.RestartPolicy = policy
+		default:
+			return fmt.Errorf("%s must be %s or %s, got %q", restartPolicyKey, corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure, v)
+		}
+	}
+
+	if v, ok := data[sinkBrokerKey]; ok {
+		if v != "" {
+			if errs := validation.IsDNS1123Subdomain(v); len(errs) > 0 {
+				return fmt.Errorf("%s must be the name of a Broker, got %q: %s", sinkBrokerKey, v, strings.Join(errs, "; "))
+			}
+		}
+		d.SinkBroker = v
+	}
+
+	for _, r := range []struct {
+		key  string
+		list *corev1.ResourceList
+		name corev1.ResourceName
+	}{
+		{cpuRequestKey, &d.Resources.Requests, corev1.ResourceCPU},
+		{memoryRequestKey, &d.Resources.Requests, corev1.ResourceMemory},
+		{cpuLimitKey, &d.Resources.Limits, corev1.ResourceCPU},
+		{memoryLimitKey, &d.Resources.Limits, corev1.ResourceMemory},
+	} {
+		v, ok := data[r.key]
+		if !ok {
+			continue
+		}
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return fmt.Errorf("%s must be a quantity, got %q: %v", r.key, v, err)
+		}
+		if *r.list == nil {
+			*r.list = make(corev1.ResourceList)
+		}
+		(*r.list)[r.name] = q
+	}
+	return nil
+}
+
+func oneOf(v string, values []string) bool {
+	for _, value := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
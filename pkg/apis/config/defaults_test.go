@@ -0,0 +1,288 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	. "knative.dev/pkg/configmap/testing"
+)
+
+// quantityComparer compares resource.Quantities by value, since cmp can't
+// look into their unexported fields.
+var quantityComparer = cmp.Comparer(func(a, b resource.Quantity) bool {
+	return a.Cmp(b) == 0
+})
+
+func TestDefaultsConfigurationFromFile(t *testing.T) {
+	cm, example := ConfigMapsFromTestFile(t, DefaultsConfigName)
+
+	if _, err := NewDefaultsFromConfigMap(cm); err != nil {
+		t.Errorf("NewDefaultsFromConfigMap(actual) = %v", err)
+	}
+	if _, err := NewDefaultsFromConfigMap(example); err != nil {
+		t.Errorf("NewDefaultsFromConfigMap(example) = %v", err)
+	}
+}
+
+func TestNewDefaultsFromMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    *Defaults
+		wantErr bool
+	}{{
+		name: "empty",
+		want: &Defaults{
+			OutputFormat:           DefaultOutputFormat,
+			CloudEventsSpecVersion: DefaultCloudEventsSpecVersion,
+			BackoffLimit:           DefaultBackoffLimit,
+			CrossNamespaceSinks:    SinkPolicyAllow,
+			SinkProbe:              SinkProbeNone,
+			SinkProbeInterval:      DefaultSinkProbeInterval,
+			StatusWrites:           StatusWritesUpdate,
+		},
+	}, {
+		name: "everything",
+		data: map[string]string{
+			"output-format":            "structured",
+			"cloudevents-spec-version": "0.3",
+			"backoff-limit":            "0",
+			"restart-policy":           "OnFailure",
+			"container-cpu-request":    "100m",
+			"container-memory-limit":   "256Mi",
+			"default-sink-broker":      "default",
+			"cross-namespace-sinks":    "deny",
+			"sink-probe":               "head",
+			"sink-probe-interval":      "1m",
+			"status-writes":            "patch",
+		},
+		want: &Defaults{
+			OutputFormat:           "structured",
+			CloudEventsSpecVersion: "0.3",
+			BackoffLimit:           0,
+			RestartPolicy:          corev1.RestartPolicyOnFailure,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+			SinkBroker:          "default",
+			CrossNamespaceSinks: SinkPolicyDeny,
+			SinkProbe:           SinkProbeHead,
+			SinkProbeInterval:   time.Minute,
+			StatusWrites:        StatusWritesPatch,
+		},
+	}, {
+		name:    "invalid output format",
+		data:    map[string]string{"output-format": "messenger_pigeon"},
+		wantErr: true,
+	}, {
+		name:    "invalid spec version",
+		data:    map[string]string{"cloudevents-spec-version": "42"},
+		wantErr: true,
+	}, {
+		name:    "negative backoff limit",
+		data:    map[string]string{"backoff-limit": "-1"},
+		wantErr: true,
+	}, {
+		name:    "restart always",
+		data:    map[string]string{"restart-policy": "Always"},
+		wantErr: true,
+	}, {
+		name:    "invalid sink broker",
+		data:    map[string]string{"default-sink-broker": "Not_A_Name"},
+		wantErr: true,
+	}, {
+		name:    "invalid cross-namespace sink policy",
+		data:    map[string]string{"cross-namespace-sinks": "sometimes"},
+		wantErr: true,
+	}, {
+		name:    "invalid sink probe",
+		data:    map[string]string{"sink-probe": "ping"},
+		wantErr: true,
+	}, {
+		name:    "sink probe interval too short",
+		data:    map[string]string{"sink-probe-interval": "10ms"},
+		wantErr: true,
+	}, {
+		name:    "invalid status writes",
+		data:    map[string]string{"status-writes": "apply"},
+		wantErr: true,
+	}, {
+		name:    "invalid quantity",
+		data:    map[string]string{"container-cpu-limit": "lots"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewDefaultsFromMap(test.data)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NewDefaultsFromMap() = %v, wantErr %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got, quantityComparer); diff != "" {
+				t.Errorf("NewDefaultsFromMap() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultsWithOverrides(t *testing.T) {
+	d, err := NewDefaultsFromMap(map[string]string{
+		"output-format":         "binary",
+		"container-cpu-request": "100m",
+	})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+
+	got, err := d.WithOverrides(map[string]string{
+		"defaults.sources.knative.dev/output-format":         "structured",
+		"defaults.sources.knative.dev/container-cpu-request": "250m",
+		"defaults.sources.knative.dev/default-sink-broker":   "default",
+		"output-format": "ignored",
+	})
+	if err != nil {
+		t.Fatalf("WithOverrides() = %v", err)
+	}
+	want := &Defaults{
+		OutputFormat:           "structured",
+		CloudEventsSpecVersion: DefaultCloudEventsSpecVersion,
+		BackoffLimit:           DefaultBackoffLimit,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+		},
+		SinkBroker:          "default",
+		CrossNamespaceSinks: SinkPolicyAllow,
+		SinkProbe:           SinkProbeNone,
+		SinkProbeInterval:   DefaultSinkProbeInterval,
+		StatusWrites:        StatusWritesUpdate,
+	}
+	if diff := cmp.Diff(want, got, quantityComparer); diff != "" {
+		t.Errorf("WithOverrides() (-want, +got) = %s", diff)
+	}
+	if got, want := d.Resources.Requests[corev1.ResourceCPU], resource.MustParse("100m"); got.Cmp(want) != 0 {
+		t.Errorf("WithOverrides() changed the cluster cpu request to %v", got.String())
+	}
+
+	if _, err := d.WithOverrides(map[string]string{"defaults.sources.knative.dev/backoff-limit": "many"}); err == nil {
+		t.Error("WithOverrides() = nil, wanted an error for an invalid backoff limit")
+	}
+}
+
+func TestDefaultsApplyResources(t *testing.T) {
+	d, err := NewDefaultsFromMap(map[string]string{
+		"container-cpu-request":    "100m",
+		"container-memory-request": "64Mi",
+		"container-cpu-limit":      "1",
+	})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		set  corev1.ResourceRequirements
+		want corev1.ResourceRequirements
+	}{{
+		name: "nothing set",
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	}, {
+		name: "request set",
+		set: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	}, {
+		name: "request above the default limit set",
+		set: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	}, {
+		name: "limit below the default request set",
+		set: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+		},
+	}, {
+		name: "limit above the default request set",
+		set: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+	}, {
+		name: "limit of a resource without a default set",
+		set: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+		},
+		want: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("1"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+			},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &corev1.Container{Resources: test.set}
+			d.ApplyResources(c)
+			if diff := cmp.Diff(test.want, c.Resources, quantityComparer); diff != "" {
+				t.Errorf("ApplyResources() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
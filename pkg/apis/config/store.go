@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/configmap"
+)
+
+type cfgKey struct{}
+
+// Config holds the collection of configurations that we attach to contexts.
+type Config struct {
+	Defaults *Defaults
+
+	// Namespaces, if set, looks up the labels with which namespaces
+	// override the Defaults.
+	Namespaces corev1listers.NamespaceLister
+}
+
+// ForNamespace returns the Defaults of the sources in the given namespace.
+// If the namespace can't be found, or its labels don't parse, they are the
+// Defaults of the cluster.
+func (c *Config) ForNamespace(namespace string) *Defaults {
+	if c.Namespaces == nil || namespace == "" {
+		return c.Defaults
+	}
+	ns, err := c.Namespaces.Get(namespace)
+	if err != nil {
+		return c.Defaults
+	}
+	d, err := c.Defaults.WithOverrides(ns.Labels)
+	if err != nil {
+		return c.Defaults
+	}
+	return d
+}
+
+// SinkPermitted reports whether sources in sourceNamespace may send to sinks
+// in sinkNamespace. Sources may always send to sinks in their own
+// namespace. Otherwise the CrossNamespaceSinksLabel of sinkNamespace
+// decides, or the Defaults if it has none.
+func (c *Config) SinkPermitted(sourceNamespace, sinkNamespace string) bool {
+	if sinkNamespace == "" || sinkNamespace == sourceNamespace {
+		return true
+	}
+	policy := c.Defaults.CrossNamespaceSinks
+	if c.Namespaces != nil {
+		if ns, err := c.Namespaces.Get(sinkNamespace); err == nil {
+			switch label := SinkPolicy(ns.Labels[CrossNamespaceSinksLabel]); label {
+			case SinkPolicyAllow, SinkPolicyDeny:
+				policy = label
+			}
+		}
+	}
+	return policy != SinkPolicyDeny
+}
+
+// ServiceNamespace returns the namespace of the Service that uri addresses
+// by its cluster-local host name, <name>.<namespace>.svc or
+// <name>.<namespace>.svc.cluster.local, or "" if it doesn't. Other ways to
+// address a Service, such as <name>.<namespace>, a custom cluster domain or
+// its IP, are not recognized.
+func ServiceNamespace(uri *apis.URL) string {
+	if uri == nil {
+		return ""
+	}
+	host := strings.TrimSuffix(strings.ToLower((*url.URL)(uri).Hostname()), ".")
+	host = strings.TrimSuffix(host, ".cluster.local")
+	labels := strings.Split(host, ".")
+	if len(labels) != 3 || labels[2] != "svc" || labels[0] == "" {
+		return ""
+	}
+	return labels[1]
+}
+
+// FromContext fetches the Config from the context, or nil if it has none.
+func FromContext(ctx context.Context) *Config {
+	x, ok := ctx.Value(cfgKey{}).(*Config)
+	if ok {
+		return x
+	}
+	return nil
+}
+
+// FromContextOrDefaults is like FromContext, but returns a Config with the
+// built-in Defaults if the context has none.
+func FromContextOrDefaults(ctx context.Context) *Config {
+	if cfg := FromContext(ctx); cfg != nil {
+		return cfg
+	}
+	defaults, _ := NewDefaultsFromMap(nil)
+	return &Config{Defaults: defaults}
+}
+
+// ToContext attaches the Config to the context.
+func ToContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, cfgKey{}, c)
+}
+
+// Store is a typed wrapper around configmap.UntypedStore to handle our
+// ConfigMaps.
+type Store struct {
+	*configmap.UntypedStore
+
+	namespaces corev1listers.NamespaceLister
+}
+
+// NewStore creates a new store of Configs whose namespaces, if not nil,
+// override the Defaults by label. It calls the optional onAfterStore
+// functions after a ConfigMap is stored.
+func NewStore(logger configmap.Logger, namespaces corev1listers.NamespaceLister, onAfterStore ...func(name string, value interface{})) *Store {
+	return &Store{
+		UntypedStore: configmap.NewUntypedStore(
+			"sources",
+			logger,
+			configmap.Constructors{
+				DefaultsConfigName: NewDefaultsFromConfigMap,
+			},
+			onAfterStore...,
+		),
+		namespaces: namespaces,
+	}
+}
+
+// ToContext attaches the current Config to the context.
+func (s *Store) ToContext(ctx context.Context) context.Context {
+	return ToContext(ctx, s.Load())
+}
+
+// Load returns a copy of the current Config.
+func (s *Store) Load() *Config {
+	return &Config{
+		Defaults:   s.UntypedLoad(DefaultsConfigName).(*Defaults).DeepCopy(),
+		Namespaces: s.namespaces,
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	. "knative.dev/pkg/configmap/testing"
+)
+
+func TestStoreLoadWithContext(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t), nil)
+
+	defaultsConfig := ConfigMapFromTestFile(t, DefaultsConfigName)
+	store.OnConfigChanged(defaultsConfig)
+
+	config := FromContext(store.ToContext(context.Background()))
+
+	expected, _ := NewDefaultsFromConfigMap(defaultsConfig)
+	if diff := cmp.Diff(expected, config.Defaults, quantityComparer); diff != "" {
+		t.Errorf("Defaults (-want, +got) = %s", diff)
+	}
+}
+
+func TestStoreImmutableConfig(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t), nil)
+	store.OnConfigChanged(ConfigMapFromTestFile(t, DefaultsConfigName))
+
+	config := store.Load()
+	config.Defaults.OutputFormat = "mutated"
+
+	if got := store.Load().Defaults.OutputFormat; got == "mutated" {
+		t.Error("Defaults of the store are mutable")
+	}
+}
+
+func TestFromContextOrDefaults(t *testing.T) {
+	config := FromContextOrDefaults(context.Background())
+	if got, want := config.Defaults.OutputFormat, DefaultOutputFormat; got != want {
+		t.Errorf("OutputFormat = %q, want %q", got, want)
+	}
+}
+
+func TestConfigForNamespace(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*corev1.Namespace{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "structured",
+			Labels: map[string]string{NamespaceLabelPrefix + "output-format": "structured"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "invalid",
+			Labels: map[string]string{NamespaceLabelPrefix + "output-format": "messenger_pigeon"},
+		},
+	}} {
+		indexer.Add(ns)
+	}
+
+	store := NewStore(logtesting.TestLogger(t), corev1listers.NewNamespaceLister(indexer))
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultsConfigName},
+		Data:       map[string]string{"output-format": "binary"},
+	})
+	config := store.Load()
+
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"structured", "structured"},
+		{"invalid", "binary"},
+		{"missing", "binary"},
+		{"", "binary"},
+	}
+	for _, test := range tests {
+		if got := config.ForNamespace(test.namespace).OutputFormat; got != test.want {
+			t.Errorf("ForNamespace(%q).OutputFormat = %q, want %q", test.namespace, got, test.want)
+		}
+	}
+}
+
+func TestConfigSinkPermitted(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []*corev1.Namespace{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "open",
+			Labels: map[string]string{CrossNamespaceSinksLabel: "allow"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "closed",
+			Labels: map[string]string{CrossNamespaceSinksLabel: "deny"},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"},
+	}} {
+		indexer.Add(ns)
+	}
+	namespaces := corev1listers.NewNamespaceLister(indexer)
+
+	tests := []struct {
+		policy string
+		sink   string
+		want   bool
+	}{
+		{"allow", "", true},
+		{"allow", "tenant", true},
+		{"allow", "unlabeled", true},
+		{"allow", "closed", false},
+		{"deny", "tenant", true},
+		{"deny", "unlabeled", false},
+		{"deny", "missing", false},
+		{"deny", "open", true},
+	}
+	for _, test := range tests {
+		defaults, err := NewDefaultsFromMap(map[string]string{"cross-namespace-sinks": test.policy})
+		if err != nil {
+			t.Fatalf("NewDefaultsFromMap() = %v", err)
+		}
+		config := &Config{Defaults: defaults, Namespaces: namespaces}
+		if got := config.SinkPermitted("tenant", test.sink); got != test.want {
+			t.Errorf("%s: SinkPermitted(tenant, %q) = %v, want %v", test.policy, test.sink, got, test.want)
+		}
+	}
+}
+
+func TestServiceNamespace(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"sink.other.svc", "other"},
+		{"sink.other.svc.cluster.local", "other"},
+		{"sink.other.svc.cluster.local:8080", "other"},
+		{"Sink.Other.SVC.cluster.local.", "other"},
+		{"sink.other", ""},
+		{"sink.other.svc.example.com", ""},
+		{"example.com", ""},
+		{"10.0.0.1", ""},
+	}
+	for _, test := range tests {
+		uri := &apis.URL{Scheme: "http", Host: test.host}
+		if got := ServiceNamespace(uri); got != test.want {
+			t.Errorf("ServiceNamespace(%q) = %q, want %q", test.host, got, test.want)
+		}
+	}
+	if got := ServiceNamespace(nil); got != "" {
+		t.Errorf("ServiceNamespace(nil) = %q, want none", got)
+	}
+}
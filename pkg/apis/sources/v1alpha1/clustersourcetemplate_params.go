@@ -0,0 +1,205 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// parameterRef matches the references to parameters in pod templates.
+	parameterRef = regexp.MustCompile(`\$\(params\.([^)]*)\)`)
+
+	// parameterName matches the names that parameters may have.
+	parameterName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+)
+
+// ResolveParameters returns the value of every parameter of the template,
+// given the values that a SourceInstance supplies. Parameters that are
+// neither supplied nor defaulted are empty.
+func (s *ClusterSourceTemplateSpec) ResolveParameters(values map[string]string) (map[string]string, error) {
+	var problems []string
+	resolved := make(map[string]string, len(s.Parameters))
+	for _, p := range s.Parameters {
+		value, ok := values[p.Name]
+		switch {
+		case ok:
+		case p.Default != nil:
+			value = *p.Default
+		case p.Required:
+			problems = append(problems, fmt.Sprintf("missing required parameter %q", p.Name))
+			continue
+		default:
+			resolved[p.Name] = ""
+			continue
+		}
+		if err := p.Check(value); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid value %q for parameter %q: %v", value, p.Name, err))
+			continue
+		}
+		resolved[p.Name] = value
+	}
+
+	var unknown []string
+	for name := range values {
+		if !s.declares(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, fmt.Sprintf("unknown parameter %q", name))
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return resolved, nil
+}
+
+// Check returns an error if value is not of the type of the parameter or
+// doesn't match its pattern.
+func (p *SourceTemplateParameter) Check(value string) error {
+	switch p.Type {
+	case SourceTemplateParameterTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("not an integer")
+		}
+	case SourceTemplateParameterTypeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("not a boolean")
+		}
+	}
+	if p.Pattern != "" {
+		pattern, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return err
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("does not match %s", p.Pattern)
+		}
+	}
+	return nil
+}
+
+// Render returns the pod template with every reference to a parameter
+// replaced with its value. values must hold every parameter that the
+// template refers to.
+func (s *ClusterSourceTemplateSpec) Render(values map[string]string) (*corev1.PodTemplateSpec, error) {
+	tree, err := templateTree(&s.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	tree = mapStrings(tree, func(str string) string {
+		return parameterRef.ReplaceAllStringFunc(str, func(ref string) string {
+			name := parameterRef.FindStringSubmatch(ref)[1]
+			value, ok := values[name]
+			if !ok {
+				missing = append(missing, name)
+				return ref
+			}
+			return value
+		})
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no value for parameters %s", strings.Join(missing, ", "))
+	}
+
+	b, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	template := &corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(b, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// References returns the names of the parameters that the pod template
+// refers to, in order.
+func (s *ClusterSourceTemplateSpec) References() []string {
+	tree, err := templateTree(&s.Template)
+	if err != nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	mapStrings(tree, func(str string) string {
+		for _, match := range parameterRef.FindAllStringSubmatch(str, -1) {
+			seen[match[1]] = true
+		}
+		return str
+	})
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *ClusterSourceTemplateSpec) declares(name string) bool {
+	for _, p := range s.Parameters {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// templateTree returns the JSON of a pod template as maps, slices and
+// values. Parameters can only be referred to in the string values of the
+// template, not its keys.
+func templateTree(template *corev1.PodTemplateSpec) (interface{}, error) {
+	b, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// mapStrings replaces every string value in tree with what f makes of it.
+func mapStrings(tree interface{}, f func(string) string) interface{} {
+	switch v := tree.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			v[key] = mapStrings(value, f)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = mapStrings(value, f)
+		}
+	case string:
+		return f(v)
+	}
+	return tree
+}
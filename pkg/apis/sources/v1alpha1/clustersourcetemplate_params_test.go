@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestResolveParameters(t *testing.T) {
+	spec := &ClusterSourceTemplateSpec{
+		Parameters: []SourceTemplateParameter{
+			{Name: "topic", Required: true, Pattern: "^[a-z]+$"},
+			{Name: "partitions", Type: SourceTemplateParameterTypeInteger, Default: ptr.String("1")},
+			{Name: "debug", Type: SourceTemplateParameterTypeBoolean},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]string
+		wantErr string
+	}{{
+		name:   "defaults",
+		values: map[string]string{"topic": "orders"},
+		want:   map[string]string{"topic": "orders", "partitions": "1", "debug": ""},
+	}, {
+		name:   "all supplied",
+		values: map[string]string{"topic": "orders", "partitions": "3", "debug": "true"},
+		want:   map[string]string{"topic": "orders", "partitions": "3", "debug": "true"},
+	}, {
+		name:    "missing required",
+		values:  map[string]string{},
+		wantErr: `missing required parameter "topic"`,
+	}, {
+		name:    "wrong types and unknown parameters",
+		values:  map[string]string{"topic": "Orders", "partitions": "three", "debug": "yes", "region": "eu", "zone": "a"},
+		wantErr: `invalid value "Orders" for parameter "topic": does not match ^[a-z]+$; invalid value "three" for parameter "partitions": not an integer; invalid value "yes" for parameter "debug": not a boolean; unknown parameter "region"; unknown parameter "zone"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := spec.ResolveParameters(test.values)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("ResolveParameters() = %v, wanted error %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveParameters() = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ResolveParameters() = %v, wanted %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	spec := &ClusterSourceTemplateSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Image: "example.com/$(params.image)",
+					Args:  []string{"--topic=$(params.topic)", "--debug=$(params.debug)"},
+					Env:   []corev1.EnvVar{{Name: "TOPIC", Value: "$(params.topic)"}},
+				}},
+			},
+		},
+	}
+
+	if got, want := spec.References(), []string{"debug", "image", "topic"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %v, wanted %v", got, want)
+	}
+
+	got, err := spec.Render(map[string]string{"image": "adapter", "topic": "orders", "debug": "false"})
+	if err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+	want := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Image: "example.com/adapter",
+				Args:  []string{"--topic=orders", "--debug=false"},
+				Env:   []corev1.EnvVar{{Name: "TOPIC", Value: "orders"}},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Render() = %v, wanted %v", got, want)
+	}
+
+	if _, err := spec.Render(map[string]string{"image": "adapter"}); err == nil {
+		t.Error("Render() = nil, wanted an error for the missing parameters")
+	}
+}
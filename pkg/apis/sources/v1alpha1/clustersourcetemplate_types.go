@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterSourceTemplate is a kind of source that a platform team defines
+// for the whole cluster. Tenants instantiate it in their namespaces with a
+// SourceInstance, which supplies its parameters and a sink.
+type ClusterSourceTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the ClusterSourceTemplate (from the client).
+	// +required
+	Spec ClusterSourceTemplateSpec `json:"spec,omitempty"`
+}
+
+// Check that ClusterSourceTemplate can be validated and defaulted.
+var _ apis.Validatable = (*ClusterSourceTemplate)(nil)
+var _ apis.Defaultable = (*ClusterSourceTemplate)(nil)
+
+// SourceTemplateMode is how the pods of a SourceInstance are run.
+type SourceTemplateMode string
+
+const (
+	// SourceTemplateModeJob runs the pod template once, as a Job.
+	SourceTemplateModeJob SourceTemplateMode = "Job"
+
+	// SourceTemplateModeCronJob runs the pod template on a schedule, as a
+	// CronJob.
+	SourceTemplateModeCronJob SourceTemplateMode = "CronJob"
+
+	// SourceTemplateModeDeployment keeps the pod template running, as a
+	// Deployment.
+	SourceTemplateModeDeployment SourceTemplateMode = "Deployment"
+)
+
+// SourceTemplateParameterType is the type of the value of a parameter.
+// Values are strings either way; the type only restricts what they may be.
+type SourceTemplateParameterType string
+
+const (
+	SourceTemplateParameterTypeString  SourceTemplateParameterType = "string"
+	SourceTemplateParameterTypeInteger SourceTemplateParameterType = "integer"
+	SourceTemplateParameterTypeBoolean SourceTemplateParameterType = "boolean"
+)
+
+// ClusterSourceTemplateSpec holds the desired state of the ClusterSourceTemplate (from the client).
+type ClusterSourceTemplateSpec struct {
+	// Mode is how the pod template is run: Job, CronJob or Deployment.
+	// +required
+	Mode SourceTemplateMode `json:"mode"`
+
+	// Schedule is the schedule of the CronJob in CronJob mode, in Cron
+	// format. It is not allowed in the other modes.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Parameters declares the parameters that SourceInstances supply.
+	// +optional
+	Parameters []SourceTemplateParameter `json:"parameters,omitempty"`
+
+	// Template is the pod template of the source. Every $(params.NAME) in
+	// its strings is replaced with the value of the parameter NAME. Its
+	// containers get K_SINK and K_OUTPUT_FORMAT like those of other sources.
+	// +required
+	Template corev1.PodTemplateSpec `json:"template"`
+}
+
+// SourceTemplateParameter declares a parameter of a ClusterSourceTemplate.
+type SourceTemplateParameter struct {
+	// Name is what the pod template refers to the parameter by.
+	// +required
+	Name string `json:"name"`
+
+	// Description tells tenants what the parameter is for.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type is string, integer or boolean. Defaults to string.
+	// +optional
+	Type SourceTemplateParameterType `json:"type,omitempty"`
+
+	// Required parameters must be supplied by every SourceInstance.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Default is the value of the parameter when a SourceInstance doesn't
+	// supply one.
+	// +optional
+	Default *string `json:"default,omitempty"`
+
+	// Pattern is a regular expression that values of the parameter must
+	// match.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterSourceTemplateList is a list of ClusterSourceTemplate resources
+type ClusterSourceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterSourceTemplate `json:"items"`
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable
+func (t *ClusterSourceTemplate) Validate(ctx context.Context) *apis.FieldError {
+	return t.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate implements apis.Validatable
+func (s *ClusterSourceTemplateSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	restartPolicy := s.Template.Spec.RestartPolicy
+	switch s.Mode {
+	case SourceTemplateModeJob, SourceTemplateModeCronJob:
+		// Jobs don't allow pods to restart always.
+		if restartPolicy == corev1.RestartPolicyAlways {
+			errs = errs.Also(apis.ErrInvalidValue(restartPolicy, "template.spec.restartPolicy"))
+		}
+	case SourceTemplateModeDeployment:
+		if restartPolicy != "" && restartPolicy != corev1.RestartPolicyAlways {
+			errs = errs.Also(apis.ErrInvalidValue(restartPolicy, "template.spec.restartPolicy"))
+		}
+	case "":
+		errs = errs.Also(apis.ErrMissingField("mode"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.Mode, "mode"))
+	}
+
+	if s.Mode == SourceTemplateModeCronJob {
+		if _, err := cron.ParseStandard(s.Schedule); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.Schedule, "schedule"))
+		}
+	} else if s.Schedule != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("schedule"))
+	}
+
+	declared := map[string]bool{}
+	for i, p := range s.Parameters {
+		if declared[p.Name] {
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("duplicate parameter %q", p.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("parameters", i))
+		}
+		declared[p.Name] = true
+		errs = errs.Also(p.Validate(ctx).ViaFieldIndex("parameters", i))
+	}
+
+	if len(s.Template.Spec.Containers) == 0 {
+		errs = errs.Also(apis.ErrMissingField("template.spec.containers"))
+	}
+	for _, name := range s.References() {
+		if !declared[name] {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("reference to undeclared parameter %q", name),
+				Paths:   []string{"template"},
+			})
+		}
+	}
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (p *SourceTemplateParameter) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if p.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	} else if !parameterName.MatchString(p.Name) {
+		errs = errs.Also(apis.ErrInvalidValue(p.Name, "name"))
+	}
+
+	switch p.Type {
+	case "", SourceTemplateParameterTypeString, SourceTemplateParameterTypeInteger, SourceTemplateParameterTypeBoolean:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(p.Type, "type"))
+	}
+
+	if _, err := regexp.Compile(p.Pattern); err != nil {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("invalid value: %s", p.Pattern),
+			Paths:   []string{"pattern"},
+			Details: err.Error(),
+		})
+	} else if p.Default != nil {
+		if err := p.Check(*p.Default); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("invalid value: %s", *p.Default),
+				Paths:   []string{"default"},
+				Details: err.Error(),
+			})
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestClusterSourceTemplateValidation(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Image: "example-img",
+				Args:  []string{"--topic=$(params.topic)"},
+			}},
+		},
+	}
+	topic := SourceTemplateParameter{Name: "topic", Required: true}
+
+	tests := []struct {
+		name string
+		t    *ClusterSourceTemplate
+		want string
+	}{{
+		name: "all perfect",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       SourceTemplateModeDeployment,
+			Parameters: []SourceTemplateParameter{topic},
+			Template:   template,
+		}},
+		want: ``,
+	}, {
+		name: "cronjob mode with a schedule",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       SourceTemplateModeCronJob,
+			Schedule:   "*/5 * * * *",
+			Parameters: []SourceTemplateParameter{topic},
+			Template:   template,
+		}},
+		want: ``,
+	}, {
+		name: "missing mode and containers",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Template: corev1.PodTemplateSpec{},
+		}},
+		want: `missing field(s): spec.mode, spec.template.spec.containers`,
+	}, {
+		name: "unknown mode",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       "StatefulSet",
+			Parameters: []SourceTemplateParameter{topic},
+			Template:   template,
+		}},
+		want: `invalid value: StatefulSet: spec.mode`,
+	}, {
+		name: "schedule outside of cronjob mode",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       SourceTemplateModeJob,
+			Schedule:   "@hourly",
+			Parameters: []SourceTemplateParameter{topic},
+			Template:   template,
+		}},
+		want: `must not set the field(s): spec.schedule`,
+	}, {
+		name: "bad schedule",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       SourceTemplateModeCronJob,
+			Schedule:   "every now and then",
+			Parameters: []SourceTemplateParameter{topic},
+			Template:   template,
+		}},
+		want: `invalid value: every now and then: spec.schedule`,
+	}, {
+		name: "deployment that does not restart",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:       SourceTemplateModeDeployment,
+			Parameters: []SourceTemplateParameter{topic},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers:    template.Spec.Containers,
+				RestartPolicy: corev1.RestartPolicyNever,
+			}},
+		}},
+		want: `invalid value: Never: spec.template.spec.restartPolicy`,
+	}, {
+		name: "bad parameters",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode: SourceTemplateModeJob,
+			Parameters: []SourceTemplateParameter{
+				topic,
+				topic,
+				{Name: "my param"},
+				{Name: "partitions", Type: "float"},
+				{Name: "broker", Pattern: "("},
+				{Name: "debug", Type: SourceTemplateParameterTypeBoolean, Default: ptr.String("yes")},
+			},
+			Template: template,
+		}},
+		want: `duplicate parameter "topic": spec.parameters[1].name
+invalid value: (: spec.parameters[4].pattern
+error parsing regexp: missing closing ): ` + "`(`" + `
+invalid value: float: spec.parameters[3].type
+invalid value: my param: spec.parameters[2].name
+invalid value: yes: spec.parameters[5].default
+not a boolean`,
+	}, {
+		name: "reference to an undeclared parameter",
+		t: &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+			Mode:     SourceTemplateModeJob,
+			Template: template,
+		}},
+		want: `reference to undeclared parameter "topic": spec.template`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := test.t.Validate(context.Background())
+			if got := errs.Error(); got != test.want {
+				t.Errorf("Validate() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestClusterSourceTemplateDefaults(t *testing.T) {
+	tmpl := &ClusterSourceTemplate{Spec: ClusterSourceTemplateSpec{
+		Parameters: []SourceTemplateParameter{
+			{Name: "topic"},
+			{Name: "partitions", Type: SourceTemplateParameterTypeInteger},
+		},
+	}}
+	tmpl.SetDefaults(context.Background())
+
+	if got, want := tmpl.Spec.Parameters[0].Type, SourceTemplateParameterTypeString; got != want {
+		t.Errorf("Parameters[0].Type = %q, wanted %q", got, want)
+	}
+	if got, want := tmpl.Spec.Parameters[1].Type, SourceTemplateParameterTypeInteger; got != want {
+		t.Errorf("Parameters[1].Type = %q, wanted %q", got, want)
+	}
+}
@@ -19,22 +19,68 @@ package v1alpha1
 import (
 	"context"
 
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// DefaultRecentRunsLimit is the RecentRunsLimit of CronJobSources that
+	// set none.
+	DefaultRecentRunsLimit = 10
+
+	// DefaultFailureThreshold is the FailureThreshold of CronJobSources
+	// that set none.
+	DefaultFailureThreshold = 3
+)
+
 // SetDefaults implements apis.Defaultable
 func (s *CronJobSource) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.BaseSourceSpec.SetDefaults(ctx)
 
 	// TODO(spencer-p) Is this a good default? Look at k8s docs
-	restartPolicy := &s.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy
-	if *restartPolicy == "" || *restartPolicy == corev1.RestartPolicyAlways {
-		*restartPolicy = corev1.RestartPolicyNever
+	tSpec := &s.Spec.JobTemplate.Spec.Template.Spec
+	if tSpec.RestartPolicy == "" || tSpec.RestartPolicy == corev1.RestartPolicyAlways {
+		tSpec.RestartPolicy = sourceDefaults(ctx).RestartPolicy
+		if tSpec.RestartPolicy == "" {
+			tSpec.RestartPolicy = corev1.RestartPolicyNever
+		}
 	}
+	setContainerDefaults(ctx, tSpec)
 
 	if s.Spec.Suspend == nil {
 		s.Spec.Suspend = ptr.Bool(false)
 	}
+
+	if s.Spec.RecentRunsLimit == nil {
+		s.Spec.RecentRunsLimit = ptr.Int32(DefaultRecentRunsLimit)
+	}
+
+	if s.Spec.FailureThreshold == nil {
+		s.Spec.FailureThreshold = ptr.Int32(DefaultFailureThreshold)
+	}
+
+	if s.Spec.IsInline() && s.Spec.ContentType == "" {
+		s.Spec.ContentType = "application/json"
+	}
+}
+
+// GetRecentRunsLimit returns the RecentRunsLimit of the spec, or the default
+// for CronJobSources stored before it was defaulted.
+func (s *CronJobSourceSpec) GetRecentRunsLimit() int32 {
+	if s.RecentRunsLimit == nil {
+		return DefaultRecentRunsLimit
+	}
+	return *s.RecentRunsLimit
+}
+
+// GetFailureThreshold returns the FailureThreshold of the spec, or the
+// default for CronJobSources stored before it was defaulted.
+func (s *CronJobSourceSpec) GetFailureThreshold() int32 {
+	if s.FailureThreshold == nil {
+		return DefaultFailureThreshold
+	}
+	return *s.FailureThreshold
 }
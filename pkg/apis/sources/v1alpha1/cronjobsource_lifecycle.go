@@ -17,7 +17,11 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"sort"
+	"time"
+
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 )
@@ -29,11 +33,16 @@ const (
 
 	// CronJobSourceConditionCronJobCreated becomes true when the underlying CronJob exists.
 	CronJobSourceConditionCronJobCreated apis.ConditionType = "CronJobCreated"
+
+	// CronJobSourceConditionRunsSucceeding becomes false when too many runs
+	// in a row have failed.
+	CronJobSourceConditionRunsSucceeding apis.ConditionType = "RunsSucceeding"
 )
 
 var cronJobCondSet = apis.NewLivingConditionSet(
 	SourceConditionSinkProvided,
 	CronJobSourceConditionCronJobCreated,
+	CronJobSourceConditionRunsSucceeding,
 )
 
 // GetGroupVersionKind implements kmeta.OwnerRefable
@@ -59,6 +68,50 @@ func (s *CronJobSourceStatus) MarkNoSink(reason, messageFormat string, messageA
 	s.BaseSourceStatus.MarkNoSink(cronJobCondSet.Manage(s), reason, messageFormat, messageA...)
 }
 
+// MarkCleanupRunning sets the condition that the cleanup Job of the given
+// name is running.
+func (s *CronJobSourceStatus) MarkCleanupRunning(jobName string) {
+	s.BaseSourceStatus.MarkCleanupRunning(cronJobCondSet.Manage(s), jobName)
+}
+
+// MarkCleanedUp sets the condition that the cleanup Job succeeded.
+func (s *CronJobSourceStatus) MarkCleanedUp() {
+	s.BaseSourceStatus.MarkCleanedUp(cronJobCondSet.Manage(s))
+}
+
+// MarkCleanupFailed sets the condition that the cleanup Job failed.
+func (s *CronJobSourceStatus) MarkCleanupFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkCleanupFailed(cronJobCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// MarkSinkReachable sets the condition that the sink responded to the last
+// probe, which took latency.
+func (s *CronJobSourceStatus) MarkSinkReachable(latency time.Duration) {
+	s.BaseSourceStatus.MarkSinkReachable(cronJobCondSet.Manage(s), latency)
+}
+
+// MarkSinkUnreachable sets the condition that the last probe of the sink
+// failed.
+func (s *CronJobSourceStatus) MarkSinkUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkSinkUnreachable(cronJobCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// ClearSinkReachable removes the condition of the probes of the sink.
+func (s *CronJobSourceStatus) ClearSinkReachable() {
+	s.BaseSourceStatus.ClearSinkReachable(cronJobCondSet.Manage(s))
+}
+
+// MarkChildNotOwned sets the condition that the existing kind of the given
+// name is not owned by the CronJobSource.
+func (s *CronJobSourceStatus) MarkChildNotOwned(kind, name string) {
+	s.BaseSourceStatus.MarkChildNotOwned(cronJobCondSet.Manage(s), CronJobSourceConditionCronJobCreated, kind, name)
+}
+
+// MarkChildOwned removes the condition that the child is not owned.
+func (s *CronJobSourceStatus) MarkChildOwned() {
+	s.BaseSourceStatus.MarkChildOwned(cronJobCondSet.Manage(s))
+}
+
 // MarkCronJobCreated sets the condition that the CronJobSource owns a CronJob.
 func (s *CronJobSourceStatus) MarkCronJobCreated() {
 	cronJobCondSet.Manage(s).MarkTrue(CronJobSourceConditionCronJobCreated)
@@ -73,3 +126,112 @@ func (s *CronJobSourceStatus) PropagateCronJobStatus(from *batchv1beta1.CronJobS
 	from.DeepCopyInto(&s.CronJobStatus)
 	s.ActiveCount = len(from.Active)
 }
+
+// MarkRunsSucceeding sets the condition that recent runs have not failed too often.
+func (s *CronJobSourceStatus) MarkRunsSucceeding() {
+	cronJobCondSet.Manage(s).MarkTrue(CronJobSourceConditionRunsSucceeding)
+}
+
+// MarkRunsFailing sets the condition that too many runs in a row have failed.
+func (s *CronJobSourceStatus) MarkRunsFailing(reason, msgFmt string, messageA ...interface{}) {
+	cronJobCondSet.Manage(s).MarkFalse(CronJobSourceConditionRunsSucceeding, reason, msgFmt, messageA...)
+}
+
+// RecordRuns merges the given runs into RecentRuns, keeping the most recent
+// limit of them. Runs that finished since they were last recorded update
+// LastSuccessfulTime and ConsecutiveFailures, and the RunsSucceeding
+// condition becomes false once failureThreshold runs in a row have failed.
+// Runs are expected in the order they were scheduled.
+func (s *CronJobSourceStatus) RecordRuns(runs []CronJobSourceRun, limit, failureThreshold int32) {
+	known := make(map[string]int, len(s.RecentRuns))
+	for i, run := range s.RecentRuns {
+		known[run.JobName] = i
+	}
+	var oldest *metav1.Time
+	if len(s.RecentRuns) > 0 {
+		oldest = s.RecentRuns[0].ScheduledTime
+	}
+
+	for _, run := range runs {
+		if i, ok := known[run.JobName]; ok {
+			prev := s.RecentRuns[i]
+			s.RecentRuns[i] = run
+			if prev.Result == CronJobSourceRunRunning && run.Result != CronJobSourceRunRunning {
+				s.recordResult(run)
+			}
+			continue
+		}
+		if oldest != nil && run.ScheduledTime != nil && run.ScheduledTime.Before(oldest) {
+			// The run already fell out of the history.
+			continue
+		}
+		s.RecentRuns = append(s.RecentRuns, run)
+		if run.Result != CronJobSourceRunRunning {
+			s.recordResult(run)
+		}
+	}
+
+	sort.SliceStable(s.RecentRuns, func(i, j int) bool {
+		a, b := s.RecentRuns[i].ScheduledTime, s.RecentRuns[j].ScheduledTime
+		return a != nil && b != nil && a.Before(b)
+	})
+	if extra := len(s.RecentRuns) - int(limit); extra > 0 {
+		s.RecentRuns = append([]CronJobSourceRun(nil), s.RecentRuns[extra:]...)
+	}
+
+	s.markConsecutiveFailures(failureThreshold)
+}
+
+// SetNextScheduleTime records the next tick of the schedule, given in the
+// schedule's time zone. The zero time clears it.
+func (s *CronJobSourceStatus) SetNextScheduleTime(next time.Time) {
+	if next.IsZero() {
+		s.NextScheduleTime = nil
+		s.NextScheduleTimeLocal = ""
+		return
+	}
+	s.NextScheduleTime = &metav1.Time{Time: next.UTC()}
+	s.NextScheduleTimeLocal = next.Format(time.RFC3339)
+}
+
+// MarkInline sets the conditions for a CronJobSource whose events the
+// controller sends itself, which needs no CronJob. LastScheduleTime is kept,
+// as it is the time of the last send once there is one.
+func (s *CronJobSourceStatus) MarkInline() {
+	cronJobCondSet.Manage(s).MarkTrue(CronJobSourceConditionCronJobCreated)
+	s.Active = nil
+	s.ActiveCount = 0
+}
+
+// PropagateSends copies the results of the events the controller sent for an
+// inline CronJobSource. Like RecordRuns, the RunsSucceeding condition becomes
+// false once failureThreshold sends in a row have failed.
+func (s *CronJobSourceStatus) PropagateSends(last *CronJobSourceSend, lastSuccessfulTime *metav1.Time, consecutiveFailures, failureThreshold int32) {
+	s.LastSend = last
+	if last != nil {
+		s.LastScheduleTime = last.ScheduledTime.DeepCopy()
+	}
+	s.LastSuccessfulTime = lastSuccessfulTime
+	s.ConsecutiveFailures = consecutiveFailures
+	s.markConsecutiveFailures(failureThreshold)
+}
+
+func (s *CronJobSourceStatus) markConsecutiveFailures(failureThreshold int32) {
+	if s.ConsecutiveFailures >= failureThreshold {
+		s.MarkRunsFailing("ConsecutiveFailures", "The last %d runs failed.", s.ConsecutiveFailures)
+	} else {
+		s.MarkRunsSucceeding()
+	}
+}
+
+func (s *CronJobSourceStatus) recordResult(run CronJobSourceRun) {
+	switch run.Result {
+	case CronJobSourceRunSucceeded:
+		s.ConsecutiveFailures = 0
+		if run.CompletionTime != nil && (s.LastSuccessfulTime == nil || s.LastSuccessfulTime.Before(run.CompletionTime)) {
+			s.LastSuccessfulTime = run.CompletionTime.DeepCopy()
+		}
+	case CronJobSourceRunFailed:
+		s.ConsecutiveFailures++
+	}
+}
@@ -18,6 +18,10 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCronJobSourceReady(t *testing.T) {
@@ -58,6 +62,7 @@ func TestCronJobSourceReady(t *testing.T) {
 			s.InitializeConditions()
 			s.MarkSink("example.com")
 			s.MarkCronJobCreated()
+			s.MarkRunsSucceeding()
 		},
 		want: true,
 	}, {
@@ -75,8 +80,18 @@ func TestCronJobSourceReady(t *testing.T) {
 			s.MarkSink("example.com")
 			s.MarkNoCronJob("", "")
 			s.MarkCronJobCreated()
+			s.MarkRunsSucceeding()
 		},
 		want: true,
+	}, {
+		name: "mark sink, cron job created and runs failing",
+		body: func(s *CronJobSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkCronJobCreated()
+			s.MarkRunsFailing("", "")
+		},
+		want: false,
 	}}
 
 	for _, test := range tests {
@@ -89,3 +104,137 @@ func TestCronJobSourceReady(t *testing.T) {
 		})
 	}
 }
+
+func TestCronJobSourceRecordRuns(t *testing.T) {
+	at := func(minute int) *metav1.Time {
+		t := metav1.NewTime(time.Date(2019, time.July, 1, 12, minute, 0, 0, time.UTC))
+		return &t
+	}
+	running := func(name string, minute int) CronJobSourceRun {
+		return CronJobSourceRun{JobName: name, ScheduledTime: at(minute), Result: CronJobSourceRunRunning}
+	}
+	succeeded := func(name string, minute int) CronJobSourceRun {
+		return CronJobSourceRun{JobName: name, ScheduledTime: at(minute), CompletionTime: at(minute + 1), Result: CronJobSourceRunSucceeded}
+	}
+	failed := func(name string, minute int) CronJobSourceRun {
+		return CronJobSourceRun{JobName: name, ScheduledTime: at(minute), CompletionTime: at(minute + 1), Result: CronJobSourceRunFailed}
+	}
+
+	tests := []struct {
+		name        string
+		status      CronJobSourceStatus
+		runs        []CronJobSourceRun
+		want        []CronJobSourceRun
+		wantSuccess *metav1.Time
+		wantFailed  int32
+		wantHealthy bool
+	}{{
+		name:        "no runs",
+		wantHealthy: true,
+	}, {
+		name:        "new runs are recorded",
+		runs:        []CronJobSourceRun{succeeded("a", 0), failed("b", 10), running("c", 20)},
+		want:        []CronJobSourceRun{succeeded("a", 0), failed("b", 10), running("c", 20)},
+		wantSuccess: at(1),
+		wantFailed:  1,
+		wantHealthy: true,
+	}, {
+		name: "finished runs are only counted once",
+		status: CronJobSourceStatus{
+			RecentRuns:          []CronJobSourceRun{failed("a", 0), running("b", 10)},
+			ConsecutiveFailures: 1,
+		},
+		runs:        []CronJobSourceRun{failed("a", 0), failed("b", 10)},
+		want:        []CronJobSourceRun{failed("a", 0), failed("b", 10)},
+		wantFailed:  2,
+		wantHealthy: true,
+	}, {
+		name: "too many failures",
+		status: CronJobSourceStatus{
+			RecentRuns:          []CronJobSourceRun{failed("a", 0), failed("b", 10)},
+			ConsecutiveFailures: 2,
+		},
+		runs:        []CronJobSourceRun{failed("b", 10), failed("c", 20)},
+		want:        []CronJobSourceRun{failed("a", 0), failed("b", 10), failed("c", 20)},
+		wantFailed:  3,
+		wantHealthy: false,
+	}, {
+		name: "success resets failures",
+		status: CronJobSourceStatus{
+			RecentRuns:          []CronJobSourceRun{failed("a", 0), failed("b", 10), failed("c", 20)},
+			ConsecutiveFailures: 3,
+		},
+		runs:        []CronJobSourceRun{succeeded("d", 30)},
+		want:        []CronJobSourceRun{failed("b", 10), failed("c", 20), succeeded("d", 30)},
+		wantSuccess: at(31),
+		wantHealthy: true,
+	}, {
+		name: "runs that fell out of the history are ignored",
+		status: CronJobSourceStatus{
+			RecentRuns: []CronJobSourceRun{succeeded("b", 10), succeeded("c", 20), succeeded("d", 30)},
+		},
+		runs:        []CronJobSourceRun{failed("a", 0), succeeded("b", 10)},
+		want:        []CronJobSourceRun{succeeded("b", 10), succeeded("c", 20), succeeded("d", 30)},
+		wantHealthy: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := test.status.DeepCopy()
+			s.InitializeConditions()
+			s.RecordRuns(test.runs, 3, 3)
+
+			if diff := cmp.Diff(test.want, s.RecentRuns); diff != "" {
+				t.Errorf("unexpected recent runs (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantSuccess, s.LastSuccessfulTime); diff != "" {
+				t.Errorf("unexpected last successful time (-want, +got): %s", diff)
+			}
+			if s.ConsecutiveFailures != test.wantFailed {
+				t.Errorf("ConsecutiveFailures = %d, wanted %d", s.ConsecutiveFailures, test.wantFailed)
+			}
+			if got := s.GetCondition(CronJobSourceConditionRunsSucceeding).IsTrue(); got != test.wantHealthy {
+				t.Errorf("RunsSucceeding = %t, wanted %t", got, test.wantHealthy)
+			}
+		})
+	}
+}
+
+func TestCronJobSourcePropagateSends(t *testing.T) {
+	scheduled := metav1.NewTime(time.Date(2019, time.July, 1, 12, 10, 0, 0, time.UTC))
+	last := &CronJobSourceSend{ScheduledTime: scheduled, EventID: "1234-1561983000", Message: "connection refused"}
+
+	tests := []struct {
+		name      string
+		failures  int32
+		wantReady bool
+	}{{
+		name:      "below threshold",
+		failures:  2,
+		wantReady: true,
+	}, {
+		name:      "at threshold",
+		failures:  3,
+		wantReady: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &CronJobSourceStatus{}
+			s.InitializeConditions()
+			s.MarkSink("uri://example")
+			s.MarkInline()
+			s.PropagateSends(last, nil, test.failures, 3)
+
+			if got := s.Ready(); got != test.wantReady {
+				t.Errorf("Ready() = %t, wanted %t", got, test.wantReady)
+			}
+			if diff := cmp.Diff(&scheduled, s.LastScheduleTime); diff != "" {
+				t.Errorf("unexpected last schedule time (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(last, s.LastSend); diff != "" {
+				t.Errorf("unexpected last send (-want, +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+const (
+	// TriggerRunAnnotation requests a one-off run of a CronJobSource outside
+	// of its schedule. A run is started every time the value changes.
+	TriggerRunAnnotation = "sources.knative.dev/trigger-run"
+
+	// BackfillAnnotation requests one run of a CronJobSource for every tick
+	// of its schedule in a time range, given as "<start>/<end>" in RFC 3339.
+	// Both ends of the range are inclusive.
+	BackfillAnnotation = "sources.knative.dev/backfill"
+
+	// MaxBackfillRuns is the most runs a single backfill may start.
+	MaxBackfillRuns = 100
+
+	// maxIntervalTicks is the most ticks that ScheduleInterval looks at.
+	maxIntervalTicks = 1000
+)
+
+// BackfillTimes returns the scheduled times that the given value of the
+// backfill annotation asks to be run, in order.
+func (s *CronJobSourceSpec) BackfillTimes(value string) ([]time.Time, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected <start>/<end>, got %q", value)
+	}
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %v", err)
+	}
+	end, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %v", err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("end %s is before start %s", parts[1], parts[0])
+	}
+
+	schedule, loc, err := s.parseSchedule()
+	if err != nil {
+		return nil, err
+	}
+
+	var times []time.Time
+	// Next is exclusive, so step back to include a tick right at the start.
+	for t := schedule.Next(start.In(loc).Add(-time.Second)); !t.After(end); t = schedule.Next(t) {
+		if len(times) == MaxBackfillRuns {
+			return nil, fmt.Errorf("range covers more than %d runs", MaxBackfillRuns)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// Location returns the time zone the schedule is in. Without a TimeZone,
+// schedules are in UTC, which is where the kube-controller-manager usually
+// runs CronJobs.
+func (s *CronJobSourceSpec) Location() (*time.Location, error) {
+	switch s.TimeZone {
+	case "":
+		return time.UTC, nil
+	case "Local":
+		// This would be the zone of whoever happens to load it.
+		return nil, fmt.Errorf("unknown time zone %s", s.TimeZone)
+	}
+	return time.LoadLocation(s.TimeZone)
+}
+
+// NextScheduleTime returns the first tick of the schedule after t, in the
+// schedule's time zone.
+func (s *CronJobSourceSpec) NextScheduleTime(t time.Time) (time.Time, error) {
+	schedule, loc, err := s.parseSchedule()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t.In(loc)), nil
+}
+
+// ScheduleInterval returns the shortest time between two ticks of the
+// schedule, or zero if it has fewer than two ticks. Only the first ticks
+// after a fixed time are looked at, which is enough to find the shortest
+// interval of any schedule that has a tick more than once a month or so.
+func (s *CronJobSourceSpec) ScheduleInterval() (time.Duration, error) {
+	schedule, loc, err := s.parseSchedule()
+	if err != nil {
+		return 0, err
+	}
+
+	var shortest time.Duration
+	prev := schedule.Next(time.Date(2019, time.January, 1, 0, 0, 0, 0, loc))
+	for i := 0; i < maxIntervalTicks && !prev.IsZero(); i++ {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if d := next.Sub(prev); shortest == 0 || d < shortest {
+			shortest = d
+		}
+		if shortest <= time.Minute {
+			// No schedule has ticks closer together.
+			break
+		}
+		prev = next
+	}
+	return shortest, nil
+}
+
+func (s *CronJobSourceSpec) parseSchedule() (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(s.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule %q: %v", s.Schedule, err)
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone %q: %v", s.TimeZone, err)
+	}
+	return schedule, loc, nil
+}
@@ -52,6 +52,31 @@ var _ kmeta.OwnerRefable = (*CronJobSource)(nil)
 type CronJobSourceSpec struct {
 	BaseSourceSpec           `json:",inline"`
 	batchv1beta1.CronJobSpec `json:",inline"`
+
+	// RecentRunsLimit is the number of runs to keep in status.recentRuns.
+	// Defaults to 10.
+	// +optional
+	RecentRunsLimit *int32 `json:"recentRunsLimit,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed runs after which
+	// the RunsSucceeding condition becomes False. Defaults to 3.
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// Data is sent as the payload of an event on every tick of the
+	// schedule. It is only used when JobTemplate has no containers, in which
+	// case the controller sends the events itself instead of running pods.
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// ContentType is the media type of Data. Defaults to application/json.
+	// +optional
+	ContentType string `json:"contentType,omitempty"`
+
+	// TimeZone is the IANA name of the time zone the schedule is in, such as
+	// Europe/Berlin. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 // CronJobSourceStatus communicates the observed state of the CronJobSource (from the controller).
@@ -64,6 +89,98 @@ type CronJobSourceStatus struct {
 	// parsed by kubectl, etc. K8s has special sauce to do this automatically
 	// when getting resources, but we have to do it manually.
 	ActiveCount int `json:"activeCount"`
+
+	// RecentRuns holds the most recent runs of the CronJob, oldest first.
+	// +optional
+	RecentRuns []CronJobSourceRun `json:"recentRuns,omitempty"`
+
+	// LastSuccessfulTime is the time the last successful run finished.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// ConsecutiveFailures is the number of runs that failed since the last
+	// successful run.
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+
+	// LastTriggerRun is the value of the trigger-run annotation that was
+	// last acted on.
+	// +optional
+	LastTriggerRun string `json:"lastTriggerRun,omitempty"`
+
+	// LastBackfill is the value of the backfill annotation that was last
+	// acted on.
+	// +optional
+	LastBackfill string `json:"lastBackfill,omitempty"`
+
+	// NextScheduleTime is the next tick of the schedule, in UTC.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// NextScheduleTimeLocal is NextScheduleTime in the time zone of the
+	// schedule, in RFC 3339.
+	// +optional
+	NextScheduleTimeLocal string `json:"nextScheduleTimeLocal,omitempty"`
+
+	// LastSend is the result of the last event sent by the controller for an
+	// inline CronJobSource.
+	// +optional
+	LastSend *CronJobSourceSend `json:"lastSend,omitempty"`
+}
+
+// CronJobSourceSend is the result of sending the event of an inline
+// CronJobSource for one tick of its schedule.
+type CronJobSourceSend struct {
+	// ScheduledTime is the tick of the schedule the event was sent for.
+	ScheduledTime metav1.Time `json:"scheduledTime"`
+
+	// EventID is the ID of the event.
+	EventID string `json:"eventID"`
+
+	// Succeeded is true if the sink accepted the event.
+	Succeeded bool `json:"succeeded"`
+
+	// Message explains why the event was not accepted.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CronJobSourceRunResult is the outcome of a single run of a CronJobSource.
+type CronJobSourceRunResult string
+
+const (
+	// CronJobSourceRunRunning means the run's Job has not finished yet.
+	CronJobSourceRunRunning CronJobSourceRunResult = "Running"
+	// CronJobSourceRunSucceeded means the run's Job completed.
+	CronJobSourceRunSucceeded CronJobSourceRunResult = "Succeeded"
+	// CronJobSourceRunFailed means the run's Job failed.
+	CronJobSourceRunFailed CronJobSourceRunResult = "Failed"
+)
+
+// CronJobSourceRun records a single run of a CronJobSource.
+type CronJobSourceRun struct {
+	// JobName is the name of the Job that carried out the run.
+	JobName string `json:"jobName"`
+
+	// ScheduledTime is the time the run was scheduled for.
+	// +optional
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
+
+	// StartTime is the time the run's Job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the run's Job finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Result is the outcome of the run.
+	Result CronJobSourceRunResult `json:"result"`
+
+	// Reason and Message explain why the run failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -76,10 +193,28 @@ type CronJobSourceList struct {
 	Items []CronJobSource `json:"items"`
 }
 
+// IsInline returns true if the controller sends the CronJobSource's events
+// itself, rather than running its job template.
+func (s *CronJobSourceSpec) IsInline() bool {
+	return len(s.JobTemplate.Spec.Template.Spec.Containers) == 0
+}
+
 func (s *CronJobSource) GetSink() apisv1alpha1.Destination {
 	return s.Spec.Sink
 }
 
+func (s *CronJobSource) GetCleanup() *SourceCleanup {
+	return s.Spec.Cleanup
+}
+
+func (s *CronJobSource) GetOutputFormat() OutputFormatType {
+	return s.Spec.OutputFormat
+}
+
+func (s *CronJobSource) GetEventTypes() []SourceEventType {
+	return s.Spec.EventTypes
+}
+
 func (s *CronJobSource) GetStatus() SourceStatus {
 	return &s.Status
 }
@@ -18,12 +18,85 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/robfig/cron"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	"knative.dev/pkg/apis"
 )
 
 // Validate implements apis.Validatable
 func (s *CronJobSource) Validate(ctx context.Context) *apis.FieldError {
-	return s.Spec.Validate(ctx).ViaField("spec")
-	// TODO(spencer-p) Verify the Job spec -- k8s does not provide a method for this
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+	errs := s.Spec.Validate(ctx).ViaField("spec")
+
+	if value, ok := s.Annotations[BackfillAnnotation]; ok {
+		if _, err := s.Spec.BackfillTimes(value); err != nil {
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("invalid value: %s", value),
+				Paths:   []string{BackfillAnnotation},
+				Details: err.Error(),
+			}).ViaField("metadata", "annotations"))
+		}
+	}
+
+	// Only new schedules are held to the minimum interval, so that sources
+	// that predate a SourceQuota can still be updated.
+	var check func(*SourceQuota, SourceQuotaUsage) *apis.FieldError
+	if original, ok := apis.GetBaseline(ctx).(*CronJobSource); !ok || original.Spec.Schedule != s.Spec.Schedule || original.Spec.TimeZone != s.Spec.TimeZone {
+		if interval, err := s.Spec.ScheduleInterval(); err == nil {
+			check = scheduleQuota(interval)
+		}
+	}
+	errs = errs.Also(validateQuotas(ctx, "CronJobSource", check))
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (s *CronJobSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := s.BaseSourceSpec.Validate(ctx)
+
+	if s.RecentRunsLimit != nil && *s.RecentRunsLimit < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.RecentRunsLimit, "recentRunsLimit"))
+	}
+
+	if s.FailureThreshold != nil && *s.FailureThreshold < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.FailureThreshold, "failureThreshold"))
+	}
+
+	if _, err := s.Location(); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(s.TimeZone, "timeZone"))
+	}
+
+	// The CronJob fields are only checked when the spec is written, so that
+	// the status of sources stored before they were checked can still be
+	// written. Inline sources always needed a valid schedule.
+	if s.IsInline() || !apis.IsInStatusUpdate(ctx) {
+		if _, err := cron.ParseStandard(s.Schedule); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.Schedule, "schedule"))
+		}
+	}
+
+	if !apis.IsInStatusUpdate(ctx) {
+		switch s.ConcurrencyPolicy {
+		case "", batchv1beta1.AllowConcurrent, batchv1beta1.ForbidConcurrent, batchv1beta1.ReplaceConcurrent:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(s.ConcurrencyPolicy, "concurrencyPolicy"))
+		}
+	}
+
+	// Either the controller sends Data, or the job template runs.
+	if s.IsInline() {
+		if s.Data == "" {
+			errs = errs.Also(apis.ErrMissingOneOf("data", "jobTemplate.spec.template.spec.containers"))
+		}
+	} else {
+		if s.Data != "" {
+			errs = errs.Also(apis.ErrMultipleOneOf("data", "jobTemplate.spec.template.spec.containers"))
+		}
+		errs = errs.Also(ValidateJobPodTemplate(&s.JobTemplate.Spec.Template).ViaField("jobTemplate", "spec", "template"))
+	}
+
+	return errs
 }
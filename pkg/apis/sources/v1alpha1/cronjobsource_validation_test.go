@@ -19,13 +19,33 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
+	"knative.dev/pkg/apis"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
 
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestCronJobSourceValidation(t *testing.T) {
+	// Any job template with a container will do.
+	cronJobSpec := batchv1beta1.CronJobSpec{
+		Schedule: "@hourly",
+		JobTemplate: batchv1beta1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Image: "example-img"}},
+					},
+				},
+			},
+		},
+	}
+
 	tests := []struct {
 		name string
 		s    *CronJobSource
@@ -40,7 +60,7 @@ func TestCronJobSourceValidation(t *testing.T) {
 				APIVersion: "42",
 				Kind:       "Service",
 			}},
-		}}},
+		}, CronJobSpec: cronJobSpec}},
 		want: ``,
 	}, {
 		name: "bad sink shows up in spec field",
@@ -50,8 +70,184 @@ func TestCronJobSourceValidation(t *testing.T) {
 				APIVersion: "42",
 				Kind:       "Service",
 			}},
-		}}},
+		}, CronJobSpec: cronJobSpec}},
 		want: `missing field(s): spec.sink.name`,
+	}, {
+		name: "bad run history settings",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec:      cronJobSpec,
+			RecentRunsLimit:  ptr.Int32(-1),
+			FailureThreshold: ptr.Int32(0),
+		}},
+		want: `invalid value: -1: spec.recentRunsLimit
+invalid value: 0: spec.failureThreshold`,
+	}, {
+		name: "bad backfill annotation",
+		s: &CronJobSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{BackfillAnnotation: "yesterday"},
+			},
+			Spec: CronJobSourceSpec{
+				BaseSourceSpec: BaseSourceSpec{
+					OutputFormat: OutputFormatBinary,
+					Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+						Name:       "Steve",
+						APIVersion: "42",
+						Kind:       "Service",
+					}},
+				},
+				CronJobSpec: cronJobSpec,
+			},
+		},
+		want: `invalid value: yesterday: metadata.annotations.sources.knative.dev/backfill
+expected <start>/<end>, got "yesterday"`,
+	}, {
+		name: "inline data",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: batchv1beta1.CronJobSpec{Schedule: "*/5 * * * *"},
+			Data:        `{"hello": "world"}`,
+		}},
+		want: ``,
+	}, {
+		name: "inline without data",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: batchv1beta1.CronJobSpec{Schedule: "sometimes"},
+		}},
+		want: `expected exactly one, got neither: spec.data, spec.jobTemplate.spec.template.spec.containers
+invalid value: sometimes: spec.schedule`,
+	}, {
+		name: "inline data with a job template",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: cronJobSpec,
+			Data:        `{"hello": "world"}`,
+		}},
+		want: `expected exactly one, got both: spec.data, spec.jobTemplate.spec.template.spec.containers`,
+	}, {
+		name: "time zone",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: cronJobSpec,
+			TimeZone:    "Europe/Berlin",
+		}},
+		want: ``,
+	}, {
+		name: "unknown time zone",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: cronJobSpec,
+			TimeZone:    "Europe/Atlantis",
+		}},
+		want: `invalid value: Europe/Atlantis: spec.timeZone`,
+	}, {
+		name: "local time zone",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: cronJobSpec,
+			TimeZone:    "Local",
+		}},
+		want: `invalid value: Local: spec.timeZone`,
+	}, {
+		name: "bad schedule and concurrency policy",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: batchv1beta1.CronJobSpec{
+				Schedule:          "every other tuesday",
+				ConcurrencyPolicy: "Sometimes",
+				JobTemplate:       cronJobSpec.JobTemplate,
+			},
+		}},
+		want: `invalid value: Sometimes: spec.concurrencyPolicy
+invalid value: every other tuesday: spec.schedule`,
+	}, {
+		name: "invalid job template",
+		s: &CronJobSource{Spec: CronJobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			CronJobSpec: batchv1beta1.CronJobSpec{
+				Schedule: "@hourly",
+				JobTemplate: batchv1beta1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Env: []corev1.EnvVar{{Name: "K_OUTPUT_FORMAT", Value: "structured"}},
+								}},
+								RestartPolicy: corev1.RestartPolicyAlways,
+							},
+						},
+					},
+				},
+			},
+		}},
+		want: `K_OUTPUT_FORMAT is reserved and set by the controller: spec.jobTemplate.spec.template.spec.containers[0].env[0].name
+invalid value: Always: spec.jobTemplate.spec.template.spec.restartPolicy
+missing field(s): spec.jobTemplate.spec.template.spec.containers[0].image`,
 	}}
 
 	for _, test := range tests {
@@ -63,3 +259,167 @@ func TestCronJobSourceValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestCronJobSourceValidationStatusUpdate(t *testing.T) {
+	// A source stored before its schedule was checked.
+	s := &CronJobSource{Spec: CronJobSourceSpec{
+		BaseSourceSpec: BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+				Name:       "Steve",
+				APIVersion: "42",
+				Kind:       "Service",
+			}},
+		},
+		CronJobSpec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Image: "example-img"}},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	statusUpdate := apis.WithinSubResourceUpdate(context.Background(), s, "status")
+	if errs := s.Validate(statusUpdate); errs != nil {
+		t.Errorf("Validate() of a status update = %v, wanted nil", errs)
+	}
+
+	want := "invalid value: : spec.schedule"
+	if got := s.Validate(apis.WithinUpdate(context.Background(), s)).Error(); got != want {
+		t.Errorf("Validate() of an update = %q, wanted %q", got, want)
+	}
+}
+
+func TestCronJobSourceBackfillTimes(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2019, time.July, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule string
+		value    string
+		want     []time.Time
+		wantErr  bool
+	}{{
+		name:     "both ends are inclusive",
+		schedule: "*/15 * * * *",
+		value:    "2019-07-01T12:00:00Z/2019-07-01T12:45:00Z",
+		want:     []time.Time{at(12, 0), at(12, 15), at(12, 30), at(12, 45)},
+	}, {
+		name:     "offsets are honoured",
+		schedule: "@hourly",
+		value:    "2019-07-01T13:30:00+02:00/2019-07-01T14:30:00+02:00",
+		want:     []time.Time{at(12, 0)},
+	}, {
+		name:     "no ticks",
+		schedule: "@hourly",
+		value:    "2019-07-01T12:10:00Z/2019-07-01T12:20:00Z",
+	}, {
+		name:     "end before start",
+		schedule: "@hourly",
+		value:    "2019-07-01T13:00:00Z/2019-07-01T12:00:00Z",
+		wantErr:  true,
+	}, {
+		name:     "bad schedule",
+		schedule: "every now and then",
+		value:    "2019-07-01T12:00:00Z/2019-07-01T13:00:00Z",
+		wantErr:  true,
+	}, {
+		name:     "too many runs",
+		schedule: "* * * * *",
+		value:    "2019-07-01T12:00:00Z/2019-07-01T14:00:00Z",
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &CronJobSourceSpec{CronJobSpec: batchv1beta1.CronJobSpec{Schedule: test.schedule}}
+			got, err := spec.BackfillTimes(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("BackfillTimes() = %v, wanted error %t", err, test.wantErr)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("BackfillTimes() = %v, wanted %v", got, test.want)
+			}
+			for i := range got {
+				if !got[i].Equal(test.want[i]) {
+					t.Errorf("BackfillTimes()[%d] = %v, wanted %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCronJobSourceNextScheduleTime(t *testing.T) {
+	now := time.Date(2019, time.March, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone string
+		want     string
+	}{{
+		name:     "utc by default",
+		schedule: "0 9 * * *",
+		want:     "2019-03-31T09:00:00Z",
+	}, {
+		name:     "clocks go forward",
+		schedule: "0 9 * * *",
+		timeZone: "Europe/Berlin",
+		// 09:00 on the 30th has passed, and the clocks go forward overnight.
+		want: "2019-03-31T09:00:00+02:00",
+	}, {
+		name:     "later today",
+		schedule: "0 18 * * *",
+		timeZone: "Europe/Berlin",
+		want:     "2019-03-30T18:00:00+01:00",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &CronJobSourceSpec{
+				CronJobSpec: batchv1beta1.CronJobSpec{Schedule: test.schedule},
+				TimeZone:    test.timeZone,
+			}
+			got, err := spec.NextScheduleTime(now)
+			if err != nil {
+				t.Fatalf("NextScheduleTime() = %v", err)
+			}
+			if got.Format(time.RFC3339) != test.want {
+				t.Errorf("NextScheduleTime() = %s, wanted %s", got.Format(time.RFC3339), test.want)
+			}
+		})
+	}
+}
+
+func TestCronJobSourceScheduleInterval(t *testing.T) {
+	tests := []struct {
+		schedule string
+		want     time.Duration
+	}{
+		{"* * * * *", time.Minute},
+		{"*/15 * * * *", 15 * time.Minute},
+		{"0 9,17 * * *", 8 * time.Hour},
+		{"30 23 * * 1-5", 24 * time.Hour},
+		{"0 0 1 * *", 28 * 24 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			spec := &CronJobSourceSpec{CronJobSpec: batchv1beta1.CronJobSpec{Schedule: test.schedule}}
+			got, err := spec.ScheduleInterval()
+			if err != nil {
+				t.Fatalf("ScheduleInterval() = %v", err)
+			}
+			if got != test.want {
+				t.Errorf("ScheduleInterval() = %v, wanted %v", got, test.want)
+			}
+		})
+	}
+}
@@ -19,30 +19,57 @@ package v1alpha1
 import (
 	"context"
 
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
+// DefaultRunHistoryLimit is the RunHistoryLimit of JobSources that set
+// none.
+const DefaultRunHistoryLimit = 5
+
 // SetDefaults implements apis.Defaultable
 func (s *JobSource) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.BaseSourceSpec.SetDefaults(ctx)
 
-	// Use the documented default for the embedded JobSpec.
-	// See k8s.io/api/batch/v1.JobSpec.BackoffLimit.
+	defaults := sourceDefaults(ctx)
+
+	// Use the configured default for the embedded JobSpec, which is the
+	// documented one of k8s.io/api/batch/v1.JobSpec.BackoffLimit unless
+	// config-sources changes it.
 	if s.Spec.BackoffLimit == nil {
-		s.Spec.BackoffLimit = ptr.Int32(6)
+		s.Spec.BackoffLimit = ptr.Int32(defaults.BackoffLimit)
+	}
+
+	if s.Spec.RunHistoryLimit == nil {
+		s.Spec.RunHistoryLimit = ptr.Int32(DefaultRunHistoryLimit)
 	}
 
 	// Kubernetes defaults the template spec RestartPolicy to "Always",
 	// which is not valid for jobs.
-	// Choose a default that makes sense given the BackoffLimit.
+	// Unless one is configured, choose a default that makes sense given
+	// the BackoffLimit.
 	if tSpec := &s.Spec.Template.Spec; tSpec.RestartPolicy == "" {
-		if s.Spec.BackoffLimit == nil || *s.Spec.BackoffLimit <= 0 {
+		if defaults.RestartPolicy != "" {
+			tSpec.RestartPolicy = defaults.RestartPolicy
+		} else if s.Spec.BackoffLimit == nil || *s.Spec.BackoffLimit <= 0 {
 			// No back off limit; don't recreate jobs forever
 			tSpec.RestartPolicy = corev1.RestartPolicyNever
 		} else {
 			tSpec.RestartPolicy = corev1.RestartPolicyOnFailure
 		}
 	}
+
+	setContainerDefaults(ctx, &s.Spec.Template.Spec)
+}
+
+// GetRunHistoryLimit returns the RunHistoryLimit of the spec, or the default
+// for JobSources stored before it was defaulted.
+func (s *JobSourceSpec) GetRunHistoryLimit() int32 {
+	if s.RunHistoryLimit == nil {
+		return DefaultRunHistoryLimit
+	}
+	return *s.RunHistoryLimit
 }
@@ -17,12 +17,15 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 )
 
 const (
 	jobRunningReason = "Running"
+	jobRerunReason   = "RerunRequested"
 
 	// JobSourceConditionSucceeded is set when the revision starts to
 	// materialize runtime resources and becomes true when the Job finishes
@@ -75,6 +78,50 @@ func (s *JobSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...i
 	s.BaseSourceStatus.MarkNoSink(jobCondSet.Manage(s), reason, messageFormat, messageA...)
 }
 
+// MarkCleanupRunning sets the condition that the cleanup Job of the given
+// name is running.
+func (s *JobSourceStatus) MarkCleanupRunning(jobName string) {
+	s.BaseSourceStatus.MarkCleanupRunning(jobCondSet.Manage(s), jobName)
+}
+
+// MarkCleanedUp sets the condition that the cleanup Job succeeded.
+func (s *JobSourceStatus) MarkCleanedUp() {
+	s.BaseSourceStatus.MarkCleanedUp(jobCondSet.Manage(s))
+}
+
+// MarkCleanupFailed sets the condition that the cleanup Job failed.
+func (s *JobSourceStatus) MarkCleanupFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkCleanupFailed(jobCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// MarkSinkReachable sets the condition that the sink responded to the last
+// probe, which took latency.
+func (s *JobSourceStatus) MarkSinkReachable(latency time.Duration) {
+	s.BaseSourceStatus.MarkSinkReachable(jobCondSet.Manage(s), latency)
+}
+
+// MarkSinkUnreachable sets the condition that the last probe of the sink
+// failed.
+func (s *JobSourceStatus) MarkSinkUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkSinkUnreachable(jobCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// ClearSinkReachable removes the condition of the probes of the sink.
+func (s *JobSourceStatus) ClearSinkReachable() {
+	s.BaseSourceStatus.ClearSinkReachable(jobCondSet.Manage(s))
+}
+
+// MarkChildNotOwned sets the condition that the existing kind of the given
+// name is not owned by the JobSource.
+func (s *JobSourceStatus) MarkChildNotOwned(kind, name string) {
+	s.BaseSourceStatus.MarkChildNotOwned(jobCondSet.Manage(s), JobSourceConditionJobSucceeded, kind, name)
+}
+
+// MarkChildOwned removes the condition that the child is not owned.
+func (s *JobSourceStatus) MarkChildOwned() {
+	s.BaseSourceStatus.MarkChildOwned(jobCondSet.Manage(s))
+}
+
 // JobSucceeded returns true if the underlying Job has succeeded.
 func (s *JobSourceStatus) JobSucceeded() bool {
 	return jobCondSet.Manage(s).GetCondition(JobSourceConditionJobSucceeded).IsTrue()
@@ -83,7 +130,7 @@ func (s *JobSourceStatus) JobSucceeded() bool {
 // IsJobRunning returns true if the job is currently running.
 func (s *JobSourceStatus) IsJobRunning() bool {
 	jobsucceeded := jobCondSet.Manage(s).GetCondition(JobSourceConditionJobSucceeded)
-	if !jobsucceeded.IsUnknown() {
+	if jobsucceeded == nil || !jobsucceeded.IsUnknown() {
 		// The job's success is known iff if it finished running.
 		return false
 	}
@@ -107,3 +154,47 @@ func (s *JobSourceStatus) MarkJobRunning(messageFormat string, messageA ...inter
 func (s *JobSourceStatus) MarkJobFailed(reason, messageFormat string, messageA ...interface{}) {
 	jobCondSet.Manage(s).MarkFalse(JobSourceConditionJobSucceeded, reason, messageFormat, messageA...)
 }
+
+// HasJob returns true if the current run's Job has been created, whether or
+// not it is still running.
+func (s *JobSourceStatus) HasJob() bool {
+	return s.IsJobRunning() || s.IsFinished()
+}
+
+// IsFinished returns true if the current run's Job has finished, successfully or not.
+func (s *JobSourceStatus) IsFinished() bool {
+	return s.CompletionTime != nil
+}
+
+// StartRun moves the outcome of the current run, if it finished, into the run
+// history and resets the status for the given run. At most limit runs are kept
+// in the history; the runs that no longer fit are returned.
+func (s *JobSourceStatus) StartRun(runGeneration int64, jobName string, limit int32) []JobSourceRun {
+	if s.IsFinished() {
+		run := JobSourceRun{
+			RunGeneration:  s.RunGeneration,
+			JobName:        jobName,
+			CompletionTime: s.CompletionTime,
+			Succeeded:      s.JobSucceeded(),
+		}
+		if cond := jobCondSet.Manage(s).GetCondition(JobSourceConditionJobSucceeded); cond != nil && !run.Succeeded {
+			run.Reason = cond.Reason
+			run.Message = cond.Message
+		}
+		s.RunHistory = append(s.RunHistory, run)
+	}
+
+	var evicted []JobSourceRun
+	if limit < 0 {
+		limit = 0
+	}
+	if extra := len(s.RunHistory) - int(limit); extra > 0 {
+		evicted = append(evicted, s.RunHistory[:extra]...)
+		s.RunHistory = append([]JobSourceRun(nil), s.RunHistory[extra:]...)
+	}
+
+	s.RunGeneration = runGeneration
+	s.CompletionTime = nil
+	jobCondSet.Manage(s).MarkUnknown(JobSourceConditionJobSucceeded, jobRerunReason, "Run %d requested.", runGeneration)
+	return evicted
+}
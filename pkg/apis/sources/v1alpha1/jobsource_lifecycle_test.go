@@ -18,6 +18,10 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestJobSourceSucceeded(t *testing.T) {
@@ -117,3 +121,103 @@ func TestJobSourceSucceeded(t *testing.T) {
 		})
 	}
 }
+
+func TestJobSourceStartRun(t *testing.T) {
+	finished := metav1.NewTime(time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name        string
+		body        func(s *JobSourceStatus)
+		limit       int32
+		wantHistory []JobSourceRun
+		wantEvicted []JobSourceRun
+	}{{
+		name: "not started",
+		body: func(s *JobSourceStatus) {
+			s.InitializeConditions()
+		},
+		limit: 5,
+	}, {
+		name: "succeeded",
+		body: func(s *JobSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkJobSucceeded()
+			s.CompletionTime = &finished
+		},
+		limit: 5,
+		wantHistory: []JobSourceRun{{
+			JobName:        "job",
+			CompletionTime: &finished,
+			Succeeded:      true,
+		}},
+	}, {
+		name: "failed",
+		body: func(s *JobSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkJobFailed("BackoffLimitExceeded", "too many retries")
+			s.CompletionTime = &finished
+		},
+		limit: 5,
+		wantHistory: []JobSourceRun{{
+			JobName:        "job",
+			CompletionTime: &finished,
+			Reason:         "BackoffLimitExceeded",
+			Message:        "too many retries",
+		}},
+	}, {
+		name: "history is bounded",
+		body: func(s *JobSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkJobSucceeded()
+			s.CompletionTime = &finished
+			s.RunGeneration = 2
+			s.RunHistory = []JobSourceRun{{RunGeneration: 0, JobName: "job-0"}, {RunGeneration: 1, JobName: "job-1"}}
+		},
+		limit: 2,
+		wantHistory: []JobSourceRun{{RunGeneration: 1, JobName: "job-1"}, {
+			RunGeneration:  2,
+			JobName:        "job",
+			CompletionTime: &finished,
+			Succeeded:      true,
+		}},
+		wantEvicted: []JobSourceRun{{RunGeneration: 0, JobName: "job-0"}},
+	}, {
+		name: "no history",
+		body: func(s *JobSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkJobSucceeded()
+			s.CompletionTime = &finished
+		},
+		limit: 0,
+		wantEvicted: []JobSourceRun{{
+			JobName:        "job",
+			CompletionTime: &finished,
+			Succeeded:      true,
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &JobSourceStatus{}
+			test.body(s)
+			evicted := s.StartRun(3, "job", test.limit)
+
+			if diff := cmp.Diff(test.wantHistory, s.RunHistory); diff != "" {
+				t.Errorf("unexpected history (-want, +got): %s", diff)
+			}
+			if diff := cmp.Diff(test.wantEvicted, evicted); diff != "" {
+				t.Errorf("unexpected evicted runs (-want, +got): %s", diff)
+			}
+			if s.RunGeneration != 3 {
+				t.Errorf("unexpected run generation: want 3, got %d", s.RunGeneration)
+			}
+			if s.IsFinished() || s.JobSucceeded() || s.IsJobRunning() {
+				t.Errorf("expected the new run to be pending, got %+v", s.Conditions)
+			}
+		})
+	}
+}
@@ -53,11 +53,74 @@ var _ kmeta.OwnerRefable = (*JobSource)(nil)
 type JobSourceSpec struct {
 	BaseSourceSpec  `json:",inline"`
 	batchv1.JobSpec `json:",inline"`
+
+	// SourceTTLSecondsAfterFinished limits the lifetime of the Job created
+	// by this JobSource once it has finished (either Succeeded or Failed).
+	// After the TTL expires the controller deletes the Job and its Pods. The
+	// JobSource itself is kept so its final status remains visible, unless
+	// DeleteSourceAfterTTL is set.
+	// It is separate from the TTLSecondsAfterFinished of the embedded
+	// JobSpec, which is passed on to the Job.
+	// +optional
+	SourceTTLSecondsAfterFinished *int32 `json:"sourceTTLSecondsAfterFinished,omitempty"`
+
+	// DeleteSourceAfterTTL also deletes the JobSource when
+	// SourceTTLSecondsAfterFinished expires. It has no effect if no TTL is set.
+	// +optional
+	DeleteSourceAfterTTL bool `json:"deleteSourceAfterTTL,omitempty"`
+
+	// RunGeneration requests another run of the JobSource when it is
+	// increased. Each run gets its own Job. A run that is still in progress
+	// is allowed to finish before the next one starts.
+	// +optional
+	RunGeneration int64 `json:"runGeneration,omitempty"`
+
+	// RunHistoryLimit is the number of finished runs to keep in
+	// status.runHistory. Jobs of runs that fall out of the history are
+	// deleted. Defaults to 5.
+	// +optional
+	RunHistoryLimit *int32 `json:"runHistoryLimit,omitempty"`
 }
 
 // JobSourceStatus communicates the observed state of the JobSource (from the controller).
 type JobSourceStatus struct {
 	BaseSourceStatus `json:",inline"`
+
+	// CompletionTime is the time the underlying Job finished, either
+	// successfully or not. It is used to compute when the TTL expires.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RunGeneration is the spec.runGeneration of the run that the rest of
+	// this status describes.
+	// +optional
+	RunGeneration int64 `json:"runGeneration,omitempty"`
+
+	// RunHistory holds the outcomes of previous runs, oldest first.
+	// +optional
+	RunHistory []JobSourceRun `json:"runHistory,omitempty"`
+}
+
+// JobSourceRun records the outcome of a finished run of a JobSource.
+type JobSourceRun struct {
+	// RunGeneration is the spec.runGeneration that requested the run.
+	RunGeneration int64 `json:"runGeneration"`
+
+	// JobName is the name of the Job that carried out the run.
+	JobName string `json:"jobName"`
+
+	// CompletionTime is the time the run's Job finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Succeeded is true if the run's Job succeeded.
+	Succeeded bool `json:"succeeded"`
+
+	// Reason and Message explain why the run failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -74,6 +137,18 @@ func (s *JobSource) GetSink() apisv1alpha1.Destination {
 	return s.Spec.Sink
 }
 
+func (s *JobSource) GetCleanup() *SourceCleanup {
+	return s.Spec.Cleanup
+}
+
+func (s *JobSource) GetOutputFormat() OutputFormatType {
+	return s.Spec.OutputFormat
+}
+
+func (s *JobSource) GetEventTypes() []SourceEventType {
+	return s.Spec.EventTypes
+}
+
 func (s *JobSource) GetStatus() SourceStatus {
 	return &s.Status
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestJobSourceSpecTTLRoundTrip(t *testing.T) {
+	in := `{"ttlSecondsAfterFinished":10,"sourceTTLSecondsAfterFinished":60}`
+
+	var spec JobSourceSpec
+	if err := json.Unmarshal([]byte(in), &spec); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	want := JobSourceSpec{
+		JobSpec:                       batchv1.JobSpec{TTLSecondsAfterFinished: ptr.Int32(10)},
+		SourceTTLSecondsAfterFinished: ptr.Int32(60),
+	}
+	if diff := cmp.Diff(want, spec); diff != "" {
+		t.Errorf("Unmarshal() (-want, +got) = %v", diff)
+	}
+
+	out, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	var got JobSourceSpec
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("Unmarshal() = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("round trip of %s (-want, +got) = %v", out, diff)
+	}
+}
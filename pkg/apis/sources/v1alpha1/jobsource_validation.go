@@ -18,12 +18,102 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
 
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmp"
 )
 
 // Validate implements apis.Validatable
 func (js *JobSource) Validate(ctx context.Context) *apis.FieldError {
-	return js.Spec.Validate(ctx).ViaField("spec")
-	// TODO(spencer-p) Verify the Job spec -- k8s does not provide a method for this
+	ctx = apis.WithinParent(ctx, js.ObjectMeta)
+	errs := js.Spec.Validate(ctx).ViaField("spec")
+
+	if apis.IsInUpdate(ctx) && !apis.IsInStatusUpdate(ctx) {
+		if original, ok := apis.GetBaseline(ctx).(*JobSource); ok {
+			errs = errs.Also(js.CheckImmutableFields(ctx, original))
+		}
+	}
+
+	// A JobSource becomes active when it is created, or when a new run is
+	// requested after the last one finished.
+	var check func(*SourceQuota, SourceQuotaUsage) *apis.FieldError
+	if original, ok := apis.GetBaseline(ctx).(*JobSource); !ok || (js.Spec.RunGeneration > original.Spec.RunGeneration && original.Status.IsFinished()) {
+		check = activeJobsQuota
+	}
+	errs = errs.Also(validateQuotas(ctx, "JobSource", check))
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (s *JobSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := s.BaseSourceSpec.Validate(ctx)
+
+	// The pod template is only checked when the spec is written, so that
+	// the status of sources stored before it was checked can still be
+	// written.
+	if !apis.IsInStatusUpdate(ctx) {
+		errs = errs.Also(ValidateJobPodTemplate(&s.Template).ViaField("template"))
+	}
+
+	if s.SourceTTLSecondsAfterFinished != nil && *s.SourceTTLSecondsAfterFinished < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.SourceTTLSecondsAfterFinished, "sourceTTLSecondsAfterFinished"))
+	}
+
+	if s.RunGeneration < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.RunGeneration, "runGeneration"))
+	}
+
+	if s.RunHistoryLimit != nil && *s.RunHistoryLimit < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.RunHistoryLimit, "runHistoryLimit"))
+	}
+
+	return errs
+}
+
+// CheckImmutableFields checks an update of original to js. The Job of a run
+// is made from the spec once, so once it exists, changes to the Job spec or
+// the output format would be ignored. They are refused instead, unless they
+// come with a new spec.runGeneration, which runs the new spec.
+func (js *JobSource) CheckImmutableFields(ctx context.Context, original *JobSource) *apis.FieldError {
+	if js.Annotations[ForceUpdateAnnotation] == "true" {
+		return nil
+	}
+
+	var errs *apis.FieldError
+
+	if js.Spec.RunGeneration < original.Spec.RunGeneration {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("runGeneration may not decrease from %d to %d", original.Spec.RunGeneration, js.Spec.RunGeneration),
+			Paths:   []string{"spec.runGeneration"},
+		})
+	}
+
+	if !original.Status.HasJob() || js.Spec.RunGeneration > original.Spec.RunGeneration {
+		return errs
+	}
+
+	fields, err := kmp.CompareSetFields(original.Spec.JobSpec, js.Spec.JobSpec)
+	if err != nil {
+		return errs.Also(&apis.FieldError{
+			Message: "Failed to diff JobSource",
+			Paths:   []string{"spec"},
+			Details: err.Error(),
+		})
+	}
+	if original.Spec.OutputFormat != js.Spec.OutputFormat {
+		fields = append(fields, "outputFormat")
+	}
+	if len(fields) == 0 {
+		return errs
+	}
+
+	changed := &apis.FieldError{
+		Message: fmt.Sprintf("Immutable fields changed: the Job of the run exists. Increase spec.runGeneration to run the new spec, or annotate the JobSource with %s=true to change it anyway", ForceUpdateAnnotation),
+	}
+	for _, field := range fields {
+		changed.Paths = append(changed.Paths, "spec."+field)
+	}
+	return errs.Also(changed)
 }
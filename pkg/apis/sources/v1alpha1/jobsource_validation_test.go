@@ -19,44 +19,255 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
+	"knative.dev/pkg/apis"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestJobSourceValidation(t *testing.T) {
+	// Any Job spec with a container will do.
+	jobSpec := batchv1.JobSpec{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Image: "example-img"}},
+			},
+		},
+	}
+
 	tests := []struct {
 		name string
 		js   *JobSource
 		want string
 	}{{
 		name: "all perfect",
-		js: &JobSource{Spec: JobSourceSpec{BaseSourceSpec: BaseSourceSpec{
-			OutputFormat: OutputFormatBinary,
-			Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
-				// None of these fields have to be meaningful
-				Name:       "Steve",
-				APIVersion: "42",
-				Kind:       "Service",
-			}},
-		}}},
+		js: &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					// None of these fields have to be meaningful
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			JobSpec: jobSpec,
+		}},
 		want: ``,
 	}, {
 		name: "bad sink shows up in spec field",
+		js: &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			JobSpec: jobSpec,
+		}},
+		want: `missing field(s): spec.sink.name`,
+	}, {
+		name: "negative ttl",
+		js: &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			JobSpec:                       jobSpec,
+			SourceTTLSecondsAfterFinished: ptr.Int32(-1),
+		}},
+		want: `invalid value: -1: spec.sourceTTLSecondsAfterFinished`,
+	}, {
+		name: "negative run generation",
+		js: &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					Name:       "Steve",
+					APIVersion: "42",
+					Kind:       "Service",
+				}},
+			},
+			JobSpec:         jobSpec,
+			RunGeneration:   -1,
+			RunHistoryLimit: ptr.Int32(-1),
+		}},
+		want: `invalid value: -1: spec.runGeneration, spec.runHistoryLimit`,
+	}, {
+		name: "no containers",
 		js: &JobSource{Spec: JobSourceSpec{BaseSourceSpec: BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+		}}},
+		want: `missing field(s): spec.template.spec.containers`,
+	}, {
+		name: "invalid pod template",
+		js: &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			},
+			JobSpec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						InitContainers: []corev1.Container{{Name: "init"}},
+						Containers: []corev1.Container{{
+							Image: "example-img",
+							Env: []corev1.EnvVar{
+								{Name: "FOO", Value: "bar"},
+								{Name: "K_SINK", Value: "http://example.com"},
+							},
+						}},
+						RestartPolicy: corev1.RestartPolicyAlways,
+					},
+				},
+			},
+		}},
+		want: `K_SINK is reserved and set by the controller: spec.template.spec.containers[0].env[1].name
+invalid value: Always: spec.template.spec.restartPolicy
+missing field(s): spec.template.spec.initContainers[0].image`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := test.js.Validate(context.Background())
+			if got := errs.Error(); got != test.want {
+				t.Errorf("Validate() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJobSourceValidationStatusUpdate(t *testing.T) {
+	// A source stored before its pod template was checked.
+	js := &JobSource{Spec: JobSourceSpec{
+		BaseSourceSpec: BaseSourceSpec{
 			OutputFormat: OutputFormatBinary,
 			Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+				Name:       "Steve",
 				APIVersion: "42",
 				Kind:       "Service",
 			}},
-		}}},
-		want: `missing field(s): spec.sink.name`,
+		},
+		JobSpec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Image: "example-img"}},
+					RestartPolicy: corev1.RestartPolicyAlways,
+				},
+			},
+		},
+	}}
+
+	statusUpdate := apis.WithinSubResourceUpdate(context.Background(), js, "status")
+	if errs := js.Validate(statusUpdate); errs != nil {
+		t.Errorf("Validate() of a status update = %v, wanted nil", errs)
+	}
+
+	want := "invalid value: Always: spec.template.spec.restartPolicy"
+	if got := js.Validate(apis.WithinUpdate(context.Background(), js)).Error(); got != want {
+		t.Errorf("Validate() of an update = %q, wanted %q", got, want)
+	}
+}
+
+func TestJobSourceImmutableFields(t *testing.T) {
+	// jobSource is a valid JobSource whose Job runs the given image.
+	jobSource := func(image string, options ...func(*JobSource)) *JobSource {
+		js := &JobSource{Spec: JobSourceSpec{
+			BaseSourceSpec: BaseSourceSpec{
+				OutputFormat: OutputFormatBinary,
+				Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			},
+			JobSpec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Image: image}},
+					},
+				},
+			},
+		}}
+		for _, option := range options {
+			option(js)
+		}
+		return js
+	}
+	running := func(js *JobSource) {
+		js.Status.InitializeConditions()
+		js.Status.MarkJobRunning("Created Job %q.", "my-job")
+	}
+	finished := func(js *JobSource) {
+		js.Status.InitializeConditions()
+		js.Status.MarkJobSucceeded()
+		js.Status.CompletionTime = &metav1.Time{Time: time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name     string
+		original *JobSource
+		js       *JobSource
+		want     string
+	}{{
+		name:     "template changes before the job exists",
+		original: jobSource("old-img"),
+		js:       jobSource("new-img"),
+		want:     ``,
+	}, {
+		name:     "unchanged while the job runs",
+		original: jobSource("old-img", running),
+		js:       jobSource("old-img", running),
+		want:     ``,
+	}, {
+		name:     "template changes while the job runs",
+		original: jobSource("old-img", running),
+		js:       jobSource("new-img"),
+		want:     `Immutable fields changed: the Job of the run exists. Increase spec.runGeneration to run the new spec, or annotate the JobSource with sources.knative.dev/force-update=true to change it anyway: spec.template`,
+	}, {
+		name:     "job spec and output format change after the job finished",
+		original: jobSource("img", finished),
+		js: jobSource("img", func(js *JobSource) {
+			js.Spec.Parallelism = ptr.Int32(2)
+			js.Spec.OutputFormat = OutputFormatStructured
+		}),
+		want: `Immutable fields changed: the Job of the run exists. Increase spec.runGeneration to run the new spec, or annotate the JobSource with sources.knative.dev/force-update=true to change it anyway: spec.outputFormat, spec.parallelism`,
+	}, {
+		name:     "template changes with a new run",
+		original: jobSource("old-img", finished),
+		js: jobSource("new-img", func(js *JobSource) {
+			js.Spec.RunGeneration = 1
+		}),
+		want: ``,
+	}, {
+		name: "run generation decreases",
+		original: jobSource("img", func(js *JobSource) {
+			js.Spec.RunGeneration = 2
+		}),
+		js: jobSource("img", func(js *JobSource) {
+			js.Spec.RunGeneration = 1
+		}),
+		want: `runGeneration may not decrease from 2 to 1: spec.runGeneration`,
+	}, {
+		name:     "forced template change",
+		original: jobSource("old-img", running),
+		js: jobSource("new-img", func(js *JobSource) {
+			js.Annotations = map[string]string{ForceUpdateAnnotation: "true"}
+		}),
+		want: ``,
 	}}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			errs := test.js.Validate(context.Background())
+			ctx := apis.WithinUpdate(context.Background(), test.original)
+			errs := test.js.Validate(ctx)
 			if got := errs.Error(); got != test.want {
 				t.Errorf("Validate() = %q, wanted %q", got, test.want)
 			}
@@ -51,6 +51,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ServiceSourceList{},
 		&CronJobSource{},
 		&CronJobSourceList{},
+		&ClusterSourceTemplate{},
+		&ClusterSourceTemplateList{},
+		&SourceInstance{},
+		&SourceInstanceList{},
+		&SourceQuota{},
+		&SourceQuotaList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
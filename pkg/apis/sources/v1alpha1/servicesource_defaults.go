@@ -18,12 +18,17 @@ package v1alpha1
 
 import (
 	"context"
+
+	"knative.dev/pkg/apis"
 )
 
 // SetDefaults implements apis.Defaultable
 func (s *ServiceSource) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.BaseSourceSpec.SetDefaults(ctx)
 
+	setContainerDefaults(ctx, &s.Spec.Template.Spec.PodSpec)
+
 	// If serving changes their API, this could create problems.
 	s.Spec.ServiceSpec.SetDefaults(ctx)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
@@ -62,6 +64,50 @@ func (s *ServiceSourceStatus) MarkNoSink(reason, messageFormat string, messageA
 	s.BaseSourceStatus.MarkNoSink(serviceSourceCondSet.Manage(s), reason, messageFormat, messageA...)
 }
 
+// MarkCleanupRunning sets the condition that the cleanup Job of the given
+// name is running.
+func (s *ServiceSourceStatus) MarkCleanupRunning(jobName string) {
+	s.BaseSourceStatus.MarkCleanupRunning(serviceSourceCondSet.Manage(s), jobName)
+}
+
+// MarkCleanedUp sets the condition that the cleanup Job succeeded.
+func (s *ServiceSourceStatus) MarkCleanedUp() {
+	s.BaseSourceStatus.MarkCleanedUp(serviceSourceCondSet.Manage(s))
+}
+
+// MarkCleanupFailed sets the condition that the cleanup Job failed.
+func (s *ServiceSourceStatus) MarkCleanupFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkCleanupFailed(serviceSourceCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// MarkSinkReachable sets the condition that the sink responded to the last
+// probe, which took latency.
+func (s *ServiceSourceStatus) MarkSinkReachable(latency time.Duration) {
+	s.BaseSourceStatus.MarkSinkReachable(serviceSourceCondSet.Manage(s), latency)
+}
+
+// MarkSinkUnreachable sets the condition that the last probe of the sink
+// failed.
+func (s *ServiceSourceStatus) MarkSinkUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkSinkUnreachable(serviceSourceCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// ClearSinkReachable removes the condition of the probes of the sink.
+func (s *ServiceSourceStatus) ClearSinkReachable() {
+	s.BaseSourceStatus.ClearSinkReachable(serviceSourceCondSet.Manage(s))
+}
+
+// MarkChildNotOwned sets the condition that the existing kind of the given
+// name is not owned by the ServiceSource.
+func (s *ServiceSourceStatus) MarkChildNotOwned(kind, name string) {
+	s.BaseSourceStatus.MarkChildNotOwned(serviceSourceCondSet.Manage(s), ServiceSourceConditionServiceReady, kind, name)
+}
+
+// MarkChildOwned removes the condition that the child is not owned.
+func (s *ServiceSourceStatus) MarkChildOwned() {
+	s.BaseSourceStatus.MarkChildOwned(serviceSourceCondSet.Manage(s))
+}
+
 func (s *ServiceSourceStatus) MarkServiceReady() {
 	serviceSourceCondSet.Manage(s).MarkTrue(ServiceSourceConditionServiceReady)
 }
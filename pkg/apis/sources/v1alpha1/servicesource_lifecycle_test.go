@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"testing"
+
+	"knative.dev/pkg/apis"
 )
 
 func TestServiceSourceReady(t *testing.T) {
@@ -53,6 +55,24 @@ func TestServiceSourceReady(t *testing.T) {
 			s.MarkServiceNotReady("MarkServiceNotReady", "")
 		},
 		want: false,
+	}, {
+		name: "has sink but service not owned",
+		body: func(s *ServiceSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkChildNotOwned("Service", "my-service")
+		},
+		want: false,
+	}, {
+		name: "has sink and service ready once owned",
+		body: func(s *ServiceSourceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkChildNotOwned("Service", "my-service")
+			s.MarkChildOwned()
+			s.MarkServiceReady()
+		},
+		want: true,
 	}, {
 		name: "has sink and service ready",
 		body: func(s *ServiceSourceStatus) {
@@ -114,3 +134,22 @@ func TestServiceSourceReady(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceSourceChildNotOwned(t *testing.T) {
+	s := &ServiceSourceStatus{}
+	s.InitializeConditions()
+	s.MarkChildNotOwned("Service", "my-service")
+
+	cond := s.GetCondition(SourceConditionChildNotOwned)
+	if cond == nil || !cond.IsTrue() || cond.Severity != apis.ConditionSeverityError {
+		t.Errorf("ChildNotOwned = %v, wanted true with severity Error", cond)
+	}
+	if cond := s.GetCondition(ServiceSourceConditionServiceReady); cond == nil || cond.Reason != ChildNotOwnedReason {
+		t.Errorf("ServiceReady = %v, wanted reason %s", cond, ChildNotOwnedReason)
+	}
+
+	s.MarkChildOwned()
+	if cond := s.GetCondition(SourceConditionChildNotOwned); cond != nil {
+		t.Errorf("ChildNotOwned = %v once owned, wanted none", cond)
+	}
+}
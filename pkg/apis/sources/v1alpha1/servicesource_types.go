@@ -82,6 +82,18 @@ func (s *ServiceSource) GetSink() apisv1alpha1.Destination {
 	return s.Spec.Sink
 }
 
+func (s *ServiceSource) GetCleanup() *SourceCleanup {
+	return s.Spec.Cleanup
+}
+
+func (s *ServiceSource) GetOutputFormat() OutputFormatType {
+	return s.Spec.OutputFormat
+}
+
+func (s *ServiceSource) GetEventTypes() []SourceEventType {
+	return s.Spec.EventTypes
+}
+
 func (s *ServiceSource) GetStatus() SourceStatus {
 	return &s.Status
 }
@@ -24,6 +24,8 @@ import (
 
 // Validate implements apis.Validatable
 func (s *ServiceSource) Validate(ctx context.Context) *apis.FieldError {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	errs := s.Spec.BaseSourceSpec.Validate(ctx).ViaField("spec")
-	return errs.Also(s.Spec.ServiceSpec.Validate(ctx).ViaField("spec"))
+	errs = errs.Also(s.Spec.ServiceSpec.Validate(ctx).ViaField("spec"))
+	return errs.Also(validateQuotas(ctx, "ServiceSource", nil))
 }
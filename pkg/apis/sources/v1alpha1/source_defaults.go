@@ -18,13 +18,77 @@ package v1alpha1
 
 import (
 	"context"
+
+	"knative.dev/pkg/apis"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+)
+
+const (
+	// brokerAPIVersion is the API version of the Brokers that sources
+	// without a sink default to.
+	brokerAPIVersion = "eventing.knative.dev/v1alpha1"
 )
 
-// BaseSourceSpec implements apis.Defaultable. Currently, only the output
-// format can be defaulted.
+// sourceDefaults returns the configured defaults of the sources in the
+// namespace of the parent of ctx.
+func sourceDefaults(ctx context.Context) *config.Defaults {
+	return config.FromContextOrDefaults(ctx).ForNamespace(apis.ParentMeta(ctx).Namespace)
+}
+
+// setContainerDefaults fills in the configured resources of the containers
+// of the pod spec when the source is created. Updates keep the resources
+// that the source was created with: filling in resources that config-sources
+// gained since would change the pod template, which JobSources don't allow
+// once their Job exists.
+func setContainerDefaults(ctx context.Context, spec *corev1.PodSpec) {
+	if apis.IsInUpdate(ctx) {
+		return
+	}
+	defaults := sourceDefaults(ctx)
+	for i := range spec.Containers {
+		defaults.ApplyResources(&spec.Containers[i])
+	}
+}
+
+// BaseSourceSpec implements apis.Defaultable. The output format and the
+// cleanup Job can be defaulted.
 func (s *BaseSourceSpec) SetDefaults(ctx context.Context) {
-	// The default output format is binary.
+	// The default output format is binary unless config-sources says
+	// otherwise.
 	if s.OutputFormat == "" {
-		s.OutputFormat = OutputFormatBinary
+		s.OutputFormat = OutputFormatType(sourceDefaults(ctx).OutputFormat)
+	}
+
+	// Sources without a sink send to the configured Broker of their
+	// namespace, if there is one. The reference is written into the spec
+	// so that it shows where the events go.
+	if s.Sink.ObjectReference == nil && s.Sink.URI == nil {
+		if broker := sourceDefaults(ctx).SinkBroker; broker != "" {
+			s.Sink.ObjectReference = &corev1.ObjectReference{
+				APIVersion: brokerAPIVersion,
+				Kind:       "Broker",
+				Name:       broker,
+			}
+		}
+	}
+
+	if s.Cleanup != nil {
+		s.Cleanup.SetDefaults(ctx)
+	}
+}
+
+// SetDefaults implements apis.Defaultable
+func (c *SourceCleanup) SetDefaults(ctx context.Context) {
+	if c.TimeoutSeconds == nil {
+		timeout := int64(DefaultCleanupTimeoutSeconds)
+		c.TimeoutSeconds = &timeout
+	}
+	// Jobs don't allow pods to restart always.
+	if c.Template.Spec.RestartPolicy == "" {
+		c.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
 	}
+	setContainerDefaults(ctx, &c.Template.Spec)
 }
@@ -17,9 +17,34 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 )
 
+// SetObservedGeneration records the generation of the source that the status
+// reflects.
+func (s *BaseSourceStatus) SetObservedGeneration(generation int64) {
+	s.ObservedGeneration = generation
+}
+
+// SetEventTypes records the types of the events that the source declares
+// it sends.
+func (s *BaseSourceStatus) SetEventTypes(eventTypes []SourceEventType) {
+	if len(eventTypes) == 0 {
+		s.EventTypes = nil
+		return
+	}
+	s.EventTypes = append([]SourceEventType(nil), eventTypes...)
+}
+
+// GetSinkURI returns the sink URI that the source last resolved.
+func (s *BaseSourceStatus) GetSinkURI() string {
+	return s.SinkURI
+}
+
 // MarkSink sets the conditions that the source has received a sink URI.
 func (s *BaseSourceStatus) MarkSink(mgr apis.ConditionManager, uri string) {
 	s.SinkURI = uri
@@ -30,8 +55,68 @@ func (s *BaseSourceStatus) MarkSink(mgr apis.ConditionManager, uri string) {
 	}
 }
 
+// MarkChildNotOwned sets the condition t to false, and the ChildNotOwned
+// condition, because the existing kind with the name that the source gives
+// its child is not owned by the source.
+func (s *BaseSourceStatus) MarkChildNotOwned(mgr apis.ConditionManager, t apis.ConditionType, kind, name string) {
+	mgr.MarkFalse(t, ChildNotOwnedReason, ChildNotOwnedMessage, kind, name, AdoptAnnotation)
+	mgr.SetCondition(apis.Condition{
+		Type:     SourceConditionChildNotOwned,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityError,
+		Reason:   ChildNotOwnedReason,
+		Message:  fmt.Sprintf(ChildNotOwnedMessage, kind, name, AdoptAnnotation),
+	})
+}
+
+// MarkChildOwned removes the ChildNotOwned condition, for when the source
+// owns its child.
+func (s *BaseSourceStatus) MarkChildOwned(mgr apis.ConditionManager) {
+	mgr.ClearCondition(SourceConditionChildNotOwned)
+}
+
 // MarkNoSink sets the condition that the source does not have a sink configured.
 // TODO(spencer-p) This method adds almost nothing -- would be nice to have MarkSinkInvalid, MarkSinkNotResolved, etc
 func (s *BaseSourceStatus) MarkNoSink(mgr apis.ConditionManager, reason, messageFormat string, messageA ...interface{}) {
 	mgr.MarkFalse(SourceConditionSinkProvided, reason, messageFormat, messageA...)
 }
+
+// MarkCleanupRunning sets the condition that the cleanup Job of the given
+// name is running.
+func (s *BaseSourceStatus) MarkCleanupRunning(mgr apis.ConditionManager, jobName string) {
+	mgr.MarkUnknown(SourceConditionCleanedUp, "CleanupRunning", "Cleanup Job %q is running", jobName)
+}
+
+// MarkCleanedUp sets the condition that the cleanup Job succeeded.
+func (s *BaseSourceStatus) MarkCleanedUp(mgr apis.ConditionManager) {
+	mgr.MarkTrue(SourceConditionCleanedUp)
+}
+
+// MarkCleanupFailed sets the condition that the cleanup Job failed, or could
+// not be made.
+func (s *BaseSourceStatus) MarkCleanupFailed(mgr apis.ConditionManager, reason, messageFormat string, messageA ...interface{}) {
+	mgr.MarkFalse(SourceConditionCleanedUp, reason, messageFormat, messageA...)
+}
+
+// MarkSinkReachable sets the condition that the sink responded to the last
+// probe, which took latency.
+func (s *BaseSourceStatus) MarkSinkReachable(mgr apis.ConditionManager, latency time.Duration) {
+	mgr.SetCondition(apis.Condition{
+		Type:     SourceConditionSinkReachable,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityInfo,
+		Message:  fmt.Sprintf("The sink responded to a probe in %v", latency),
+	})
+}
+
+// MarkSinkUnreachable sets the condition that the last probe of the sink
+// failed.
+func (s *BaseSourceStatus) MarkSinkUnreachable(mgr apis.ConditionManager, reason, messageFormat string, messageA ...interface{}) {
+	mgr.MarkFalse(SourceConditionSinkReachable, reason, messageFormat, messageA...)
+}
+
+// ClearSinkReachable removes the condition of the probes of the sink, for
+// when sinks are not probed.
+func (s *BaseSourceStatus) ClearSinkReachable(mgr apis.ConditionManager) {
+	mgr.ClearCondition(SourceConditionSinkReachable)
+}
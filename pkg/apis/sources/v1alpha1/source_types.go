@@ -17,6 +17,9 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"knative.dev/pkg/apis"
@@ -42,6 +45,47 @@ type BaseSourceSpec struct {
 	// +optional
 	// TODO(n3wscott) This is a stub; currently unused.
 	CloudEventOverrides *duckv1beta1.CloudEventOverrides `json:"ceOverrides,omitempty"`
+
+	// Cleanup is run as a Job when the source is deleted. The source is only
+	// gone once the Job has succeeded or timed out.
+	// +optional
+	Cleanup *SourceCleanup `json:"cleanup,omitempty"`
+
+	// EventTypes lists the types of the events that the source sends. When
+	// the sink is a Broker, the source registers them with it as EventTypes.
+	// +optional
+	EventTypes []SourceEventType `json:"eventTypes,omitempty"`
+}
+
+// SourceEventType describes a type of CloudEvent that a source sends.
+type SourceEventType struct {
+	// Type is the CloudEvents type of the events.
+	// +required
+	Type string `json:"type"`
+
+	// Schema is the URL of the schema of the data of the events.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// Description tells consumers what the events are about.
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// SourceCleanup describes the Job that cleans up after a source, such as by
+// removing the webhooks it registered with an external system. Its
+// containers get K_SINK and the variables that identify the source, like
+// those of the source.
+type SourceCleanup struct {
+	// Template is the pod template of the Job. The restart policy defaults
+	// to Never.
+	// +required
+	Template corev1.PodTemplateSpec `json:"template"`
+
+	// TimeoutSeconds is how long the Job may take before the source is
+	// deleted anyway. Defaults to 300.
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
 }
 
 // BaseSourceStatus holds status information that sources need. This base will not necessarily need
@@ -52,6 +96,11 @@ type BaseSourceStatus struct {
 	// SinkURI is the current sink URI configured for the source.
 	// +optional
 	SinkURI string `json:"sinkUri,omitempty"`
+
+	// EventTypes are the types of the events that the source declares it
+	// sends.
+	// +optional
+	EventTypes []SourceEventType `json:"eventTypes,omitempty"`
 }
 
 const (
@@ -59,14 +108,67 @@ const (
 	// provided to the source. All sources will use this condition and set it true when the
 	// source is configured with a sink.
 	SourceConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// SourceConditionCleanedUp reports the progress of the cleanup Job of a
+	// source that is being deleted. It doesn't affect readiness.
+	SourceConditionCleanedUp apis.ConditionType = "CleanedUp"
+
+	// SourceConditionSinkReachable reports the result of the last probe of
+	// the sink URI, if the controller probes sinks. It doesn't affect
+	// readiness.
+	SourceConditionSinkReachable apis.ConditionType = "SinkReachable"
+
+	// SourceConditionChildNotOwned is true while an object of the name that
+	// the source gives its child exists but is controlled by something else.
+	// It is removed once the source owns its child. The condition of the
+	// child is false meanwhile, so the source is not ready.
+	SourceConditionChildNotOwned apis.ConditionType = "ChildNotOwned"
+
+	// CleanupFinalizer keeps a source with a cleanup Job around until the
+	// Job has succeeded or timed out.
+	CleanupFinalizer = "sources.knative.dev/cleanup"
+
+	// DefaultCleanupTimeoutSeconds is the default timeout of cleanup Jobs.
+	DefaultCleanupTimeoutSeconds = 300
+
+	// ChildNotOwnedReason is the reason a source is not ready when an object
+	// of the name it gives its child exists but is controlled by something
+	// else. The source leaves such objects alone.
+	ChildNotOwnedReason = "ChildNotOwned"
+
+	// ChildNotOwnedMessage is the message format that goes with
+	// ChildNotOwnedReason. Its arguments are the kind and name of the
+	// child, and AdoptAnnotation.
+	ChildNotOwnedMessage = "There is an existing %s %q that the source does not own. Delete it, or annotate the source with %s=true to adopt it if nothing else controls it."
+
+	// AdoptAnnotation, set to "true" on a source, makes it take over an
+	// existing object of the name it gives its child if nothing controls
+	// that object.
+	AdoptAnnotation = "sources.knative.dev/adopt"
+
+	// ForceUpdateAnnotation, set to "true" on a source, lets an update
+	// change fields that the webhook otherwise refuses to change.
+	ForceUpdateAnnotation = "sources.knative.dev/force-update"
 )
 
 // SourceStatus describes a status that has a sink condition.
 // Sources should have a Status member that satisfies this interface.
 // BaseSourceStatus provides methods to help satisfy this interface.
 type SourceStatus interface {
+	InitializeConditions()
+	SetObservedGeneration(generation int64)
+	SetEventTypes(eventTypes []SourceEventType)
+	GetSinkURI() string
 	MarkSink(uri string)
 	MarkNoSink(reason, messageFormat string, messageA ...interface{})
+	MarkCleanupRunning(jobName string)
+	MarkCleanedUp()
+	MarkCleanupFailed(reason, messageFormat string, messageA ...interface{})
+	MarkSinkReachable(latency time.Duration)
+	MarkSinkUnreachable(reason, messageFormat string, messageA ...interface{})
+	ClearSinkReachable()
+	MarkChildNotOwned(kind, name string)
+	MarkChildOwned()
 }
 
 // Source describes a general source that one can reason about without knowing implementation details.
@@ -74,5 +176,8 @@ type Source interface {
 	metav1.Object
 
 	GetSink() apisv1alpha1.Destination
+	GetCleanup() *SourceCleanup
+	GetOutputFormat() OutputFormatType
+	GetEventTypes() []SourceEventType
 	GetStatus() SourceStatus
 }
@@ -18,8 +18,13 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
 )
 
 // Validate implements apis.Validatable
@@ -32,5 +37,127 @@ func (s *BaseSourceSpec) Validate(ctx context.Context) *apis.FieldError {
 	// The Sink ObjectReference must be okay
 	errs = errs.Also(s.Sink.Validate(ctx).ViaField("sink"))
 
+	// The sink may be in another namespace only if the policy permits it.
+	// Status updates are let through, so that the controller can report
+	// sinks that the policy stopped permitting.
+	// Sink URIs are subject to it if they address a Service by its
+	// cluster-local host name.
+	if ns := apis.ParentMeta(ctx).Namespace; ns != "" && !apis.IsInStatusUpdate(ctx) {
+		sinkNamespace, path := config.ServiceNamespace(s.Sink.URI), "sink.uri"
+		if ref := s.Sink.ObjectReference; ref != nil {
+			sinkNamespace, path = ref.Namespace, "sink.namespace"
+		}
+		if !config.FromContextOrDefaults(ctx).SinkPermitted(ns, sinkNamespace) {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("sinks in namespace %q are not permitted for sources in namespace %q", sinkNamespace, ns),
+				Paths:   []string{path},
+			})
+		}
+	}
+
+	if s.Cleanup != nil {
+		errs = errs.Also(s.Cleanup.Validate(ctx).ViaField("cleanup"))
+	}
+
+	seen := map[string]bool{}
+	for i, et := range s.EventTypes {
+		if seen[et.Type] {
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("duplicate event type %q", et.Type),
+				Paths:   []string{"type"},
+			}).ViaFieldIndex("eventTypes", i))
+		}
+		seen[et.Type] = true
+		errs = errs.Also(et.Validate(ctx).ViaFieldIndex("eventTypes", i))
+	}
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (t *SourceEventType) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if t.Type == "" {
+		errs = errs.Also(apis.ErrMissingField("type"))
+	}
+
+	if t.Schema != "" {
+		if u, err := url.Parse(t.Schema); err != nil || !u.IsAbs() {
+			errs = errs.Also(apis.ErrInvalidValue(t.Schema, "schema"))
+		}
+	}
+
+	return errs
+}
+
+// Validate implements apis.Validatable
+func (c *SourceCleanup) Validate(ctx context.Context) *apis.FieldError {
+	errs := ValidateJobPodTemplate(&c.Template).ViaField("template")
+
+	if c.TimeoutSeconds != nil && *c.TimeoutSeconds <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*c.TimeoutSeconds, "timeoutSeconds"))
+	}
+
+	return errs
+}
+
+// ReservedEnvVars are set on the containers of sources by the controller, so
+// sources may not set them themselves.
+var ReservedEnvVars = []string{
+	"K_SINK",
+	"K_OUTPUT_FORMAT",
+	"K_CE_SOURCE",
+	"K_SOURCE_KIND",
+	"K_SOURCE_NAME",
+	"K_SOURCE_NAMESPACE",
+	"K_SOURCE_UID",
+}
+
+// ValidateJobPodTemplate checks the pod template of a Job that a source
+// runs: it needs containers with images, a restart policy that Jobs allow,
+// and no reserved environment variables. Kubernetes has no validation that
+// can be used outside of the API server, so this covers the mistakes that
+// would otherwise only show up once the controller fails to create the Job.
+func ValidateJobPodTemplate(template *corev1.PodTemplateSpec) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if len(template.Spec.Containers) == 0 {
+		errs = errs.Also(apis.ErrMissingField("spec.containers"))
+	}
+	for i, c := range template.Spec.Containers {
+		errs = errs.Also(validateContainer(&c).ViaFieldIndex("containers", i).ViaField("spec"))
+	}
+	for i, c := range template.Spec.InitContainers {
+		errs = errs.Also(validateContainer(&c).ViaFieldIndex("initContainers", i).ViaField("spec"))
+	}
+
+	switch template.Spec.RestartPolicy {
+	case "", corev1.RestartPolicyNever, corev1.RestartPolicyOnFailure:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(template.Spec.RestartPolicy, "spec.restartPolicy"))
+	}
+
+	return errs
+}
+
+func validateContainer(c *corev1.Container) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if c.Image == "" {
+		errs = errs.Also(apis.ErrMissingField("image"))
+	}
+
+	for i, env := range c.Env {
+		for _, reserved := range ReservedEnvVars {
+			if env.Name == reserved {
+				errs = errs.Also((&apis.FieldError{
+					Message: fmt.Sprintf("%s is reserved and set by the controller", env.Name),
+					Paths:   []string{"name"},
+				}).ViaFieldIndex("env", i))
+			}
+		}
+	}
+
 	return errs
 }
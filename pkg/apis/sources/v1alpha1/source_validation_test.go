@@ -20,11 +20,21 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
 )
 
 func TestSourceValidation(t *testing.T) {
@@ -85,6 +95,70 @@ missing field(s): outputFormat`,
 				Kind:       "Service",
 			}}},
 		want: `invalid value: messenger_pigeon: outputFormat`,
+	}, {
+		name: "cleanup",
+		s: &BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			Cleanup: &SourceCleanup{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Image: "example.com/cleanup"}},
+				}},
+			},
+		},
+		want: ``,
+	}, {
+		name: "cleanup without containers",
+		s: &BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			Cleanup:      &SourceCleanup{},
+		},
+		want: `missing field(s): cleanup.template.spec.containers`,
+	}, {
+		name: "cleanup that restarts always and never times out",
+		s: &BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			Cleanup: &SourceCleanup{
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Image: "example.com/cleanup"}},
+					RestartPolicy: corev1.RestartPolicyAlways,
+				}},
+				TimeoutSeconds: ptr.Int64(0),
+			},
+		},
+		want: `invalid value: 0: cleanup.timeoutSeconds
+invalid value: Always: cleanup.template.spec.restartPolicy`,
+	}, {
+		name: "event types",
+		s: &BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			EventTypes: []SourceEventType{{
+				Type:        "dev.example.order.created",
+				Schema:      "https://example.com/schemas/order.json",
+				Description: "An order was placed",
+			}, {
+				Type: "dev.example.order.shipped",
+			}},
+		},
+		want: ``,
+	}, {
+		name: "invalid event types",
+		s: &BaseSourceSpec{
+			OutputFormat: OutputFormatBinary,
+			Sink:         apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "example.com"}},
+			EventTypes: []SourceEventType{{
+				Type:   "dev.example.order.created",
+				Schema: "schemas/order.json",
+			}, {
+				Type: "dev.example.order.created",
+			}, {}},
+		},
+		want: `duplicate event type "dev.example.order.created": eventTypes[1].type
+invalid value: schemas/order.json: eventTypes[0].schema
+missing field(s): eventTypes[2].type`,
 	}}
 
 	for _, test := range tests {
@@ -114,3 +188,231 @@ func TestSourceDuckTypes(t *testing.T) {
 		}
 	}
 }
+
+func TestSourceCleanupDefaults(t *testing.T) {
+	s := &BaseSourceSpec{Cleanup: &SourceCleanup{}}
+	s.SetDefaults(context.Background())
+
+	if got, want := *s.Cleanup.TimeoutSeconds, int64(DefaultCleanupTimeoutSeconds); got != want {
+		t.Errorf("TimeoutSeconds = %d, want %d", got, want)
+	}
+	if got, want := s.Cleanup.Template.Spec.RestartPolicy, corev1.RestartPolicyNever; got != want {
+		t.Errorf("RestartPolicy = %q, want %q", got, want)
+	}
+}
+
+func TestSourceConfiguredDefaults(t *testing.T) {
+	defaults, err := config.NewDefaultsFromMap(map[string]string{
+		"output-format":         "structured",
+		"backoff-limit":         "2",
+		"restart-policy":        "Never",
+		"container-cpu-request": "100m",
+	})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "tenant",
+		Labels: map[string]string{config.NamespaceLabelPrefix + "backoff-limit": "0"},
+	}})
+	ctx := config.ToContext(context.Background(), &config.Config{
+		Defaults:   defaults,
+		Namespaces: corev1listers.NewNamespaceLister(indexer),
+	})
+
+	tests := []struct {
+		namespace   string
+		wantBackoff int32
+	}{
+		{"default", 2},
+		{"tenant", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.namespace, func(t *testing.T) {
+			s := &JobSource{
+				ObjectMeta: metav1.ObjectMeta{Namespace: test.namespace, Name: "source"},
+				Spec: JobSourceSpec{JobSpec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "example.com/source"}}},
+				}}},
+			}
+			s.SetDefaults(ctx)
+
+			if got, want := s.Spec.OutputFormat, OutputFormatStructured; got != want {
+				t.Errorf("OutputFormat = %q, want %q", got, want)
+			}
+			if got := *s.Spec.BackoffLimit; got != test.wantBackoff {
+				t.Errorf("BackoffLimit = %d, want %d", got, test.wantBackoff)
+			}
+			if got, want := s.Spec.Template.Spec.RestartPolicy, corev1.RestartPolicyNever; got != want {
+				t.Errorf("RestartPolicy = %q, want %q", got, want)
+			}
+			got := s.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+			if want := resource.MustParse("100m"); got.Cmp(want) != 0 {
+				t.Errorf("cpu request = %s, want %s", got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestSourceResourceDefaultsOnCreate(t *testing.T) {
+	jobSource := func() *JobSource {
+		return &JobSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "source"},
+			Spec: JobSourceSpec{JobSpec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "example.com/source"}}},
+			}}},
+		}
+	}
+	// The source was created before config-sources set a cpu request, and
+	// its Job runs.
+	original := jobSource()
+	original.SetDefaults(context.Background())
+	original.Status.InitializeConditions()
+	original.Status.MarkJobRunning("Created Job %q.", "my-job")
+
+	defaults, err := config.NewDefaultsFromMap(map[string]string{"container-cpu-request": "100m"})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+	ctx := config.ToContext(context.Background(), &config.Config{Defaults: defaults})
+
+	// A metadata-only update keeps the resources of the source, so the Job
+	// spec doesn't change.
+	updated := original.DeepCopy()
+	updated.Labels = map[string]string{"team": "a"}
+	updated.SetDefaults(apis.WithinUpdate(ctx, original))
+	if got := updated.Spec.Template.Spec.Containers[0].Resources; len(got.Requests) != 0 {
+		t.Errorf("Resources of an update = %v, want none", got)
+	}
+	if errs := updated.CheckImmutableFields(ctx, original); errs != nil {
+		t.Errorf("CheckImmutableFields() = %v, wanted nil", errs)
+	}
+
+	created := jobSource()
+	created.SetDefaults(ctx)
+	got := created.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+	if want := resource.MustParse("100m"); got.Cmp(want) != 0 {
+		t.Errorf("cpu request of a create = %s, want %s", got.String(), want.String())
+	}
+}
+
+func TestSourceSinkBrokerDefault(t *testing.T) {
+	defaults, err := config.NewDefaultsFromMap(map[string]string{"default-sink-broker": "default"})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+	ctx := config.ToContext(context.Background(), &config.Config{Defaults: defaults})
+	uri := &apis.URL{Scheme: "http", Host: "example.com"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		sink apisv1alpha1.Destination
+		want apisv1alpha1.Destination
+	}{{
+		name: "no sink",
+		ctx:  ctx,
+		want: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+			APIVersion: "eventing.knative.dev/v1alpha1",
+			Kind:       "Broker",
+			Name:       "default",
+		}},
+	}, {
+		name: "sink uri",
+		ctx:  ctx,
+		sink: apisv1alpha1.Destination{URI: uri},
+		want: apisv1alpha1.Destination{URI: uri},
+	}, {
+		name: "not configured",
+		ctx:  context.Background(),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &BaseSourceSpec{Sink: test.sink}
+			s.SetDefaults(test.ctx)
+			if diff := cmp.Diff(test.want, s.Sink); diff != "" {
+				t.Errorf("Sink (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
+
+func TestSourceCrossNamespaceSinkPolicy(t *testing.T) {
+	withSinkURI := func(js *JobSource, host string) *JobSource {
+		js.Spec.Sink = apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: host}}
+		return js
+	}
+
+	defaults, err := config.NewDefaultsFromMap(map[string]string{"cross-namespace-sinks": "deny"})
+	if err != nil {
+		t.Fatalf("NewDefaultsFromMap() = %v", err)
+	}
+	deny := config.ToContext(context.Background(), &config.Config{Defaults: defaults})
+
+	source := func(sinkNamespace string) *JobSource {
+		return &JobSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "source"},
+			Spec: JobSourceSpec{
+				BaseSourceSpec: BaseSourceSpec{
+					OutputFormat: OutputFormatBinary,
+					Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+						APIVersion: "eventing.knative.dev/v1alpha1",
+						Kind:       "Broker",
+						Namespace:  sinkNamespace,
+						Name:       "default",
+					}},
+				},
+				JobSpec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "example.com/source"}}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		s    *JobSource
+		want string
+	}{{
+		name: "same namespace",
+		ctx:  deny,
+		s:    source("tenant"),
+	}, {
+		name: "other namespace allowed by default",
+		ctx:  context.Background(),
+		s:    source("other"),
+	}, {
+		name: "other namespace denied",
+		ctx:  deny,
+		s:    source("other"),
+		want: `sinks in namespace "other" are not permitted for sources in namespace "tenant": spec.sink.namespace`,
+	}, {
+		name: "status update of a denied sink",
+		ctx:  apis.WithinSubResourceUpdate(deny, source("other"), "status"),
+		s:    source("other"),
+	}, {
+		name: "uri of a service in the same namespace",
+		ctx:  deny,
+		s:    withSinkURI(source(""), "sink.tenant.svc.cluster.local"),
+	}, {
+		name: "uri of a service in another namespace denied",
+		ctx:  deny,
+		s:    withSinkURI(source(""), "sink.other.svc.cluster.local"),
+		want: `sinks in namespace "other" are not permitted for sources in namespace "tenant": spec.sink.uri`,
+	}, {
+		name: "uri outside the cluster",
+		ctx:  deny,
+		s:    withSinkURI(source(""), "example.com"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.s.Validate(test.ctx).Error(); got != test.want {
+				t.Errorf("Validate() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
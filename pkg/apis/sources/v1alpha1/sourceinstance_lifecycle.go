@@ -0,0 +1,138 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// SourceInstanceConditionReady is true when the template of the
+	// SourceInstance resolved, it has a sink, and its pods are deployed.
+	SourceInstanceConditionReady = apis.ConditionReady
+
+	// SinkProvided is inherited from the base status.
+
+	// SourceInstanceConditionTemplateResolved is true when the template
+	// exists and the parameters of the SourceInstance are valid for it.
+	SourceInstanceConditionTemplateResolved apis.ConditionType = "TemplateResolved"
+
+	// SourceInstanceConditionDeployed is true when the Job of the
+	// SourceInstance succeeded, its CronJob exists, or its Deployment is
+	// available, depending on the mode of the template.
+	SourceInstanceConditionDeployed apis.ConditionType = "Deployed"
+)
+
+var sourceInstanceCondSet = apis.NewLivingConditionSet(
+	SourceConditionSinkProvided,
+	SourceInstanceConditionTemplateResolved,
+	SourceInstanceConditionDeployed,
+)
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (s *SourceInstance) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("SourceInstance")
+}
+
+func (s *SourceInstanceStatus) InitializeConditions() {
+	sourceInstanceCondSet.Manage(s).InitializeConditions()
+}
+
+func (s *SourceInstanceStatus) Ready() bool {
+	return sourceInstanceCondSet.Manage(s).IsHappy()
+}
+
+// MarkSink sets the conditions that the source has received a sink URI.
+func (s *SourceInstanceStatus) MarkSink(uri string) {
+	s.BaseSourceStatus.MarkSink(sourceInstanceCondSet.Manage(s), uri)
+}
+
+func (s *SourceInstanceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkNoSink(sourceInstanceCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// MarkCleanupRunning sets the condition that the cleanup Job of the given
+// name is running.
+func (s *SourceInstanceStatus) MarkCleanupRunning(jobName string) {
+	s.BaseSourceStatus.MarkCleanupRunning(sourceInstanceCondSet.Manage(s), jobName)
+}
+
+// MarkCleanedUp sets the condition that the cleanup Job succeeded.
+func (s *SourceInstanceStatus) MarkCleanedUp() {
+	s.BaseSourceStatus.MarkCleanedUp(sourceInstanceCondSet.Manage(s))
+}
+
+// MarkCleanupFailed sets the condition that the cleanup Job failed.
+func (s *SourceInstanceStatus) MarkCleanupFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkCleanupFailed(sourceInstanceCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// MarkSinkReachable sets the condition that the sink responded to the last
+// probe, which took latency.
+func (s *SourceInstanceStatus) MarkSinkReachable(latency time.Duration) {
+	s.BaseSourceStatus.MarkSinkReachable(sourceInstanceCondSet.Manage(s), latency)
+}
+
+// MarkSinkUnreachable sets the condition that the last probe of the sink
+// failed.
+func (s *SourceInstanceStatus) MarkSinkUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	s.BaseSourceStatus.MarkSinkUnreachable(sourceInstanceCondSet.Manage(s), reason, messageFormat, messageA...)
+}
+
+// ClearSinkReachable removes the condition of the probes of the sink.
+func (s *SourceInstanceStatus) ClearSinkReachable() {
+	s.BaseSourceStatus.ClearSinkReachable(sourceInstanceCondSet.Manage(s))
+}
+
+// MarkChildNotOwned sets the condition that the existing kind of the given
+// name is not owned by the SourceInstance.
+func (s *SourceInstanceStatus) MarkChildNotOwned(kind, name string) {
+	s.BaseSourceStatus.MarkChildNotOwned(sourceInstanceCondSet.Manage(s), SourceInstanceConditionDeployed, kind, name)
+}
+
+// MarkChildOwned removes the condition that the child is not owned.
+func (s *SourceInstanceStatus) MarkChildOwned() {
+	s.BaseSourceStatus.MarkChildOwned(sourceInstanceCondSet.Manage(s))
+}
+
+// MarkTemplateResolved sets the condition that the template exists and the
+// parameters are valid for it, and records its mode.
+func (s *SourceInstanceStatus) MarkTemplateResolved(mode SourceTemplateMode) {
+	s.Mode = mode
+	sourceInstanceCondSet.Manage(s).MarkTrue(SourceInstanceConditionTemplateResolved)
+}
+
+// MarkTemplateNotResolved sets the condition that the template is missing,
+// or the parameters are not valid for it.
+func (s *SourceInstanceStatus) MarkTemplateNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	sourceInstanceCondSet.Manage(s).MarkFalse(SourceInstanceConditionTemplateResolved, reason, messageFormat, messageA...)
+}
+
+func (s *SourceInstanceStatus) MarkDeployed() {
+	sourceInstanceCondSet.Manage(s).MarkTrue(SourceInstanceConditionDeployed)
+}
+
+func (s *SourceInstanceStatus) MarkDeploying(reason, messageFormat string, messageA ...interface{}) {
+	sourceInstanceCondSet.Manage(s).MarkUnknown(SourceInstanceConditionDeployed, reason, messageFormat, messageA...)
+}
+
+func (s *SourceInstanceStatus) MarkNotDeployed(reason, messageFormat string, messageA ...interface{}) {
+	sourceInstanceCondSet.Manage(s).MarkFalse(SourceInstanceConditionDeployed, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestSourceInstanceReady(t *testing.T) {
+	tests := []struct {
+		name string
+		body func(s *SourceInstanceStatus)
+		want bool
+	}{{
+		name: "initialize",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+		},
+		want: false,
+	}, {
+		name: "has sink and template but not deployed",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkTemplateResolved(SourceTemplateModeDeployment)
+			s.MarkDeploying("Deploying", "")
+		},
+		want: false,
+	}, {
+		name: "template not resolved",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkTemplateNotResolved("TemplateNotFound", "")
+			s.MarkDeployed() // left over from before the template went away
+		},
+		want: false,
+	}, {
+		name: "deployment failed",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkTemplateResolved(SourceTemplateModeJob)
+			s.MarkNotDeployed("JobFailed", "")
+		},
+		want: false,
+	}, {
+		name: "has sink, template and deployed",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkTemplateResolved(SourceTemplateModeJob)
+			s.MarkDeployed()
+		},
+		want: true,
+	}, {
+		name: "take sink away",
+		body: func(s *SourceInstanceStatus) {
+			s.InitializeConditions()
+			s.MarkSink("example.com")
+			s.MarkTemplateResolved(SourceTemplateModeJob)
+			s.MarkDeployed()
+			s.MarkNoSink("MarkNoSink", "")
+		},
+		want: false,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &SourceInstanceStatus{}
+			test.body(s)
+			if got := s.Ready(); got != test.want {
+				t.Errorf("Got %t, wanted %t", got, test.want)
+			}
+		})
+	}
+}
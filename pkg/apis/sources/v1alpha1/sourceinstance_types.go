@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceInstance is a source of a kind that a ClusterSourceTemplate
+// defines. It supplies the parameters of the template and a sink.
+type SourceInstance struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the SourceInstance (from the client).
+	// +required
+	Spec SourceInstanceSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the SourceInstance (from the controller).
+	// +optional
+	Status SourceInstanceStatus `json:"status,omitempty"`
+}
+
+// Check that SourceInstance can be validated and defaulted.
+var _ apis.Validatable = (*SourceInstance)(nil)
+var _ apis.Defaultable = (*SourceInstance)(nil)
+var _ kmeta.OwnerRefable = (*SourceInstance)(nil)
+
+// SourceInstanceSpec holds the desired state of the SourceInstance (from the client).
+type SourceInstanceSpec struct {
+	BaseSourceSpec `json:",inline"`
+
+	// Template is the name of the ClusterSourceTemplate to instantiate.
+	// +required
+	Template string `json:"template"`
+
+	// Parameters are the values of the parameters of the template, by name.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// SourceInstanceStatus communicates the observed state of the SourceInstance (from the controller).
+type SourceInstanceStatus struct {
+	BaseSourceStatus `json:",inline"`
+
+	// Mode is the mode of the template that the SourceInstance was last
+	// deployed in.
+	// +optional
+	Mode SourceTemplateMode `json:"mode,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceInstanceList is a list of SourceInstance resources
+type SourceInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SourceInstance `json:"items"`
+}
+
+func (s *SourceInstance) GetSink() apisv1alpha1.Destination {
+	return s.Spec.Sink
+}
+
+func (s *SourceInstance) GetCleanup() *SourceCleanup {
+	return s.Spec.Cleanup
+}
+
+func (s *SourceInstance) GetOutputFormat() OutputFormatType {
+	return s.Spec.OutputFormat
+}
+
+func (s *SourceInstance) GetEventTypes() []SourceEventType {
+	return s.Spec.EventTypes
+}
+
+func (s *SourceInstance) GetStatus() SourceStatus {
+	return &s.Status
+}
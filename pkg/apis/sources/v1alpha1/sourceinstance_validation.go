@@ -0,0 +1,34 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate implements apis.Validatable. The parameters are validated
+// against the template by the controller, since the template may change.
+func (s *SourceInstance) Validate(ctx context.Context) *apis.FieldError {
+	ctx = apis.WithinParent(ctx, s.ObjectMeta)
+	errs := s.Spec.BaseSourceSpec.Validate(ctx).ViaField("spec")
+	if s.Spec.Template == "" {
+		errs = errs.Also(apis.ErrMissingField("spec.template"))
+	}
+	return errs.Also(validateQuotas(ctx, "SourceInstance", nil))
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// SourceQuotaConditionReady is true when the usage in the status of the
+	// SourceQuota has been counted.
+	SourceQuotaConditionReady = apis.ConditionReady
+
+	// SourceQuotaConditionExceeded is true when the sources of the namespace
+	// use more than the SourceQuota allows, which sources created at the
+	// same time can make them do. It doesn't affect readiness.
+	SourceQuotaConditionExceeded apis.ConditionType = "Exceeded"
+
+	// QuotaExceededReason is the reason of the Exceeded condition.
+	QuotaExceededReason = "QuotaExceeded"
+)
+
+var sourceQuotaCondSet = apis.NewLivingConditionSet()
+
+// GetGroupVersionKind implements kmeta.OwnerRefable
+func (q *SourceQuota) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("SourceQuota")
+}
+
+func (s *SourceQuotaStatus) InitializeConditions() {
+	sourceQuotaCondSet.Manage(s).InitializeConditions()
+}
+
+func (s *SourceQuotaStatus) Ready() bool {
+	return sourceQuotaCondSet.Manage(s).IsHappy()
+}
+
+// MarkUsage records what the sources of the namespace use.
+func (s *SourceQuotaStatus) MarkUsage(used SourceQuotaUsage) {
+	s.Used = used
+	sourceQuotaCondSet.Manage(s).MarkTrue(SourceQuotaConditionReady)
+}
+
+// MarkUsageUnknown sets the condition that the usage could not be counted.
+func (s *SourceQuotaStatus) MarkUsageUnknown(reason, messageFormat string, messageA ...interface{}) {
+	sourceQuotaCondSet.Manage(s).MarkUnknown(SourceQuotaConditionReady, reason, messageFormat, messageA...)
+}
+
+// MarkExceeded sets the Exceeded condition to what the usage exceeds of the
+// limits, as returned by quota.Exceeded.
+func (s *SourceQuotaStatus) MarkExceeded(exceeded []string) {
+	sourceQuotaCondSet.Manage(s).SetCondition(apis.Condition{
+		Type:     SourceQuotaConditionExceeded,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   QuotaExceededReason,
+		Message:  fmt.Sprintf("The namespace has %s", strings.Join(exceeded, ", ")),
+	})
+}
+
+// MarkWithinLimits removes the Exceeded condition.
+func (s *SourceQuotaStatus) MarkWithinLimits() {
+	sourceQuotaCondSet.Manage(s).ClearCondition(SourceQuotaConditionExceeded)
+}
+
+// IsExceeded reports whether the Exceeded condition is set.
+func (s *SourceQuotaStatus) IsExceeded() bool {
+	return s.GetCondition(SourceQuotaConditionExceeded).IsTrue()
+}
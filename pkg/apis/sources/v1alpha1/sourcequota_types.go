@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceQuota limits the sources of its namespace. The webhook refuses
+// sources that would exceed any SourceQuota of their namespace.
+type SourceQuota struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec holds the desired state of the SourceQuota (from the client).
+	// +required
+	Spec SourceQuotaSpec `json:"spec,omitempty"`
+
+	// Status communicates the observed state of the SourceQuota (from the controller).
+	// +optional
+	Status SourceQuotaStatus `json:"status,omitempty"`
+}
+
+// Check that SourceQuota can be validated and defaulted.
+var _ apis.Validatable = (*SourceQuota)(nil)
+var _ apis.Defaultable = (*SourceQuota)(nil)
+var _ kmeta.OwnerRefable = (*SourceQuota)(nil)
+
+// SourceQuotaSpec holds the desired state of the SourceQuota (from the client).
+type SourceQuotaSpec struct {
+	// Sources is the most sources of each kind, such as CronJobSource, that
+	// the namespace may have. Kinds that are not listed are not limited.
+	// +optional
+	Sources map[string]int32 `json:"sources,omitempty"`
+
+	// ActiveJobs is the most JobSources of the namespace whose current run
+	// has not finished.
+	// +optional
+	ActiveJobs *int32 `json:"activeJobs,omitempty"`
+
+	// MinScheduleInterval is the shortest time that the schedule of a
+	// CronJobSource of the namespace may leave between two ticks.
+	// +optional
+	MinScheduleInterval *metav1.Duration `json:"minScheduleInterval,omitempty"`
+}
+
+// SourceQuotaStatus communicates the observed state of the SourceQuota (from the controller).
+type SourceQuotaStatus struct {
+	duckv1beta1.Status `json:",inline"`
+
+	// Used is what the sources of the namespace currently use.
+	// +optional
+	Used SourceQuotaUsage `json:"used,omitempty"`
+}
+
+// SourceQuotaUsage is what the sources of a namespace use of a SourceQuota.
+type SourceQuotaUsage struct {
+	// Sources is the number of sources of each kind.
+	// +optional
+	Sources map[string]int32 `json:"sources,omitempty"`
+
+	// ActiveJobs is the number of JobSources whose current run has not
+	// finished.
+	// +optional
+	ActiveJobs int32 `json:"activeJobs"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceQuotaList is a list of SourceQuota resources
+type SourceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SourceQuota `json:"items"`
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// QuotaSourceKinds are the kinds of sources that SourceQuotas limit.
+var QuotaSourceKinds = []string{"JobSource", "CronJobSource", "ServiceSource", "SourceInstance"}
+
+// Validate implements apis.Validatable
+func (q *SourceQuota) Validate(ctx context.Context) *apis.FieldError {
+	return q.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate implements apis.Validatable
+func (s *SourceQuotaSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	for kind, limit := range s.Sources {
+		if !isQuotaSourceKind(kind) {
+			errs = errs.Also(apis.ErrInvalidKeyName(kind, "sources", fmt.Sprintf("must be one of %v", QuotaSourceKinds)))
+		} else if limit < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(limit, apis.CurrentField).ViaFieldKey("sources", kind))
+		}
+	}
+
+	if s.ActiveJobs != nil && *s.ActiveJobs < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*s.ActiveJobs, "activeJobs"))
+	}
+
+	if s.MinScheduleInterval != nil && s.MinScheduleInterval.Duration <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.MinScheduleInterval.Duration.String(), "minScheduleInterval"))
+	}
+
+	return errs
+}
+
+func isQuotaSourceKind(kind string) bool {
+	for _, k := range QuotaSourceKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+type quotaUsageKey struct{}
+
+// QuotaUsage looks up the SourceQuotas of namespaces and what the sources
+// in them use. Implementations may count from caches, so sources created at
+// the same time may together exceed a quota.
+type QuotaUsage interface {
+	// Quotas returns the SourceQuotas of the namespace.
+	Quotas(namespace string) ([]*SourceQuota, error)
+
+	// Usage returns what the sources of the namespace use.
+	Usage(namespace string) (SourceQuotaUsage, error)
+}
+
+// WithQuotaUsage attaches the QuotaUsage to the context, which makes the
+// Validate of sources enforce the SourceQuotas of their namespace.
+func WithQuotaUsage(ctx context.Context, u QuotaUsage) context.Context {
+	return context.WithValue(ctx, quotaUsageKey{}, u)
+}
+
+// GetQuotaUsage returns the QuotaUsage of the context, or nil if it has
+// none.
+func GetQuotaUsage(ctx context.Context) QuotaUsage {
+	u, _ := ctx.Value(quotaUsageKey{}).(QuotaUsage)
+	return u
+}
+
+// validateQuotas checks the creation or update of a source of the given kind
+// against the SourceQuotas of its namespace, if the context has a
+// QuotaUsage. A source being created must fit in the limit of its kind;
+// check, if not nil, checks the other limits.
+func validateQuotas(ctx context.Context, kind string, check func(*SourceQuota, SourceQuotaUsage) *apis.FieldError) *apis.FieldError {
+	qu := GetQuotaUsage(ctx)
+	if qu == nil || apis.IsInStatusUpdate(ctx) || (!apis.IsInCreate(ctx) && check == nil) {
+		return nil
+	}
+
+	namespace := apis.ParentMeta(ctx).Namespace
+	quotas, err := qu.Quotas(namespace)
+	if err != nil {
+		return &apis.FieldError{
+			Message: "Failed to get the SourceQuotas of the namespace",
+			Paths:   []string{"metadata.namespace"},
+			Details: err.Error(),
+		}
+	}
+	if len(quotas) == 0 {
+		return nil
+	}
+	usage, err := qu.Usage(namespace)
+	if err != nil {
+		return &apis.FieldError{
+			Message: "Failed to count the sources of the namespace",
+			Paths:   []string{"metadata.namespace"},
+			Details: err.Error(),
+		}
+	}
+
+	var errs *apis.FieldError
+	for _, q := range quotas {
+		if limit, ok := q.Spec.Sources[kind]; ok && apis.IsInCreate(ctx) && usage.Sources[kind] >= limit {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("Exceeded SourceQuota %q: the namespace has %d of at most %d %ss", q.Name, usage.Sources[kind], limit, kind),
+				Paths:   []string{"metadata.namespace"},
+			})
+		}
+		if check != nil {
+			errs = errs.Also(check(q, usage))
+		}
+	}
+	return errs
+}
+
+// activeJobsQuota checks that a JobSource that starts a run fits in the
+// limit of active JobSources.
+func activeJobsQuota(q *SourceQuota, usage SourceQuotaUsage) *apis.FieldError {
+	if q.Spec.ActiveJobs == nil || usage.ActiveJobs < *q.Spec.ActiveJobs {
+		return nil
+	}
+	return &apis.FieldError{
+		Message: fmt.Sprintf("Exceeded SourceQuota %q: the namespace has %d of at most %d active JobSources", q.Name, usage.ActiveJobs, *q.Spec.ActiveJobs),
+		Paths:   []string{"metadata.namespace"},
+	}
+}
+
+// scheduleQuota returns a check that the given interval of a schedule is
+// not shorter than the minimum.
+func scheduleQuota(interval time.Duration) func(*SourceQuota, SourceQuotaUsage) *apis.FieldError {
+	return func(q *SourceQuota, _ SourceQuotaUsage) *apis.FieldError {
+		if q.Spec.MinScheduleInterval == nil || interval == 0 || interval >= q.Spec.MinScheduleInterval.Duration {
+			return nil
+		}
+		return &apis.FieldError{
+			Message: fmt.Sprintf("Exceeded SourceQuota %q: the schedule has ticks %v apart, less than the minimum of %v", q.Name, interval, q.Spec.MinScheduleInterval.Duration),
+			Paths:   []string{"spec.schedule"},
+		}
+	}
+}
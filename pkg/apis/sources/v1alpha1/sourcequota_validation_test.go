@@ -0,0 +1,220 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"knative.dev/pkg/apis"
+	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+	"knative.dev/pkg/ptr"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSourceQuotaValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		spec SourceQuotaSpec
+		want string
+	}{{
+		name: "empty",
+	}, {
+		name: "all limits",
+		spec: SourceQuotaSpec{
+			Sources:             map[string]int32{"CronJobSource": 10, "JobSource": 0},
+			ActiveJobs:          ptr.Int32(3),
+			MinScheduleInterval: &metav1.Duration{Duration: 5 * time.Minute},
+		},
+	}, {
+		name: "unknown kind",
+		spec: SourceQuotaSpec{Sources: map[string]int32{"Pod": 10}},
+		want: "invalid key name \"Pod\": spec.sources\nmust be one of [JobSource CronJobSource ServiceSource SourceInstance]",
+	}, {
+		name: "negative source limit",
+		spec: SourceQuotaSpec{Sources: map[string]int32{"JobSource": -1}},
+		want: "invalid value: -1: spec.sources[JobSource]",
+	}, {
+		name: "negative active jobs",
+		spec: SourceQuotaSpec{ActiveJobs: ptr.Int32(-1)},
+		want: "invalid value: -1: spec.activeJobs",
+	}, {
+		name: "zero interval",
+		spec: SourceQuotaSpec{MinScheduleInterval: &metav1.Duration{}},
+		want: "invalid value: 0s: spec.minScheduleInterval",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := &SourceQuota{Spec: test.spec}
+			if got := q.Validate(context.Background()).Error(); got != test.want {
+				t.Errorf("Validate() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
+
+// fakeQuotaUsage is a QuotaUsage with fixed quotas and usage.
+type fakeQuotaUsage struct {
+	quotas []*SourceQuota
+	used   SourceQuotaUsage
+	err    error
+}
+
+func (f *fakeQuotaUsage) Quotas(namespace string) ([]*SourceQuota, error) {
+	return f.quotas, nil
+}
+
+func (f *fakeQuotaUsage) Usage(namespace string) (SourceQuotaUsage, error) {
+	return f.used, f.err
+}
+
+func TestSourceQuotaEnforcement(t *testing.T) {
+	usage := &fakeQuotaUsage{
+		quotas: []*SourceQuota{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "limits"},
+			Spec: SourceQuotaSpec{
+				Sources:             map[string]int32{"JobSource": 5, "SourceInstance": 2},
+				ActiveJobs:          ptr.Int32(2),
+				MinScheduleInterval: &metav1.Duration{Duration: 5 * time.Minute},
+			},
+		}},
+		used: SourceQuotaUsage{
+			Sources:    map[string]int32{"JobSource": 3, "SourceInstance": 2, "CronJobSource": 7},
+			ActiveJobs: 2,
+		},
+	}
+	within := WithQuotaUsage(context.Background(), usage)
+
+	sink := apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+		Name:       "default",
+	}}
+	podSpec := corev1.PodSpec{Containers: []corev1.Container{{Image: "example.com/source"}}}
+
+	jobSource := func(runGeneration int64, finished bool) *JobSource {
+		js := &JobSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "job"},
+			Spec: JobSourceSpec{
+				BaseSourceSpec: BaseSourceSpec{OutputFormat: OutputFormatBinary, Sink: sink},
+				JobSpec:        batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: podSpec}},
+				RunGeneration:  runGeneration,
+			},
+		}
+		if finished {
+			js.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		}
+		return js
+	}
+	cronJobSource := func(schedule string) *CronJobSource {
+		return &CronJobSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "cron"},
+			Spec: CronJobSourceSpec{
+				BaseSourceSpec: BaseSourceSpec{OutputFormat: OutputFormatBinary, Sink: sink},
+				CronJobSpec: batchv1beta1.CronJobSpec{
+					Schedule: schedule,
+					JobTemplate: batchv1beta1.JobTemplateSpec{Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{Spec: podSpec},
+					}},
+				},
+			},
+		}
+	}
+	instance := &SourceInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "instance"},
+		Spec: SourceInstanceSpec{
+			BaseSourceSpec: BaseSourceSpec{OutputFormat: OutputFormatBinary, Sink: sink},
+			Template:       "template",
+		},
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		s    apis.Validatable
+		want string
+	}{{
+		name: "without quota usage",
+		ctx:  apis.WithinCreate(context.Background()),
+		s:    jobSource(0, false),
+	}, {
+		name: "job source beyond the active jobs",
+		ctx:  apis.WithinCreate(within),
+		s:    jobSource(0, false),
+		want: `Exceeded SourceQuota "limits": the namespace has 2 of at most 2 active JobSources: metadata.namespace`,
+	}, {
+		name: "update of an active job source",
+		ctx:  apis.WithinUpdate(within, jobSource(1, false)),
+		s:    jobSource(2, false),
+	}, {
+		name: "new run of a finished job source",
+		ctx:  apis.WithinUpdate(within, jobSource(1, true)),
+		s:    jobSource(2, true),
+		want: `Exceeded SourceQuota "limits": the namespace has 2 of at most 2 active JobSources: metadata.namespace`,
+	}, {
+		name: "status update of a finished job source",
+		ctx:  apis.WithinSubResourceUpdate(within, jobSource(1, true), "status"),
+		s:    jobSource(2, true),
+	}, {
+		name: "source instances beyond their limit",
+		ctx:  apis.WithinCreate(within),
+		s:    instance,
+		want: `Exceeded SourceQuota "limits": the namespace has 2 of at most 2 SourceInstances: metadata.namespace`,
+	}, {
+		name: "update of a source instance",
+		ctx:  apis.WithinUpdate(within, instance),
+		s:    instance,
+	}, {
+		name: "cron job source with an unlimited kind",
+		ctx:  apis.WithinCreate(within),
+		s:    cronJobSource("*/5 * * * *"),
+	}, {
+		name: "schedule too frequent",
+		ctx:  apis.WithinCreate(within),
+		s:    cronJobSource("* * * * *"),
+		want: `Exceeded SourceQuota "limits": the schedule has ticks 1m0s apart, less than the minimum of 5m0s: spec.schedule`,
+	}, {
+		name: "frequent schedule that did not change",
+		ctx:  apis.WithinUpdate(within, cronJobSource("* * * * *")),
+		s:    cronJobSource("* * * * *"),
+	}, {
+		name: "changed to a frequent schedule",
+		ctx:  apis.WithinUpdate(within, cronJobSource("0 * * * *")),
+		s:    cronJobSource("0,1 * * * *"),
+		want: `Exceeded SourceQuota "limits": the schedule has ticks 1m0s apart, less than the minimum of 5m0s: spec.schedule`,
+	}, {
+		name: "usage not counted",
+		ctx:  apis.WithinCreate(WithQuotaUsage(context.Background(), &fakeQuotaUsage{quotas: usage.quotas, err: errors.New("boom")})),
+		s:    cronJobSource("*/5 * * * *"),
+		want: "Failed to count the sources of the namespace: metadata.namespace\nboom",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.s.Validate(test.ctx).Error(); got != test.want {
+				t.Errorf("Validate() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
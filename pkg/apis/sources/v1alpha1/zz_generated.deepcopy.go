@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apis "knative.dev/pkg/apis"
 	v1beta1 "knative.dev/pkg/apis/duck/v1beta1"
@@ -35,6 +36,16 @@ func (in *BaseSourceSpec) DeepCopyInto(out *BaseSourceSpec) {
 		*out = new(v1beta1.CloudEventOverrides)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(SourceCleanup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]SourceEventType, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -52,6 +63,11 @@ func (in *BaseSourceSpec) DeepCopy() *BaseSourceSpec {
 func (in *BaseSourceStatus) DeepCopyInto(out *BaseSourceStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]SourceEventType, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -65,6 +81,90 @@ func (in *BaseSourceStatus) DeepCopy() *BaseSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSourceTemplate) DeepCopyInto(out *ClusterSourceTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSourceTemplate.
+func (in *ClusterSourceTemplate) DeepCopy() *ClusterSourceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSourceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSourceTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSourceTemplateList) DeepCopyInto(out *ClusterSourceTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSourceTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSourceTemplateList.
+func (in *ClusterSourceTemplateList) DeepCopy() *ClusterSourceTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSourceTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSourceTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSourceTemplateSpec) DeepCopyInto(out *ClusterSourceTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]SourceTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSourceTemplateSpec.
+func (in *ClusterSourceTemplateSpec) DeepCopy() *ClusterSourceTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSourceTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CronJobSource) DeepCopyInto(out *CronJobSource) {
 	*out = *in
@@ -126,11 +226,66 @@ func (in *CronJobSourceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronJobSourceRun) DeepCopyInto(out *CronJobSourceRun) {
+	*out = *in
+	if in.ScheduledTime != nil {
+		in, out := &in.ScheduledTime, &out.ScheduledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronJobSourceRun.
+func (in *CronJobSourceRun) DeepCopy() *CronJobSourceRun {
+	if in == nil {
+		return nil
+	}
+	out := new(CronJobSourceRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CronJobSourceSend) DeepCopyInto(out *CronJobSourceSend) {
+	*out = *in
+	in.ScheduledTime.DeepCopyInto(&out.ScheduledTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CronJobSourceSend.
+func (in *CronJobSourceSend) DeepCopy() *CronJobSourceSend {
+	if in == nil {
+		return nil
+	}
+	out := new(CronJobSourceSend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CronJobSourceSpec) DeepCopyInto(out *CronJobSourceSpec) {
 	*out = *in
 	in.BaseSourceSpec.DeepCopyInto(&out.BaseSourceSpec)
 	in.CronJobSpec.DeepCopyInto(&out.CronJobSpec)
+	if in.RecentRunsLimit != nil {
+		in, out := &in.RecentRunsLimit, &out.RecentRunsLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -149,6 +304,26 @@ func (in *CronJobSourceStatus) DeepCopyInto(out *CronJobSourceStatus) {
 	*out = *in
 	in.BaseSourceStatus.DeepCopyInto(&out.BaseSourceStatus)
 	in.CronJobStatus.DeepCopyInto(&out.CronJobStatus)
+	if in.RecentRuns != nil {
+		in, out := &in.RecentRuns, &out.RecentRuns
+		*out = make([]CronJobSourceRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSend != nil {
+		in, out := &in.LastSend, &out.LastSend
+		*out = new(CronJobSourceSend)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -223,11 +398,41 @@ func (in *JobSourceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobSourceRun) DeepCopyInto(out *JobSourceRun) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobSourceRun.
+func (in *JobSourceRun) DeepCopy() *JobSourceRun {
+	if in == nil {
+		return nil
+	}
+	out := new(JobSourceRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobSourceSpec) DeepCopyInto(out *JobSourceSpec) {
 	*out = *in
 	in.BaseSourceSpec.DeepCopyInto(&out.BaseSourceSpec)
 	in.JobSpec.DeepCopyInto(&out.JobSpec)
+	if in.SourceTTLSecondsAfterFinished != nil {
+		in, out := &in.SourceTTLSecondsAfterFinished, &out.SourceTTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RunHistoryLimit != nil {
+		in, out := &in.RunHistoryLimit, &out.RunHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -245,6 +450,17 @@ func (in *JobSourceSpec) DeepCopy() *JobSourceSpec {
 func (in *JobSourceStatus) DeepCopyInto(out *JobSourceStatus) {
 	*out = *in
 	in.BaseSourceStatus.DeepCopyInto(&out.BaseSourceStatus)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RunHistory != nil {
+		in, out := &in.RunHistory, &out.RunHistory
+		*out = make([]JobSourceRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -387,6 +603,146 @@ func (in *ServiceSourceStatus) DeepCopy() *ServiceSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceCleanup) DeepCopyInto(out *SourceCleanup) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceCleanup.
+func (in *SourceCleanup) DeepCopy() *SourceCleanup {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceCleanup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceEventType) DeepCopyInto(out *SourceEventType) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceEventType.
+func (in *SourceEventType) DeepCopy() *SourceEventType {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceEventType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInstance) DeepCopyInto(out *SourceInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInstance.
+func (in *SourceInstance) DeepCopy() *SourceInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInstanceList) DeepCopyInto(out *SourceInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInstanceList.
+func (in *SourceInstanceList) DeepCopy() *SourceInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInstanceSpec) DeepCopyInto(out *SourceInstanceSpec) {
+	*out = *in
+	in.BaseSourceSpec.DeepCopyInto(&out.BaseSourceSpec)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInstanceSpec.
+func (in *SourceInstanceSpec) DeepCopy() *SourceInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceInstanceStatus) DeepCopyInto(out *SourceInstanceStatus) {
+	*out = *in
+	in.BaseSourceStatus.DeepCopyInto(&out.BaseSourceStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceInstanceStatus.
+func (in *SourceInstanceStatus) DeepCopy() *SourceInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourcePod) DeepCopyInto(out *SourcePod) {
 	*out = *in
@@ -414,3 +770,159 @@ func (in *SourcePod) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceQuota) DeepCopyInto(out *SourceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceQuota.
+func (in *SourceQuota) DeepCopy() *SourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceQuotaList) DeepCopyInto(out *SourceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceQuotaList.
+func (in *SourceQuotaList) DeepCopy() *SourceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceQuotaSpec) DeepCopyInto(out *SourceQuotaSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveJobs != nil {
+		in, out := &in.ActiveJobs, &out.ActiveJobs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinScheduleInterval != nil {
+		in, out := &in.MinScheduleInterval, &out.MinScheduleInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceQuotaSpec.
+func (in *SourceQuotaSpec) DeepCopy() *SourceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceQuotaStatus) DeepCopyInto(out *SourceQuotaStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	in.Used.DeepCopyInto(&out.Used)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceQuotaStatus.
+func (in *SourceQuotaStatus) DeepCopy() *SourceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceQuotaUsage) DeepCopyInto(out *SourceQuotaUsage) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceQuotaUsage.
+func (in *SourceQuotaUsage) DeepCopy() *SourceQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplateParameter) DeepCopyInto(out *SourceTemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplateParameter.
+func (in *SourceTemplateParameter) DeepCopy() *SourceTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -28,6 +28,10 @@ type FakeSourcesV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSourcesV1alpha1) ClusterSourceTemplates() v1alpha1.ClusterSourceTemplateInterface {
+	return &FakeClusterSourceTemplates{c}
+}
+
 func (c *FakeSourcesV1alpha1) CronJobSources(namespace string) v1alpha1.CronJobSourceInterface {
 	return &FakeCronJobSources{c, namespace}
 }
@@ -40,6 +44,14 @@ func (c *FakeSourcesV1alpha1) ServiceSources(namespace string) v1alpha1.ServiceS
 	return &FakeServiceSources{c, namespace}
 }
 
+func (c *FakeSourcesV1alpha1) SourceInstances(namespace string) v1alpha1.SourceInstanceInterface {
+	return &FakeSourceInstances{c, namespace}
+}
+
+func (c *FakeSourcesV1alpha1) SourceQuotas(namespace string) v1alpha1.SourceQuotaInterface {
+	return &FakeSourceQuotas{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSourcesV1alpha1) RESTClient() rest.Interface {
@@ -18,8 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+type ClusterSourceTemplateExpansion interface{}
+
 type CronJobSourceExpansion interface{}
 
 type JobSourceExpansion interface{}
 
 type ServiceSourceExpansion interface{}
+
+type SourceInstanceExpansion interface{}
+
+type SourceQuotaExpansion interface{}
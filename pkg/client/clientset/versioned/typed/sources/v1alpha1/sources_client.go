@@ -27,9 +27,12 @@ import (
 
 type SourcesV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterSourceTemplatesGetter
 	CronJobSourcesGetter
 	JobSourcesGetter
 	ServiceSourcesGetter
+	SourceInstancesGetter
+	SourceQuotasGetter
 }
 
 // SourcesV1alpha1Client is used to interact with features provided by the sources.knative.dev group.
@@ -37,6 +40,10 @@ type SourcesV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SourcesV1alpha1Client) ClusterSourceTemplates() ClusterSourceTemplateInterface {
+	return newClusterSourceTemplates(c)
+}
+
 func (c *SourcesV1alpha1Client) CronJobSources(namespace string) CronJobSourceInterface {
 	return newCronJobSources(c, namespace)
 }
@@ -49,6 +56,14 @@ func (c *SourcesV1alpha1Client) ServiceSources(namespace string) ServiceSourceIn
 	return newServiceSources(c, namespace)
 }
 
+func (c *SourcesV1alpha1Client) SourceInstances(namespace string) SourceInstanceInterface {
+	return newSourceInstances(c, namespace)
+}
+
+func (c *SourcesV1alpha1Client) SourceQuotas(namespace string) SourceQuotaInterface {
+	return newSourceQuotas(c, namespace)
+}
+
 // NewForConfig creates a new SourcesV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*SourcesV1alpha1Client, error) {
 	config := *c
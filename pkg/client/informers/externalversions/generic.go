@@ -53,12 +53,18 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=sources.knative.dev, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("clustersourcetemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().ClusterSourceTemplates().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("cronjobsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().CronJobSources().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("jobsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().JobSources().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("servicesources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().ServiceSources().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("sourceinstances"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().SourceInstances().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("sourcequotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sources().V1alpha1().SourceQuotas().Informer()}, nil
 
 	}
 
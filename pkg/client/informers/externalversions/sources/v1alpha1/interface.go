@@ -24,12 +24,18 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterSourceTemplates returns a ClusterSourceTemplateInformer.
+	ClusterSourceTemplates() ClusterSourceTemplateInformer
 	// CronJobSources returns a CronJobSourceInformer.
 	CronJobSources() CronJobSourceInformer
 	// JobSources returns a JobSourceInformer.
 	JobSources() JobSourceInformer
 	// ServiceSources returns a ServiceSourceInformer.
 	ServiceSources() ServiceSourceInformer
+	// SourceInstances returns a SourceInstanceInformer.
+	SourceInstances() SourceInstanceInformer
+	// SourceQuotas returns a SourceQuotaInformer.
+	SourceQuotas() SourceQuotaInformer
 }
 
 type version struct {
@@ -43,6 +49,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterSourceTemplates returns a ClusterSourceTemplateInformer.
+func (v *version) ClusterSourceTemplates() ClusterSourceTemplateInformer {
+	return &clusterSourceTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // CronJobSources returns a CronJobSourceInformer.
 func (v *version) CronJobSources() CronJobSourceInformer {
 	return &cronJobSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,3 +68,13 @@ func (v *version) JobSources() JobSourceInformer {
 func (v *version) ServiceSources() ServiceSourceInformer {
 	return &serviceSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SourceInstances returns a SourceInstanceInformer.
+func (v *version) SourceInstances() SourceInstanceInformer {
+	return &sourceInstanceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SourceQuotas returns a SourceQuotaInformer.
+func (v *version) SourceQuotas() SourceQuotaInformer {
+	return &sourceQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
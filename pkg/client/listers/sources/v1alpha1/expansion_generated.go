@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+// ClusterSourceTemplateListerExpansion allows custom methods to be added to
+// ClusterSourceTemplateLister.
+type ClusterSourceTemplateListerExpansion interface{}
+
 // CronJobSourceListerExpansion allows custom methods to be added to
 // CronJobSourceLister.
 type CronJobSourceListerExpansion interface{}
@@ -41,3 +45,19 @@ type ServiceSourceListerExpansion interface{}
 // ServiceSourceNamespaceListerExpansion allows custom methods to be added to
 // ServiceSourceNamespaceLister.
 type ServiceSourceNamespaceListerExpansion interface{}
+
+// SourceInstanceListerExpansion allows custom methods to be added to
+// SourceInstanceLister.
+type SourceInstanceListerExpansion interface{}
+
+// SourceInstanceNamespaceListerExpansion allows custom methods to be added to
+// SourceInstanceNamespaceLister.
+type SourceInstanceNamespaceListerExpansion interface{}
+
+// SourceQuotaListerExpansion allows custom methods to be added to
+// SourceQuotaLister.
+type SourceQuotaListerExpansion interface{}
+
+// SourceQuotaNamespaceListerExpansion allows custom methods to be added to
+// SourceQuotaNamespaceLister.
+type SourceQuotaNamespaceListerExpansion interface{}
@@ -18,8 +18,10 @@ package cloudeventclient
 import (
 	"fmt"
 	gohttp "net/http"
+	"os"
 
 	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/cloudevents/sdk-go/pkg/cloudevents/client"
 	"github.com/cloudevents/sdk-go/pkg/cloudevents/transport/http"
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	"go.opencensus.io/plugin/ochttp"
@@ -27,7 +29,12 @@ import (
 	"knative.dev/pkg/tracing"
 )
 
+// SourceEnvVar is the environment variable in which the controller gives
+// the containers of a source the canonical CloudEvents source of its events.
+const SourceEnvVar = "K_CE_SOURCE"
+
 // New creates a default client using one of the two source OutputFormatTypes.
+// Events sent without a source get the one in SourceEnvVar, if it is set.
 func New(format v1alpha1.OutputFormatType, target ...string) (cloudevents.Client, error) {
 	var tOpts []http.Option
 	switch format {
@@ -56,13 +63,28 @@ func New(format v1alpha1.OutputFormatType, target ...string) (cloudevents.Client
 	}
 
 	// Use the transport to make a new CloudEvents client.
-	c, err := cloudevents.NewClient(t,
+	opts := []client.Option{
 		cloudevents.WithUUIDs(),
 		cloudevents.WithTimeNow(),
-	)
+	}
+	if source := os.Getenv(SourceEnvVar); source != "" {
+		opts = append(opts, cloudevents.WithEventDefaulter(DefaultSource(source)))
+	}
+	c, err := cloudevents.NewClient(t, opts...)
 
 	if err != nil {
 		return nil, err
 	}
 	return c, nil
 }
+
+// DefaultSource returns an EventDefaulter that sets the source of events
+// that have none.
+func DefaultSource(source string) client.EventDefaulter {
+	return func(event cloudevents.Event) cloudevents.Event {
+		if event.Context != nil && event.Source() == "" {
+			event.SetSource(source)
+		}
+		return event
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudeventclient
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+)
+
+func TestDefaultSource(t *testing.T) {
+	defaulter := DefaultSource("/apis/v1/namespaces/default/jobsources/steve")
+
+	event := cloudevents.NewEvent()
+	if got, want := defaulter(event).Source(), "/apis/v1/namespaces/default/jobsources/steve"; got != want {
+		t.Errorf("Source() = %q, want %q", got, want)
+	}
+
+	event.SetSource("http://example.com/")
+	if got, want := defaulter(event).Source(), "http://example.com/"; got != want {
+		t.Errorf("Source() = %q, want the source of the application %q", got, want)
+	}
+}
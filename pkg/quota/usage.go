@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota counts what the sources of namespaces use of their
+// SourceQuotas, for the webhook that enforces them and the controller that
+// reports them.
+package quota
+
+import (
+	"fmt"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
+	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// liveMargin is how close the cached usage of a namespace must come to a
+// limit of one of its SourceQuotas for Usage to count from the API server.
+const liveMargin = 5
+
+// Usage counts what the sources of namespaces use of their SourceQuotas.
+// The webhook enforces SourceQuotas with it, and the controller reports it
+// in their status.
+//
+// Usage counts from the caches of informers, which lag behind the API
+// server. Sources created before the caches see the ones created just
+// earlier are each checked against the same count, so together they may
+// exceed a quota. With a Client, Usage counts from the API server instead
+// once the cached count comes within liveMargin of a limit, which leaves
+// only sources created at the same time to exceed it. The controller
+// reports SourceQuotas that were exceeded.
+type Usage struct {
+	QuotaLister          listers.SourceQuotaLister
+	JobSourceLister      listers.JobSourceLister
+	CronJobSourceLister  listers.CronJobSourceLister
+	ServiceSourceLister  listers.ServiceSourceLister
+	SourceInstanceLister listers.SourceInstanceLister
+
+	// Client lists the sources of namespaces that are close to a limit.
+	// +optional
+	Client clientset.Interface
+}
+
+var _ v1alpha1.QuotaUsage = (*Usage)(nil)
+
+// Quotas implements v1alpha1.QuotaUsage.
+func (u *Usage) Quotas(namespace string) ([]*v1alpha1.SourceQuota, error) {
+	return u.QuotaLister.SourceQuotas(namespace).List(labels.Everything())
+}
+
+// Usage implements v1alpha1.QuotaUsage. A JobSource is active until the
+// Job of its current run finishes.
+func (u *Usage) Usage(namespace string) (v1alpha1.SourceQuotaUsage, error) {
+	used, err := u.cachedUsage(namespace)
+	if err != nil || u.Client == nil {
+		return used, err
+	}
+	quotas, err := u.Quotas(namespace)
+	if err != nil {
+		return used, err
+	}
+	for _, q := range quotas {
+		if nearLimit(q, used) {
+			return u.liveUsage(namespace)
+		}
+	}
+	return used, nil
+}
+
+// cachedUsage counts the sources of the namespace in the caches.
+func (u *Usage) cachedUsage(namespace string) (v1alpha1.SourceQuotaUsage, error) {
+	used := v1alpha1.SourceQuotaUsage{Sources: make(map[string]int32, len(v1alpha1.QuotaSourceKinds))}
+
+	jobSources, err := u.JobSourceLister.JobSources(namespace).List(labels.Everything())
+	if err != nil {
+		return used, err
+	}
+	used.Sources["JobSource"] = int32(len(jobSources))
+	for _, js := range jobSources {
+		if !js.Status.IsFinished() {
+			used.ActiveJobs++
+		}
+	}
+
+	cronJobSources, err := u.CronJobSourceLister.CronJobSources(namespace).List(labels.Everything())
+	if err != nil {
+		return used, err
+	}
+	used.Sources["CronJobSource"] = int32(len(cronJobSources))
+
+	serviceSources, err := u.ServiceSourceLister.ServiceSources(namespace).List(labels.Everything())
+	if err != nil {
+		return used, err
+	}
+	used.Sources["ServiceSource"] = int32(len(serviceSources))
+
+	sourceInstances, err := u.SourceInstanceLister.SourceInstances(namespace).List(labels.Everything())
+	if err != nil {
+		return used, err
+	}
+	used.Sources["SourceInstance"] = int32(len(sourceInstances))
+
+	return used, nil
+}
+
+// liveUsage counts the sources of the namespace in the API server.
+func (u *Usage) liveUsage(namespace string) (v1alpha1.SourceQuotaUsage, error) {
+	used := v1alpha1.SourceQuotaUsage{Sources: make(map[string]int32, len(v1alpha1.QuotaSourceKinds))}
+	sources := u.Client.SourcesV1alpha1()
+
+	jobSources, err := sources.JobSources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return used, err
+	}
+	used.Sources["JobSource"] = int32(len(jobSources.Items))
+	for i := range jobSources.Items {
+		if !jobSources.Items[i].Status.IsFinished() {
+			used.ActiveJobs++
+		}
+	}
+
+	cronJobSources, err := sources.CronJobSources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return used, err
+	}
+	used.Sources["CronJobSource"] = int32(len(cronJobSources.Items))
+
+	serviceSources, err := sources.ServiceSources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return used, err
+	}
+	used.Sources["ServiceSource"] = int32(len(serviceSources.Items))
+
+	sourceInstances, err := sources.SourceInstances(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return used, err
+	}
+	used.Sources["SourceInstance"] = int32(len(sourceInstances.Items))
+
+	return used, nil
+}
+
+// nearLimit reports whether the usage comes within liveMargin of a limit of
+// the quota.
+func nearLimit(q *v1alpha1.SourceQuota, used v1alpha1.SourceQuotaUsage) bool {
+	for kind, limit := range q.Spec.Sources {
+		if used.Sources[kind] > limit-liveMargin {
+			return true
+		}
+	}
+	return q.Spec.ActiveJobs != nil && used.ActiveJobs > *q.Spec.ActiveJobs-liveMargin
+}
+
+// Exceeded returns what the usage exceeds of the limits of the quota, or
+// nil if it is within them.
+func Exceeded(q *v1alpha1.SourceQuota, used v1alpha1.SourceQuotaUsage) []string {
+	var exceeded []string
+	for _, kind := range v1alpha1.QuotaSourceKinds {
+		if limit, ok := q.Spec.Sources[kind]; ok && used.Sources[kind] > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%d of at most %d %ss", used.Sources[kind], limit, kind))
+		}
+	}
+	if q.Spec.ActiveJobs != nil && used.ActiveJobs > *q.Spec.ActiveJobs {
+		exceeded = append(exceeded, fmt.Sprintf("%d of at most %d active JobSources", used.ActiveJobs, *q.Spec.ActiveJobs))
+	}
+	return exceeded
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/client/clientset/versioned/fake"
+	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestUsageNearLimit(t *testing.T) {
+	jobSource := func(i int) *v1alpha1.JobSource {
+		return &v1alpha1.JobSource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("js-%d", i)}}
+	}
+	quota := func(limit int32) *v1alpha1.SourceQuota {
+		return &v1alpha1.SourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quota"},
+			Spec:       v1alpha1.SourceQuotaSpec{Sources: map[string]int32{"JobSource": limit}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		limit int32
+		want  int32
+	}{{
+		name:  "far from the limit counts the cache",
+		limit: 100,
+		want:  1,
+	}, {
+		name:  "close to the limit counts the API server",
+		limit: 5,
+		want:  3,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The cache has seen one of the three JobSources.
+			cached := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			cached.Add(jobSource(0))
+			quotas := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			quotas.Add(quota(test.limit))
+			empty := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+			u := &Usage{
+				QuotaLister:          listers.NewSourceQuotaLister(quotas),
+				JobSourceLister:      listers.NewJobSourceLister(cached),
+				CronJobSourceLister:  listers.NewCronJobSourceLister(empty),
+				ServiceSourceLister:  listers.NewServiceSourceLister(empty),
+				SourceInstanceLister: listers.NewSourceInstanceLister(empty),
+				Client:               fake.NewSimpleClientset([]runtime.Object{jobSource(0), jobSource(1), jobSource(2)}...),
+			}
+			used, err := u.Usage("default")
+			if err != nil {
+				t.Fatalf("Usage() = %v", err)
+			}
+			if got := used.Sources["JobSource"]; got != test.want {
+				t.Errorf("Usage() JobSources = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	activeJobs := int32(1)
+	q := &v1alpha1.SourceQuota{Spec: v1alpha1.SourceQuotaSpec{
+		Sources:    map[string]int32{"JobSource": 2, "CronJobSource": 1},
+		ActiveJobs: &activeJobs,
+	}}
+
+	tests := []struct {
+		name string
+		used v1alpha1.SourceQuotaUsage
+		want []string
+	}{{
+		name: "within the limits",
+		used: v1alpha1.SourceQuotaUsage{Sources: map[string]int32{"JobSource": 2, "CronJobSource": 1, "ServiceSource": 10}, ActiveJobs: 1},
+	}, {
+		name: "over the limits",
+		used: v1alpha1.SourceQuotaUsage{Sources: map[string]int32{"JobSource": 3, "CronJobSource": 2}, ActiveJobs: 2},
+		want: []string{"3 of at most 2 JobSources", "2 of at most 1 CronJobSources", "2 of at most 1 active JobSources"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if diff := cmp.Diff(test.want, Exceeded(q, test.used)); diff != "" {
+				t.Errorf("Exceeded() (-want, +got) = %s", diff)
+			}
+		})
+	}
+}
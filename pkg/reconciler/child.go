@@ -0,0 +1,151 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+// ChildObject is the object that a source runs its receive adapter in, such
+// as a Job or a Knative Service. It must have a Spec field.
+type ChildObject interface {
+	kmeta.Accessor
+}
+
+// ChildKind is what a kind of source with one child supplies to
+// ReconcileChild.
+type ChildKind interface {
+	// MakeChild returns the child that the source should have. It returns
+	// nil, after marking the status of the source, if there can be no
+	// child until the spec of the source changes.
+	MakeChild(source SourceObject) ChildObject
+
+	// GetChild returns the existing child of the given name, which must not
+	// be modified, or a NotFound error.
+	GetChild(namespace, name string) (ChildObject, error)
+
+	CreateChild(child ChildObject) (ChildObject, error)
+	UpdateChild(child ChildObject) (ChildObject, error)
+
+	// MarkChildFailed records on the source why it has no working child.
+	MarkChildFailed(source SourceObject, reason, messageFormat string, messageA ...interface{})
+
+	// PropagateStatus records the status of child on the source. changed is
+	// true if child was just created or updated.
+	PropagateStatus(source SourceObject, child ChildObject, changed bool) error
+}
+
+// ReconcileChild makes the child of source match what kind makes of it. It
+// creates the child if there is none, and updates it if the source owns it
+// and it was made from a different spec. It returns the child, or nil if
+// there is none yet. A child that exists but is not in the cache yet is
+// reported with ErrNotCached.
+func ReconcileChild(ctx context.Context, kind ChildKind, source SourceObject) (ChildObject, error) {
+	logger := logging.FromContext(ctx)
+
+	// The state we would like to see show up in K8s eventually
+	desired := kind.MakeChild(source)
+	if desired == nil {
+		return nil, nil
+	}
+	childKind := kindOf(desired)
+	SetSpecHash(desired, specOf(desired).Interface())
+
+	existing, err := kind.GetChild(desired.GetNamespace(), desired.GetName())
+	if apierrs.IsNotFound(err) {
+		child, err := kind.CreateChild(desired)
+		if apierrs.IsAlreadyExists(err) {
+			// The cache hasn't seen the child yet.
+			return nil, ErrNotCached(childKind, desired.GetName())
+		} else if err != nil {
+			kind.MarkChildFailed(source, "FailedCreate", "Failed to make %s. %v", childKind, err)
+			return nil, fmt.Errorf("failed to create %s: %s", childKind, err)
+		}
+		source.GetStatus().MarkChildOwned()
+		return child, kind.PropagateStatus(source, child, true)
+	} else if err != nil {
+		logger.Warnw("Failed get:", zap.Error(err))
+		kind.MarkChildFailed(source, "FailedGet", "%v", err)
+		return nil, fmt.Errorf("failed to get %s: %s", childKind, err)
+	}
+	// Don't modify the informer's copy.
+	existing = existing.DeepCopyObject().(ChildObject)
+
+	owns, adopted := Owns(source, existing)
+	if !owns {
+		source.GetStatus().MarkChildNotOwned(childKind, existing.GetName())
+		return nil, fmt.Errorf("%s %q does not own %s %q", source.GetGroupVersionKind().Kind, source.GetName(), childKind, existing.GetName())
+	}
+	source.GetStatus().MarkChildOwned()
+
+	// Update the child if it was made from a different spec. The API server
+	// fills in defaults, so the specs themselves are not compared.
+	if SpecHashChanged(existing, desired) || adopted {
+		specOf(existing).Set(specOf(desired))
+		SetSpecHash(existing, specOf(desired).Interface())
+		child, err := kind.UpdateChild(existing)
+		if err != nil {
+			return nil, err
+		}
+		logger.Desugar().Info(childKind+" updated.", zap.Any("child", child))
+		return child, kind.PropagateStatus(source, child, true)
+	}
+
+	return existing, kind.PropagateStatus(source, existing, false)
+}
+
+// SinkEnv returns the environment that tells the containers of a child
+// where to send events, and in which format.
+func SinkEnv(sinkURI string, format v1alpha1.OutputFormatType) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "K_SINK", Value: sinkURI},
+		{Name: "K_OUTPUT_FORMAT", Value: string(format)},
+	}
+}
+
+// SourceEnv returns the environment that tells the containers of a child
+// which source they run for: the canonical CloudEvents source of its events
+// and the kind, name, namespace and UID of the source.
+func SourceEnv(source SourceObject) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "K_CE_SOURCE", Value: CloudEventSource(source)},
+		{Name: "K_SOURCE_KIND", Value: source.GetGroupVersionKind().Kind},
+		{Name: "K_SOURCE_NAME", Value: source.GetName()},
+		{Name: "K_SOURCE_NAMESPACE", Value: source.GetNamespace()},
+		{Name: "K_SOURCE_UID", Value: string(source.GetUID())},
+	}
+}
+
+// specOf returns the Spec field of a child.
+func specOf(child ChildObject) reflect.Value {
+	return reflect.ValueOf(child).Elem().FieldByName("Spec")
+}
+
+// kindOf returns the name of the Go type of a child, which is its kind.
+func kindOf(child ChildObject) string {
+	return reflect.TypeOf(child).Elem().Name()
+}
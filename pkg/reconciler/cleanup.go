@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/eventing/pkg/utils"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	cleanupLabelKey = "sources.knative.dev/cleanup"
+)
+
+// MakeCleanupJob returns the Job that runs spec.cleanup of a source that is
+// being deleted. Its containers send events to sinkURI.
+func MakeCleanupJob(source SourceObject, sinkURI string) *batchv1.Job {
+	template := source.GetCleanup().Template.DeepCopy()
+	template.Labels = Labels(source, cleanupLabelKey)
+	template.Annotations = Annotations(source)
+	if template.Spec.RestartPolicy == "" {
+		template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	containers := []corev1.Container{}
+	for i, c := range template.Spec.Containers {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("cleanup%d", i)
+		}
+		c.Env = append(c.Env, SinkEnv(sinkURI, source.GetOutputFormat())...)
+		c.Env = append(c.Env, SourceEnv(source)...)
+		containers = append(containers, c)
+	}
+	template.Spec.Containers = containers
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            CleanupJobName(source),
+			Namespace:       source.GetNamespace(),
+			Labels:          Labels(source, cleanupLabelKey),
+			Annotations:     Annotations(source),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+		},
+		Spec: batchv1.JobSpec{
+			Template: *template,
+		},
+	}
+}
+
+// CleanupJobName returns the name of the cleanup Job of a source.
+func CleanupJobName(source metav1.Object) string {
+	return utils.GenerateFixedName(source, source.GetName()+"-cleanup-")
+}
+
+// reconcileFinalizer adds the cleanup finalizer to sources with
+// spec.cleanup, and removes it from sources without.
+func (r *SourceReconciler) reconcileFinalizer(source SourceObject) error {
+	want := source.GetCleanup() != nil
+	if want == hasCleanupFinalizer(source) {
+		return nil
+	}
+	finalizers := withoutCleanupFinalizer(source.GetFinalizers())
+	if want {
+		finalizers = append(finalizers, v1alpha1.CleanupFinalizer)
+	}
+	return r.patchFinalizers(source, finalizers)
+}
+
+// finalize runs the cleanup Job of a source that is being deleted, and
+// removes the cleanup finalizer once the Job has succeeded or timed out.
+func (r *SourceReconciler) finalize(ctx context.Context, source SourceObject) error {
+	if !hasCleanupFinalizer(source) {
+		return nil
+	}
+	cleanup := source.GetCleanup()
+	if cleanup == nil {
+		// spec.cleanup was removed after the source was deleted.
+		return r.patchFinalizers(source, withoutCleanupFinalizer(source.GetFinalizers()))
+	}
+	status := source.GetStatus()
+
+	job, err := r.JobLister.Jobs(source.GetNamespace()).Get(CleanupJobName(source))
+	if apierrs.IsNotFound(err) {
+		job, err = r.KubeClientSet.BatchV1().Jobs(source.GetNamespace()).Create(MakeCleanupJob(source, status.GetSinkURI()))
+		if err != nil {
+			status.MarkCleanupFailed("FailedCreate", "Failed to make cleanup Job. %v", err)
+			return fmt.Errorf("failed to create cleanup Job: %s", err)
+		}
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, "CleanupStarted", "Started cleanup Job %q", job.Name)
+	} else if err != nil {
+		return fmt.Errorf("failed to get cleanup Job: %s", err)
+	}
+
+	switch cond := jobFinishedCondition(job); {
+	case cond == nil:
+		status.MarkCleanupRunning(job.Name)
+	case cond.Type == batchv1.JobComplete:
+		status.MarkCleanedUp()
+		return r.patchFinalizers(source, withoutCleanupFinalizer(source.GetFinalizers()))
+	default:
+		// The source waits for the timeout all the same, to leave time to
+		// look into the failure.
+		status.MarkCleanupFailed("CleanupFailed", "Cleanup Job %q failed: %s", job.Name, cond.Message)
+	}
+
+	timeout := time.Duration(v1alpha1.DefaultCleanupTimeoutSeconds) * time.Second
+	if cleanup.TimeoutSeconds != nil {
+		timeout = time.Duration(*cleanup.TimeoutSeconds) * time.Second
+	}
+	started := job.CreationTimestamp.Time
+	if started.IsZero() {
+		// The Job was only just created.
+		started = r.Clock.Now()
+	}
+	if remaining := started.Add(timeout).Sub(r.Clock.Now()); remaining > 0 {
+		r.EnqueueAfter(source, remaining)
+		return nil
+	}
+
+	status.MarkCleanupFailed("CleanupTimedOut", "Cleanup Job %q did not succeed within %v", job.Name, timeout)
+	r.Recorder.Eventf(source, corev1.EventTypeWarning, "CleanupTimedOut", "Cleanup Job %q did not succeed within %v", job.Name, timeout)
+	return r.patchFinalizers(source, withoutCleanupFinalizer(source.GetFinalizers()))
+}
+
+// patchFinalizers sets the finalizers of source. The resource version makes
+// the patch fail rather than drop finalizers added since source was read.
+func (r *SourceReconciler) patchFinalizers(source SourceObject, finalizers []string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": source.GetResourceVersion(),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.Kind.SourceClient().Patch(source.GetNamespace(), source.GetName(), patch); err != nil {
+		return fmt.Errorf("failed to update finalizers: %s", err)
+	}
+	source.SetFinalizers(finalizers)
+	return nil
+}
+
+func hasCleanupFinalizer(source metav1.Object) bool {
+	for _, f := range source.GetFinalizers() {
+		if f == v1alpha1.CleanupFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutCleanupFinalizer(finalizers []string) []string {
+	without := []string{}
+	for _, f := range finalizers {
+		if f != v1alpha1.CleanupFinalizer {
+			without = append(without, f)
+		}
+	}
+	return without
+}
+
+// jobFinishedCondition returns the condition of the Job that says it
+// completed or failed, if any.
+func jobFinishedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return &c
+		}
+	}
+	return nil
+}
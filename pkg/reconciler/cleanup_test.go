@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestMakeCleanupJobEnv(t *testing.T) {
+	source := &v1alpha1.JobSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-jobsource", UID: "1234"},
+	}
+	source.Spec.OutputFormat = v1alpha1.OutputFormatStructured
+	source.Spec.Cleanup = &v1alpha1.SourceCleanup{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Image: "cleanup",
+					Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				}},
+			},
+		},
+	}
+
+	job := MakeCleanupJob(source, "http://sink")
+
+	// Cleanup containers get the same contract as the source's own.
+	want := []corev1.EnvVar{
+		{Name: "FOO", Value: "bar"},
+		{Name: "K_SINK", Value: "http://sink"},
+		{Name: "K_OUTPUT_FORMAT", Value: "structured"},
+		{Name: "K_CE_SOURCE", Value: "/apis/v1/namespaces/default/jobsources/my-jobsource"},
+		{Name: "K_SOURCE_KIND", Value: "JobSource"},
+		{Name: "K_SOURCE_NAME", Value: "my-jobsource"},
+		{Name: "K_SOURCE_NAMESPACE", Value: "default"},
+		{Name: "K_SOURCE_UID", Value: "1234"},
+	}
+	if diff := cmp.Diff(want, job.Spec.Template.Spec.Containers[0].Env); diff != "" {
+		t.Errorf("Unexpected env (-want, +got): %s", diff)
+	}
+}
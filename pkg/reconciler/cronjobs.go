@@ -0,0 +1,180 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"time"
+
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	batchv1beta1listers "k8s.io/client-go/listers/batch/v1beta1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	// BatchV1 serves CronJobs from Kubernetes 1.21 on.
+	BatchV1 = schema.GroupVersion{Group: "batch", Version: "v1"}
+
+	// BatchV1beta1 serves CronJobs up to Kubernetes 1.24.
+	BatchV1beta1 = batchv1beta1.SchemeGroupVersion
+)
+
+// CronJobClient writes and watches CronJobs in the version of the batch API
+// that the cluster serves them in. CronJobs are batchv1beta1.CronJob in
+// memory either way; the fields that sources use are the same in batch/v1.
+// CronJobs read through batch/v1 keep that apiVersion in their TypeMeta.
+type CronJobClient interface {
+	// GroupVersion is the version of the batch API in use.
+	GroupVersion() schema.GroupVersion
+
+	Create(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error)
+	Update(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error)
+	Delete(namespace, name string, options *metav1.DeleteOptions) error
+
+	// NewInformer returns an informer on the CronJobs in all namespaces.
+	NewInformer(resync time.Duration) cache.SharedIndexInformer
+
+	// Lister returns a lister on the indexer of an informer made by
+	// NewInformer.
+	Lister(indexer cache.Indexer) CronJobLister
+}
+
+// CronJobLister gets CronJobs from the cache of an informer. The CronJobs
+// it returns must not be modified.
+type CronJobLister interface {
+	Get(namespace, name string) (*batchv1beta1.CronJob, error)
+}
+
+// NewCronJobClient asks the cluster through discovery whether it serves
+// batch/v1 CronJobs, and falls back to batch/v1beta1 if it doesn't.
+func NewCronJobClient(discovery discovery.DiscoveryInterface, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) CronJobClient {
+	if ServesResource(discovery, BatchV1, "cronjobs") {
+		return &dynamicCronJobClient{resource: DynamicResource{
+			GVR:    BatchV1.WithResource("cronjobs"),
+			Kind:   "CronJob",
+			Client: dynamicClient,
+		}}
+	}
+	return &v1beta1CronJobClient{client: kubeClient}
+}
+
+// v1beta1CronJobClient uses the typed client for batch/v1beta1.
+type v1beta1CronJobClient struct {
+	client kubernetes.Interface
+}
+
+var _ CronJobClient = (*v1beta1CronJobClient)(nil)
+
+func (c *v1beta1CronJobClient) GroupVersion() schema.GroupVersion {
+	return BatchV1beta1
+}
+
+func (c *v1beta1CronJobClient) Create(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
+	return c.client.BatchV1beta1().CronJobs(cronjob.Namespace).Create(cronjob)
+}
+
+func (c *v1beta1CronJobClient) Update(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
+	return c.client.BatchV1beta1().CronJobs(cronjob.Namespace).Update(cronjob)
+}
+
+func (c *v1beta1CronJobClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return c.client.BatchV1beta1().CronJobs(namespace).Delete(name, options)
+}
+
+func (c *v1beta1CronJobClient) NewInformer(resync time.Duration) cache.SharedIndexInformer {
+	cronjobs := c.client.BatchV1beta1().CronJobs(metav1.NamespaceAll)
+	return NewListWatchInformer(
+		func(options metav1.ListOptions) (runtime.Object, error) {
+			return cronjobs.List(options)
+		},
+		func(options metav1.ListOptions) (watch.Interface, error) {
+			return cronjobs.Watch(options)
+		},
+		&batchv1beta1.CronJob{}, resync)
+}
+
+func (c *v1beta1CronJobClient) Lister(indexer cache.Indexer) CronJobLister {
+	return v1beta1CronJobLister{lister: batchv1beta1listers.NewCronJobLister(indexer)}
+}
+
+type v1beta1CronJobLister struct {
+	lister batchv1beta1listers.CronJobLister
+}
+
+func (l v1beta1CronJobLister) Get(namespace, name string) (*batchv1beta1.CronJob, error) {
+	return l.lister.CronJobs(namespace).Get(name)
+}
+
+// dynamicCronJobClient goes through the dynamic client, for versions of the
+// batch API that client-go has no types for.
+type dynamicCronJobClient struct {
+	resource DynamicResource
+}
+
+var _ CronJobClient = (*dynamicCronJobClient)(nil)
+
+func (c *dynamicCronJobClient) GroupVersion() schema.GroupVersion {
+	return c.resource.GVR.GroupVersion()
+}
+
+func (c *dynamicCronJobClient) Create(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
+	created := &batchv1beta1.CronJob{}
+	if err := c.resource.Create(cronjob.Namespace, cronjob, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *dynamicCronJobClient) Update(cronjob *batchv1beta1.CronJob) (*batchv1beta1.CronJob, error) {
+	updated := &batchv1beta1.CronJob{}
+	if err := c.resource.Update(cronjob.Namespace, cronjob, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *dynamicCronJobClient) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return c.resource.Delete(namespace, name, options)
+}
+
+func (c *dynamicCronJobClient) NewInformer(resync time.Duration) cache.SharedIndexInformer {
+	return c.resource.NewInformer(resync)
+}
+
+func (c *dynamicCronJobClient) Lister(indexer cache.Indexer) CronJobLister {
+	return dynamicCronJobLister{resource: &c.resource, indexer: indexer}
+}
+
+// dynamicCronJobLister converts the unstructured CronJobs in the cache.
+type dynamicCronJobLister struct {
+	resource *DynamicResource
+	indexer  cache.Indexer
+}
+
+func (l dynamicCronJobLister) Get(namespace, name string) (*batchv1beta1.CronJob, error) {
+	cronjob := &batchv1beta1.CronJob{}
+	if err := l.resource.Get(l.indexer, namespace, name, cronjob); err != nil {
+		return nil, err
+	}
+	return cronjob, nil
+}
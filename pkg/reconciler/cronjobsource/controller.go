@@ -18,19 +18,38 @@ package cronjobsource
 
 import (
 	"context"
+	"os"
+	"sync/atomic"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	cjsinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/cronjobsource"
 	"github.com/n3wscott/sources/pkg/reconciler"
-	cronjobinformer "knative.dev/pkg/client/injection/kube/informers/batch/v1beta1/cronjob"
+	jobinformer "knative.dev/pkg/client/injection/kube/informers/batch/v1/job"
 
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/system"
 )
 
 const (
 	controllerAgentName = "cronjobsource-controller"
+
+	// leaseDuration is how long the other replicas wait for a leader that
+	// stopped renewing its lock before taking over.
+	leaseDuration = 15 * time.Second
+	// renewDeadline is how long the leader keeps trying to renew its lock
+	// before it steps down.
+	renewDeadline = 10 * time.Second
+	// retryPeriod is how often the replicas try to acquire or renew the lock.
+	retryPeriod = 2 * time.Second
 )
 
 // NewController returns a new HPA reconcile controller.
@@ -40,22 +59,141 @@ func NewController(
 ) *controller.Impl {
 
 	cjsInformer := cjsinformer.Get(ctx)
-	cronJobInformer := cronjobinformer.Get(ctx)
+	jobInformer := jobinformer.Get(ctx)
 
 	r := &Reconciler{
-		Base:   reconciler.NewBase(ctx, "CronJobSource", cmw),
-		Lister: cjsInformer.Lister(),
+		Base:      reconciler.NewBase(ctx, "CronJobSource", cmw),
+		Lister:    cjsInformer.Lister(),
+		JobLister: jobInformer.Lister(),
+		Clock:     system.RealClock{},
+	}
+	r.CronJobs = reconciler.NewCronJobClient(r.KubeClientSet.Discovery(), r.KubeClientSet, r.DynamicClientSet)
+	sr := &reconciler.SourceReconciler{
+		Base:        r.Base,
+		Kind:        r,
+		JobLister:   r.JobLister,
+		Clock:       r.Clock,
+		ConfigStore: reconciler.NewConfigStore(ctx, cmw),
+	}
+	impl := controller.NewImpl(sr, r.Logger, "CronJobSources")
+	sr.EnqueueAfter = impl.EnqueueAfter
+	sr.WatchEventTypes(ctx, impl, "CronJobSource")
+
+	// Every replica schedules the events of inline CronJobSources, but only
+	// the leader sends them.
+	leader, err := runLeaderElection(ctx, r)
+	if err != nil {
+		r.Logger.Fatalw("Failed to set up leader election", zap.Error(err))
 	}
-	impl := controller.NewImpl(r, r.Logger, "CronJobSources")
+	r.Inline = newInlineSender(ctx, r.Logger, leader.IsLeader, impl.EnqueueKey)
 
 	r.Logger.Info("Setting up event handlers for CronJobSources")
 
 	cjsInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
-	cronJobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+	// Clusters serve CronJobs in batch/v1, batch/v1beta1 or both, so the
+	// informer is not one of the injected ones, which would wait forever on
+	// a version that isn't served.
+	r.Logger.Infof("Watching CronJobs in %s", r.CronJobs.GroupVersion())
+	cronJobInformer := r.CronJobs.NewInformer(controller.GetResyncPeriod(ctx))
+	cronJobInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("CronJobSource")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+	r.CronJobLister = r.CronJobs.Lister(cronJobInformer.GetIndexer())
+	if err := reconciler.StartInformer(ctx, cronJobInformer); err != nil {
+		r.Logger.Fatalw("Failed to sync the CronJob informer", zap.Error(err))
+	}
+
+	// Jobs are owned by the CronJob rather than the CronJobSource, so go
+	// through the CronJob to find the CronJobSource to enqueue.
+	jobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(r.CronJobs.GroupVersion().WithKind("CronJob")),
+		Handler: controller.HandleAll(func(obj interface{}) {
+			object, err := kmeta.DeletionHandlingAccessor(obj)
+			if err != nil {
+				return
+			}
+			owner := metav1.GetControllerOf(object)
+			cronjob, err := r.CronJobLister.Get(object.GetNamespace(), owner.Name)
+			if err != nil {
+				return
+			}
+			impl.EnqueueControllerOf(cronjob)
+		}),
+	})
+
+	// Cleanup Jobs run while CronJobSources are deleted.
+	jobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("CronJobSource")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
 	return impl
 }
+
+// leader tracks whether this replica currently leads.
+type leader struct {
+	leading int32
+}
+
+// IsLeader returns true between the start and the end of this replica's
+// leadership.
+func (l *leader) IsLeader() bool {
+	return atomic.LoadInt32(&l.leading) == 1
+}
+
+func (l *leader) set(leading bool) {
+	var v int32
+	if leading {
+		v = 1
+	}
+	atomic.StoreInt32(&l.leading, v)
+}
+
+// runLeaderElection competes with the other replicas for the
+// cronjobsource-controller lock in the system namespace until ctx is done.
+func runLeaderElection(ctx context.Context, r *Reconciler) (*leader, error) {
+	lock, err := resourcelock.New(resourcelock.ConfigMapsResourceLock,
+		system.Namespace(), controllerAgentName, r.KubeClientSet.CoreV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      identity(),
+			EventRecorder: r.Recorder,
+		})
+	if err != nil {
+		return nil, err
+	}
+	l := &leader{}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				r.Logger.Info("Started leading")
+				l.set(true)
+			},
+			OnStoppedLeading: func() {
+				r.Logger.Info("Stopped leading")
+				l.set(false)
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Run returns when leadership is lost, after which this replica
+	// competes again.
+	go wait.Until(func() { elector.Run(ctx) }, retryPeriod, ctx.Done())
+	return l, nil
+}
+
+// identity tells this replica apart from the others in leader election.
+func identity() string {
+	if name := os.Getenv("POD_NAME"); name != "" {
+		return name
+	}
+	name, _ := os.Hostname()
+	return name
+}
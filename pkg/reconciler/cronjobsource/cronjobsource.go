@@ -20,31 +20,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
 	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/cronjobsource/resources"
 
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
 
-	"github.com/google/go-cmp/cmp"
 	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
-	"go.uber.org/zap"
-	"knative.dev/pkg/controller"
-	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
 )
 
 var (
 	errSinkMissing = errors.New("Sink missing from spec")
 )
 
-// Reconciler implements controller.Reconciler for CronJobSource resources.
+// Reconciler is the reconciler.Kind for CronJobSource resources.
 type Reconciler struct {
 	// +required
 	*reconciler.Base
@@ -52,145 +51,240 @@ type Reconciler struct {
 	// Lister allows us to query for CronJobSources
 	// +required
 	Lister listers.CronJobSourceLister
+
+	// JobLister allows us to query for the Jobs started by CronJobs
+	// +required
+	JobLister batchv1listers.JobLister
+
+	// CronJobs writes CronJobs in the version of the batch API that the
+	// cluster serves.
+	// +required
+	CronJobs reconciler.CronJobClient
+
+	// CronJobLister reads CronJobs from the informer on the version of the
+	// batch API that CronJobs uses.
+	// +required
+	CronJobLister reconciler.CronJobLister
+
+	// Clock provides the time of runs that are triggered by hand.
+	// +required
+	Clock system.Clock
+
+	// Inline sends the events of CronJobSources without a job template.
+	// +required
+	Inline InlineSender
 }
 
-// Check that our Reconciler implements controller.Reconciler
-var _ controller.Reconciler = (*Reconciler)(nil)
+// Check that our Reconciler is a kind of source with a child.
+var (
+	_ reconciler.Kind               = (*Reconciler)(nil)
+	_ reconciler.DeletionReconciler = (*Reconciler)(nil)
+	_ reconciler.Forgetter          = (*Reconciler)(nil)
+	_ reconciler.ChildKind          = (*Reconciler)(nil)
+)
 
-// Reconcile implements controller.Reconciler
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
-	logger := logging.FromContext(ctx)
+// SourceClient implements reconciler.Kind
+func (r *Reconciler) SourceClient() reconciler.SourceClient {
+	return cronJobSourceClient{lister: r.Lister, client: r.SourcesClientSet}
+}
 
-	// Convert the namespace/name string into a distinct namespace and name
-	namespace, name, err := cache.SplitMetaNamespaceKey(key)
-	if err != nil {
-		logger.Errorf("invalid resource key: %s", key)
-		return nil
+// Forget implements reconciler.Forgetter
+func (r *Reconciler) Forget(key string) {
+	r.Inline.Unschedule(key)
+}
+
+// ReconcileDeletion implements reconciler.DeletionReconciler
+func (r *Reconciler) ReconcileDeletion(ctx context.Context, source reconciler.SourceObject) error {
+	r.Inline.Unschedule(inlineKey(source.(*v1alpha1.CronJobSource)))
+	return nil
+}
+
+// ReconcileKind implements reconciler.Kind. Having a sink is a prereq for
+// the cronjob, so the sink is resolved by then.
+func (r *Reconciler) ReconcileKind(ctx context.Context, source reconciler.SourceObject) error {
+	s := source.(*v1alpha1.CronJobSource)
+
+	if next, err := s.Spec.NextScheduleTime(r.Clock.Now()); err == nil && !isSuspended(s) {
+		s.Status.SetNextScheduleTime(next)
+	} else {
+		s.Status.SetNextScheduleTime(time.Time{})
 	}
 
-	// If our controller has configuration state, we'd "freeze" it and
-	// attach the frozen configuration to the context.
-	//    ctx = r.configStore.ToContext(ctx)
-
-	// Get the resource with this namespace/name.
-	original, err := r.Lister.CronJobSources(namespace).Get(name)
-	if apierrs.IsNotFound(err) {
-		// The resource may no longer exist, in which case we stop processing.
-		logger.Errorf("resource %q no longer exists", key)
-		return nil
-	} else if err != nil {
-		return err
+	if s.Spec.IsInline() {
+		return r.reconcileInline(ctx, s)
 	}
-	// Don't modify the informers copy.
-	resource := original.DeepCopy()
-
-	// Reconcile this copy of the resource and then write back any status
-	// updates regardless of whether the reconciliation errored out.
-	reconcileErr := r.reconcile(ctx, resource)
-	if equality.Semantic.DeepEqual(original.Status, resource.Status) {
-		// If we didn't change anything then don't call updateStatus.
-		// This is important because the copy we loaded from the informer's
-		// cache may be stale and we don't want to overwrite a prior update
-		// to status with this stale state.
-	} else if _, err = r.updateStatus(resource); err != nil {
-		logger.Warnw("Failed to update resource status", zap.Error(err))
-		r.Recorder.Eventf(resource, corev1.EventTypeWarning, "UpdateFailed",
-			"Failed to update status for %q: %v", resource.Name, err)
+	// The CronJobSource may have been inline before.
+	r.Inline.Unschedule(inlineKey(s))
+
+	child, err := reconciler.ReconcileChild(ctx, r, s)
+	if err != nil || child == nil {
 		return err
 	}
-	if reconcileErr != nil {
-		r.Logger.Warnw("Internal error reconciling:", zap.Error(reconcileErr))
-		r.Recorder.Event(resource, corev1.EventTypeWarning, "InternalError", reconcileErr.Error())
+	cronjob := child.(*batchv1beta1.CronJob)
+
+	if err := r.reconcileRunRequests(ctx, s, cronjob); err != nil {
+		return err
 	}
-	return reconcileErr
+
+	return r.reconcileRuns(ctx, s, cronjob)
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, s *v1alpha1.CronJobSource) error {
+// MakeChild implements reconciler.ChildKind. It returns no CronJob if none
+// can be made until the spec changes.
+func (r *Reconciler) MakeChild(source reconciler.SourceObject) reconciler.ChildObject {
+	s := source.(*v1alpha1.CronJobSource)
+	desired := resources.MakeCronJob(s)
+	if s.Spec.TimeZone != "" {
+		schedule, err := r.utcSchedule(s)
+		if err != nil {
+			s.Status.MarkNoCronJob("InvalidSchedule", "%v", err)
+			return nil
+		}
+		desired.Spec.Schedule = schedule
+	}
+	return desired
+}
 
-	if s.GetDeletionTimestamp() != nil {
-		// Check for a DeletionTimestamp.  If present, elide the normal reconcile logic.
-		// When a controller needs finalizer handling, it would go here.
-		return nil
+// GetChild implements reconciler.ChildKind
+func (r *Reconciler) GetChild(namespace, name string) (reconciler.ChildObject, error) {
+	cronjob, err := r.CronJobLister.Get(namespace, name)
+	if err != nil {
+		return nil, err
 	}
-	s.Status.InitializeConditions()
+	return cronjob, nil
+}
 
-	// Having a sink is a prereq for the cronjob
-	if err := r.ReconcileSink(ctx, s); err != nil {
-		return err
+// CreateChild implements reconciler.ChildKind
+func (r *Reconciler) CreateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	cronjob, err := r.CronJobs.Create(child.(*batchv1beta1.CronJob))
+	if err != nil {
+		return nil, err
 	}
+	return cronjob, nil
+}
 
-	if err := r.reconcileCronJob(ctx, s); err != nil {
-		return err
+// UpdateChild implements reconciler.ChildKind
+func (r *Reconciler) UpdateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	cronjob, err := r.CronJobs.Update(child.(*batchv1beta1.CronJob))
+	if err != nil {
+		return nil, err
 	}
+	return cronjob, nil
+}
 
-	s.Status.ObservedGeneration = s.Generation
+// MarkChildFailed implements reconciler.ChildKind
+func (r *Reconciler) MarkChildFailed(source reconciler.SourceObject, reason, messageFormat string, messageA ...interface{}) {
+	source.(*v1alpha1.CronJobSource).Status.MarkNoCronJob(reason, messageFormat, messageA...)
+}
+
+// PropagateStatus implements reconciler.ChildKind. It copies the status of
+// the CronJob, whether or not it just changed.
+func (r *Reconciler) PropagateStatus(source reconciler.SourceObject, child reconciler.ChildObject, _ bool) error {
+	s := source.(*v1alpha1.CronJobSource)
+	s.Status.MarkCronJobCreated()
+	s.Status.PropagateCronJobStatus(&child.(*batchv1beta1.CronJob).Status)
 	return nil
 }
 
-// Ensure the CronJob exists according to the CronJobSourceSpec.
+// Have the controller send the events of an inline CronJobSource.
 // Assumes Status.SinkURI is set.
-func (r *Reconciler) reconcileCronJob(ctx context.Context, s *v1alpha1.CronJobSource) error {
-	cronjob, err := r.getCronJob(ctx, s)
-	desired := resources.MakeCronJob(s)
-
-	if apierrs.IsNotFound(err) {
-		// No job, must create it
-		cronjob, err := r.KubeClientSet.BatchV1beta1().CronJobs(s.Namespace).Create(desired)
-		if err != nil || cronjob == nil {
-			msg := "Failed to make CronJob."
-			if err != nil {
-				msg = msg + " " + err.Error()
-			}
-			s.Status.MarkNoCronJob("FailedCreate", msg)
-			return fmt.Errorf("failed to create CronJob: %s", err)
+func (r *Reconciler) reconcileInline(ctx context.Context, s *v1alpha1.CronJobSource) error {
+	// The CronJobSource may have had a job template before, and the pods of
+	// its CronJob would send events as well.
+	if cronjob, err := r.getCronJob(ctx, s); err == nil && metav1.IsControlledBy(cronjob, s) {
+		propagation := metav1.DeletePropagationBackground
+		err := r.CronJobs.Delete(s.Namespace, cronjob.Name, &metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete CronJob: %s", err)
 		}
-
-		s.Status.MarkCronJobCreated()
-		s.Status.PropagateCronJobStatus(&cronjob.Status)
-		return nil
-	} else if err != nil {
-		r.Logger.Warnw("Failed get:", zap.Error(err))
-		s.Status.MarkNoCronJob("FailedGet", err.Error())
+		r.Recorder.Eventf(s, corev1.EventTypeNormal, "CronJobDeleted", "Deleted CronJob %q", cronjob.Name)
+	} else if err != nil && !apierrs.IsNotFound(err) {
 		return fmt.Errorf("failed to get CronJob: %s", err)
 	}
 
-	// CronJob exists. Make sure it matches what we want.
-	// The service exists; check if it looks like we expect
-	if diff := cmp.Diff(desired.Spec, cronjob.Spec); diff != "" {
-		cronjob.Spec = desired.Spec
-		cronjob, err := r.KubeClientSet.BatchV1beta1().CronJobs(s.Namespace).Update(cronjob)
-		r.Logger.Desugar().Info("CronJob updated.",
-			zap.Error(err), zap.Any("cronjob", cronjob), zap.String("diff", diff))
-		// TODO(spencer-p) What should the status be at this point?
-		s.Status.MarkCronJobCreated()
-		s.Status.PropagateCronJobStatus(&cronjob.Status)
+	key := inlineKey(s)
+	if isSuspended(s) {
+		r.Inline.Unschedule(key)
+	} else if err := r.Inline.Schedule(ctx, s); err != nil {
 		return err
 	}
+	s.Status.MarkInline()
 
-	// Copy its status.
-	s.Status.MarkCronJobCreated()
-	s.Status.PropagateCronJobStatus(&cronjob.Status)
-
+	if sends := r.Inline.Sends(key); sends != nil {
+		s.Status.PropagateSends(sends.Last, sends.LastSuccessfulTime, sends.ConsecutiveFailures, s.Spec.GetFailureThreshold())
+	} else if c := s.Status.GetCondition(v1alpha1.CronJobSourceConditionRunsSucceeding); c == nil || c.IsUnknown() {
+		// Only the leader knows the results of the sends; the other replicas
+		// leave the ones it wrote alone.
+		s.Status.MarkRunsSucceeding()
+	}
 	return nil
 }
 
+// utcSchedule translates the schedule of the CronJobSource to UTC, where
+// CronJobs are assumed to run. The translation holds until the UTC offset of
+// the time zone changes after the next tick, by when the Job of the tick
+// has had the CronJobSource reconciled again.
+func (r *Reconciler) utcSchedule(s *v1alpha1.CronJobSource) (string, error) {
+	loc, err := s.Spec.Location()
+	if err != nil {
+		return "", err
+	}
+	next, err := s.Spec.NextScheduleTime(r.Clock.Now())
+	if err != nil {
+		return "", err
+	}
+	return resources.TranslateSchedule(s.Spec.Schedule, loc, next)
+}
+
+func isSuspended(s *v1alpha1.CronJobSource) bool {
+	return s.Spec.Suspend != nil && *s.Spec.Suspend
+}
+
+func inlineKey(s *v1alpha1.CronJobSource) string {
+	return types.NamespacedName{Namespace: s.Namespace, Name: s.Name}.String()
+}
+
 func (r *Reconciler) getCronJob(ctx context.Context, owner metav1.Object) (*batchv1beta1.CronJob, error) {
-	return r.KubeClientSet.BatchV1beta1().CronJobs(owner.GetNamespace()).Get(resources.CronJobName(owner), metav1.GetOptions{})
+	return r.CronJobLister.Get(owner.GetNamespace(), resources.CronJobName(owner))
+}
+
+// cronJobSourceClient reads CronJobSources and writes them for the SourceReconciler.
+type cronJobSourceClient struct {
+	lister listers.CronJobSourceLister
+	client clientset.Interface
 }
 
-// Update the Status of the resource.  Caller is responsible for checking
-// for semantic differences before calling.
-func (r *Reconciler) updateStatus(desired *v1alpha1.CronJobSource) (*v1alpha1.CronJobSource, error) {
-	actual, err := r.Lister.CronJobSources(desired.Namespace).Get(desired.Name)
+var _ reconciler.SourceClient = cronJobSourceClient{}
+
+func (c cronJobSourceClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	var (
+		source *v1alpha1.CronJobSource
+		err    error
+	)
+	if fresh {
+		source, err = c.client.SourcesV1alpha1().CronJobSources(namespace).Get(name, metav1.GetOptions{})
+	} else {
+		source, err = c.lister.CronJobSources(namespace).Get(name)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// If there's nothing to update, just return.
-	if reflect.DeepEqual(actual.Status, desired.Status) {
-		return actual, nil
-	}
-	// Don't modify the informers copy
-	existing := actual.DeepCopy()
-	existing.Status = desired.Status
-	return r.SourcesClientSet.SourcesV1alpha1().CronJobSources(desired.Namespace).UpdateStatus(existing)
+	return source, nil
+}
+
+func (c cronJobSourceClient) UpdateStatus(source runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().CronJobSources(source.(*v1alpha1.CronJobSource).Namespace).UpdateStatus(source.(*v1alpha1.CronJobSource))
+	return err
+}
+
+func (c cronJobSourceClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().CronJobSources(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c cronJobSourceClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().CronJobSources(namespace).Patch(name, types.MergePatchType, patch)
+	return err
 }
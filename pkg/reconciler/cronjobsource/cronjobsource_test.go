@@ -31,12 +31,13 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 	"knative.dev/pkg/apis"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
-	"knative.dev/pkg/configmap"
-	"knative.dev/pkg/controller"
 	"knative.dev/pkg/ptr"
 
 	. "github.com/n3wscott/sources/pkg/reconciler/testing"
@@ -54,6 +55,12 @@ const (
 
 	failreason  = "fail reason"
 	failmessage = "fail message"
+
+	cronJobUID = "5678"
+
+	// sentName is an inline CronJobSource whose events failed to send.
+	sentName = "my-sent-cronjobsource"
+	sentKey  = ns + "/" + sentName
 )
 
 var (
@@ -65,6 +72,8 @@ var (
 	}))
 
 	lastScheduleTime = metav1.Time{time.Time{}.Add(time.Duration(1337))}
+
+	newYork, _ = time.LoadLocation("America/New_York")
 )
 
 func init() {
@@ -107,6 +116,104 @@ func newUnstructuredSink(scheme, hostname string) *unstructured.Unstructured {
 	}
 }
 
+// now is the time according to the reconciler's clock.
+var now = scheduleTime(42)
+
+// runningCronJob is the CronJob of a CronJobSource that is up and running.
+var runningCronJob = NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+	s.UID = sUID
+	s.Spec.Sink = svcSink
+	s.Status.InitializeConditions()
+	s.Status.MarkSink(sinkURI)
+}), WithCronJobUID(cronJobUID))
+
+// scheduleTime returns a time the CronJob could have been scheduled for.
+func scheduleTime(minute int) time.Time {
+	return time.Date(2019, time.July, 1, 12, minute, 0, 0, time.UTC)
+}
+
+// runName returns the name the CronJob gives its Job for scheduleTime(minute).
+func runName(minute int) string {
+	return resources.ScheduledJobName(runningCronJob, scheduleTime(minute))
+}
+
+// backfillingSource is a CronJobSource asked to backfill scheduleTime(0) to scheduleTime(30).
+func backfillingSource(s *v1alpha1.CronJobSource) {
+	s.UID = sUID
+	s.Spec.Sink = svcSink
+	s.Spec.Schedule = "*/10 * * * *"
+	s.Annotations = map[string]string{
+		v1alpha1.BackfillAnnotation: "2019-07-01T12:00:00Z/2019-07-01T12:30:00Z",
+	}
+	s.Status.InitializeConditions()
+	s.Status.MarkSink(sinkURI)
+}
+
+// backfillingCronJob is the CronJob of backfillingSource.
+var backfillingCronJob = NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, backfillingSource), WithCronJobUID(cronJobUID))
+
+// fakeInlineSender reports failed sends for sentKey.
+type fakeInlineSender struct{}
+
+func (fakeInlineSender) Schedule(context.Context, *v1alpha1.CronJobSource) error { return nil }
+
+func (fakeInlineSender) Unschedule(string) {}
+
+func (fakeInlineSender) Sends(key string) *InlineSends {
+	if key != sentKey {
+		return nil
+	}
+	return &InlineSends{
+		Last: &v1alpha1.CronJobSourceSend{
+			ScheduledTime: metav1.NewTime(scheduleTime(40)),
+			EventID:       sUID + "-1561984800",
+			Message:       "connection refused",
+		},
+		LastSuccessfulTime:  &metav1.Time{Time: scheduleTime(0)},
+		ConsecutiveFailures: 4,
+	}
+}
+
+// newYorkSource is a CronJobSource scheduled in the time zone of New York,
+// which is at UTC-04:00 around now.
+func newYorkSource(schedule string) CronJobSourceOption {
+	return func(s *v1alpha1.CronJobSource) {
+		s.UID = sUID
+		s.Spec.Sink = svcSink
+		s.Spec.Schedule = schedule
+		s.Spec.TimeZone = "America/New_York"
+	}
+}
+
+// foreignController controls children that the source does not own.
+var foreignController = metav1.OwnerReference{
+	APIVersion: "apps/v1",
+	Kind:       "Deployment",
+	Name:       "someone-else",
+	UID:        "5678",
+	Controller: ptr.Bool(true),
+}
+
+// servedCronJobs is a discovery client for a cluster that serves CronJobs in
+// the given version of the batch API.
+func servedCronJobs(gv schema.GroupVersion) discovery.DiscoveryInterface {
+	return &fakediscovery.FakeDiscovery{Fake: &clientgotesting.Fake{
+		Resources: []*metav1.APIResourceList{{
+			GroupVersion: gv.String(),
+			APIResources: []metav1.APIResource{{Name: "cronjobs", Namespaced: true, Kind: "CronJob"}},
+		}},
+	}}
+}
+
+// withoutRunLimits clears the RecentRunsLimit and FailureThreshold that
+// NewCronJobSource defaults, like those of a CronJobSource stored before
+// they were defaulted.
+func withoutRunLimits(s *v1alpha1.CronJobSource) *v1alpha1.CronJobSource {
+	s.Spec.RecentRunsLimit = nil
+	s.Spec.FailureThreshold = nil
+	return s
+}
+
 func TestCronJobSource(t *testing.T) {
 	table := TableTest{{
 		Name: "bad workqueue key",
@@ -119,7 +226,7 @@ func TestCronJobSource(t *testing.T) {
 	}, {
 		Name: "missing sink in spec causes errors",
 		Objects: []runtime.Object{
-			NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Status.InitializeConditions()
 			}),
@@ -127,7 +234,7 @@ func TestCronJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 
 				s.Status.InitializeConditions()
@@ -140,7 +247,7 @@ func TestCronJobSource(t *testing.T) {
 	}, {
 		Name: "sink not existing causes errors",
 		Objects: []runtime.Object{
-			NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Status.InitializeConditions()
 				s.Spec.Sink = namedTestSink("dne") // does not exist
@@ -150,7 +257,7 @@ func TestCronJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Spec.Sink = namedTestSink("dne")
 
@@ -164,7 +271,7 @@ func TestCronJobSource(t *testing.T) {
 	}, {
 		Name: "sink with bad address causes errors",
 		Objects: []runtime.Object{
-			NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Status.InitializeConditions()
 				s.Spec.Sink = namedTestSink(sinkName)
@@ -176,7 +283,7 @@ func TestCronJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewCronJobSource(sName, func(s *v1alpha1.CronJobSource) {
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Spec.Sink = namedTestSink(sinkName)
 
@@ -205,9 +312,10 @@ func TestCronJobSource(t *testing.T) {
 				s.Status.InitializeConditions()
 				s.Status.MarkSink(sinkURI)
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 			}),
 		}},
-		WantCreates: []runtime.Object{resources.MakeCronJob(
+		WantCreates: []runtime.Object{NewCronJob(
 			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Spec.Sink = svcSink
@@ -233,9 +341,10 @@ func TestCronJobSource(t *testing.T) {
 				s.Status.InitializeConditions()
 				s.Status.MarkSink("http://example.com")
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 			}),
 		}},
-		WantCreates: []runtime.Object{resources.MakeCronJob(
+		WantCreates: []runtime.Object{NewCronJob(
 			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Status.InitializeConditions()
@@ -260,9 +369,10 @@ func TestCronJobSource(t *testing.T) {
 				s.Status.InitializeConditions()
 				s.Status.MarkSink("http://example.com/foo/bar")
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 			}),
 		}},
-		WantCreates: []runtime.Object{resources.MakeCronJob(
+		WantCreates: []runtime.Object{NewCronJob(
 			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
 				s.Status.InitializeConditions()
@@ -299,6 +409,7 @@ func TestCronJobSource(t *testing.T) {
 				s.Status.InitializeConditions()
 				s.Status.MarkSink(sinkURI)
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 
 				// Time in the CronJobSource should match what we set in the CronJob
 				s.Status.LastScheduleTime = &lastScheduleTime
@@ -313,6 +424,7 @@ func TestCronJobSource(t *testing.T) {
 				s.Spec.Sink = namedTestSink(sinkName)
 				s.Status.MarkSink(sinkURI)
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 			}),
 			NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
 				s.UID = sUID
@@ -332,6 +444,7 @@ func TestCronJobSource(t *testing.T) {
 				s.Status.InitializeConditions()
 				s.Status.MarkSink("http://garbage")
 				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
 			}),
 		}},
 		WantUpdates: []clientgotesting.UpdateActionImpl{
@@ -344,12 +457,591 @@ func TestCronJobSource(t *testing.T) {
 				})),
 			},
 		},
+	}, {
+		Name: "cronjob owned by something else is left alone",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+			}),
+			NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Spec.Schedule = "*/10 * * * *"
+			}), WithCronJobOwnerReferences(foreignController)),
+		},
+		Key:     key,
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkChildNotOwned("CronJob", sName)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", "CronJobSource %q does not own CronJob %q", sName, sName),
+		},
+	}, {
+		Name: "adopt annotation adopts a cronjob that nothing controls",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.InitializeConditions()
+			}),
+			NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.MarkSink(sinkURI)
+			}), WithCronJobOwnerReferences()),
+		},
+		Key: key,
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.MarkSink(sinkURI)
+			})),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+		}},
+	}, {
+		Name: "cronjobsource records the runs of the cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			runningCronJob,
+			NewCronJobRun(runningCronJob, scheduleTime(0), WithJobCompleted(metav1.NewTime(scheduleTime(1)))),
+			NewCronJobRun(runningCronJob, scheduleTime(10), WithJobFailed(metav1.NewTime(scheduleTime(11)), failreason, failmessage)),
+			NewCronJobRun(runningCronJob, scheduleTime(20)),
+			// Not started by the CronJob.
+			NewCronJobRun(NewCronJob(NewCronJobSource("other", WithFakeCronJobSpec), WithCronJobUID("other")), scheduleTime(0)),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+
+				s.Status.RecentRuns = []v1alpha1.CronJobSourceRun{{
+					JobName:        runName(0),
+					ScheduledTime:  &metav1.Time{Time: scheduleTime(0)},
+					CompletionTime: &metav1.Time{Time: scheduleTime(1)},
+					Result:         v1alpha1.CronJobSourceRunSucceeded,
+				}, {
+					JobName:        runName(10),
+					ScheduledTime:  &metav1.Time{Time: scheduleTime(10)},
+					CompletionTime: &metav1.Time{Time: scheduleTime(11)},
+					Result:         v1alpha1.CronJobSourceRunFailed,
+					Reason:         failreason,
+					Message:        failmessage,
+				}, {
+					JobName:       runName(20),
+					ScheduledTime: &metav1.Time{Time: scheduleTime(20)},
+					Result:        v1alpha1.CronJobSourceRunRunning,
+				}}
+				s.Status.LastSuccessfulTime = &metav1.Time{Time: scheduleTime(1)}
+				s.Status.ConsecutiveFailures = 1
+			}),
+		}},
+	}, {
+		Name: "cronjobsource stored without run limits records its runs",
+		Objects: []runtime.Object{
+			withoutRunLimits(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			})),
+			runningCronJob,
+			NewCronJobRun(runningCronJob, scheduleTime(10), WithJobFailed(metav1.NewTime(scheduleTime(11)), failreason, failmessage)),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withoutRunLimits(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				// One failure is below the default threshold.
+				s.Status.MarkRunsSucceeding()
+
+				s.Status.RecentRuns = []v1alpha1.CronJobSourceRun{{
+					JobName:        runName(10),
+					ScheduledTime:  &metav1.Time{Time: scheduleTime(10)},
+					CompletionTime: &metav1.Time{Time: scheduleTime(11)},
+					Result:         v1alpha1.CronJobSourceRunFailed,
+					Reason:         failreason,
+					Message:        failmessage,
+				}}
+				s.Status.ConsecutiveFailures = 1
+			})),
+		}},
+	}, {
+		Name: "consecutive failures mark the cronjobsource not ready",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Spec.FailureThreshold = ptr.Int32(1)
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			runningCronJob,
+			NewCronJobRun(runningCronJob, scheduleTime(10), WithJobFailed(metav1.NewTime(scheduleTime(11)), failreason, failmessage)),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Spec.FailureThreshold = ptr.Int32(1)
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsFailing("ConsecutiveFailures", "The last %d runs failed.", 1)
+
+				s.Status.RecentRuns = []v1alpha1.CronJobSourceRun{{
+					JobName:        runName(10),
+					ScheduledTime:  &metav1.Time{Time: scheduleTime(10)},
+					CompletionTime: &metav1.Time{Time: scheduleTime(11)},
+					Result:         v1alpha1.CronJobSourceRunFailed,
+					Reason:         failreason,
+					Message:        failmessage,
+				}}
+				s.Status.ConsecutiveFailures = 1
+			}),
+		}},
+	}, {
+		Name: "trigger-run annotation starts a job",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.TriggerRunAnnotation: "1"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			runningCronJob,
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			resources.MakeRunJob(runningCronJob, resources.TriggeredJobName(runningCronJob, "1"), now),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.TriggerRunAnnotation: "1"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.LastTriggerRun = "1"
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "RunTriggered", "Created Job %q", resources.TriggeredJobName(runningCronJob, "1")),
+		},
+	}, {
+		Name: "trigger-run annotation is acted on once",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.TriggerRunAnnotation: "1"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.LastTriggerRun = "1"
+			}),
+			runningCronJob,
+		},
+		Key: key,
+		// Expect nothing to happen
+	}, {
+		Name: "backfill annotation starts a job per missed tick",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, backfillingSource, func(s *v1alpha1.CronJobSource) {
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			backfillingCronJob,
+			NewCronJobRun(backfillingCronJob, scheduleTime(10)),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			resources.MakeRunJob(backfillingCronJob, runName(0), scheduleTime(0)),
+			resources.MakeRunJob(backfillingCronJob, runName(10), scheduleTime(10)),
+			resources.MakeRunJob(backfillingCronJob, runName(20), scheduleTime(20)),
+			resources.MakeRunJob(backfillingCronJob, runName(30), scheduleTime(30)),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, backfillingSource, func(s *v1alpha1.CronJobSource) {
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.LastBackfill = "2019-07-01T12:00:00Z/2019-07-01T12:30:00Z"
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+				s.Status.RecentRuns = []v1alpha1.CronJobSourceRun{{
+					JobName:       runName(10),
+					ScheduledTime: &metav1.Time{Time: scheduleTime(10)},
+					Result:        v1alpha1.CronJobSourceRunRunning,
+				}}
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Backfilled", "Created %d of %d Jobs for backfill %q", 3, 4, "2019-07-01T12:00:00Z/2019-07-01T12:30:00Z"),
+		},
+	}, {
+		Name: "inline source has no cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.MarkRunsSucceeding()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+			}),
+		}},
+	}, {
+		Name: "inline source deletes its cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			runningCronJob,
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.MarkRunsSucceeding()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+			}),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ns,
+				Verb:      "delete",
+				Resource:  batchv1beta1.SchemeGroupVersion.WithResource("cronjobs"),
+			},
+			Name: runningCronJob.Name,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "CronJobDeleted", "Deleted CronJob %q", runningCronJob.Name),
+		},
+	}, {
+		Name: "inline source reports failed sends",
+		Objects: []runtime.Object{
+			NewCronJobSource(sentName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.MarkRunsSucceeding()
+			}),
+		},
+		Key: sentKey,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sentName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+				s.Status.PropagateSends(fakeInlineSender{}.Sends(sentKey).Last, &metav1.Time{Time: scheduleTime(0)}, 4, 3)
+			}),
+		}},
+	}, {
+		Name: "inline source stored without a failure threshold reports failed sends",
+		Objects: []runtime.Object{
+			withoutRunLimits(NewCronJobSource(sentName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.MarkRunsSucceeding()
+			})),
+		},
+		Key: sentKey,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withoutRunLimits(NewCronJobSource(sentName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+				s.Status.PropagateSends(fakeInlineSender{}.Sends(sentKey).Last, &metav1.Time{Time: scheduleTime(0)}, 4, 3)
+			})),
+		}},
+	}, {
+		Name: "inline source keeps the sends of the leader on other replicas",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.PropagateSends(fakeInlineSender{}.Sends(sentKey).Last, &metav1.Time{Time: scheduleTime(0)}, 4, 3)
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+				s.Status.PropagateSends(fakeInlineSender{}.Sends(sentKey).Last, &metav1.Time{Time: scheduleTime(0)}, 4, 3)
+			}),
+		}},
+	}, {
+		Name: "time zone is translated to utc",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, newYorkSource("0 9 * * 1-5")),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, newYorkSource("0 9 * * 1-5"), func(s *v1alpha1.CronJobSource) {
+				s.Status.MarkSink(sinkURI)
+			}), func(cronjob *batchv1beta1.CronJob) {
+				cronjob.Spec.Schedule = "0 13 * * 1,2,3,4,5"
+			}),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, newYorkSource("0 9 * * 1-5"), func(s *v1alpha1.CronJobSource) {
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.SetNextScheduleTime(time.Date(2019, time.July, 1, 13, 0, 0, 0, time.UTC).In(newYork))
+			}),
+		}},
+	}, {
+		Name: "time zone that cannot be translated",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, newYorkSource("0 19,21 * * 1")),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, newYorkSource("0 19,21 * * 1"), func(s *v1alpha1.CronJobSource) {
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkNoCronJob("InvalidSchedule", `the days of "0 19,21 * * 1" at UTC-04:00 cannot be expressed in UTC`)
+				s.Status.SetNextScheduleTime(time.Date(2019, time.July, 1, 23, 0, 0, 0, time.UTC).In(newYork))
+			}),
+		}},
+	}}
+
+	table.Test(t, MakeSourceFactory("CronJobSource", FakeClock{Time: now}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
+		r := &Reconciler{
+			Base:      base,
+			Lister:    listers.GetCronJobSourceLister(),
+			JobLister: listers.GetJobLister(),
+			Clock:     FakeClock{Time: now},
+			Inline:    fakeInlineSender{},
+		}
+		r.CronJobs = reconciler.NewCronJobClient(servedCronJobs(reconciler.BatchV1beta1), r.KubeClientSet, r.DynamicClientSet)
+		r.CronJobLister = r.CronJobs.Lister(listers.GetIndexer(&batchv1beta1.CronJob{}))
+		return r
+	}))
+}
+
+// asBatchV1 returns the CronJob as served by batch/v1.
+func asBatchV1(cronjob *batchv1beta1.CronJob) *unstructured.Unstructured {
+	u, err := reconciler.ToUnstructured(cronjob, reconciler.BatchV1.WithKind("CronJob"))
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestCronJobSourceBatchV1(t *testing.T) {
+	v1CronJob := runningCronJob.DeepCopy()
+	v1CronJob.SetGroupVersionKind(reconciler.BatchV1.WithKind("CronJob"))
+
+	table := TableTest{{
+		Name: "having sink creates a cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+			}),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			asBatchV1(NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.MarkSink(sinkURI)
+			}))),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+		}},
+	}, {
+		Name: "cronjob is updated to match the spec",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Spec.Schedule = "*/10 * * * *"
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+			}),
+			asBatchV1(runningCronJob),
+		},
+		Key: key,
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: asBatchV1(NewCronJob(NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Spec.Schedule = "*/10 * * * *"
+				s.Status.MarkSink(sinkURI)
+			}), WithCronJobUID(cronJobUID))),
+		}},
+	}, {
+		Name: "trigger-run annotation starts a job of the cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.TriggerRunAnnotation: "1"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+			}),
+			asBatchV1(runningCronJob),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			// The Job refers to its CronJob in batch/v1.
+			resources.MakeRunJob(v1CronJob, resources.TriggeredJobName(v1CronJob, "1"), now),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCronJobSource(sName, WithFakeCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Annotations = map[string]string{v1alpha1.TriggerRunAnnotation: "1"}
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkCronJobCreated()
+				s.Status.MarkRunsSucceeding()
+				s.Status.LastTriggerRun = "1"
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "RunTriggered", "Created Job %q", resources.TriggeredJobName(v1CronJob, "1")),
+		},
+	}, {
+		Name: "inline source deletes its cronjob",
+		Objects: []runtime.Object{
+			NewCronJobSource(sName, WithInlineCronJobSpec, func(s *v1alpha1.CronJobSource) {
+				s.UID = sUID
+				s.Spec.Sink = svcSink
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkInline()
+				s.Status.MarkRunsSucceeding()
+				s.Status.SetNextScheduleTime(scheduleTime(50))
+			}),
+			asBatchV1(runningCronJob),
+		},
+		Key: key,
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ns,
+				Verb:      "delete",
+				Resource:  reconciler.BatchV1.WithResource("cronjobs"),
+			},
+			Name: runningCronJob.Name,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "CronJobDeleted", "Deleted CronJob %q", runningCronJob.Name),
+		},
 	}}
 
-	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
-		return &Reconciler{
-			Base:   reconciler.NewBase(ctx, "CronJobSource", cmw),
-			Lister: listers.GetCronJobSourceLister(),
+	table.Test(t, MakeSourceFactory("CronJobSource", FakeClock{Time: now}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
+		r := &Reconciler{
+			Base:      base,
+			Lister:    listers.GetCronJobSourceLister(),
+			JobLister: listers.GetJobLister(),
+			Clock:     FakeClock{Time: now},
+			Inline:    fakeInlineSender{},
 		}
+		r.CronJobs = reconciler.NewCronJobClient(servedCronJobs(reconciler.BatchV1), r.KubeClientSet, r.DynamicClientSet)
+		r.CronJobLister = r.CronJobs.Lister(listers.GetIndexer(&unstructured.Unstructured{}))
+		return r
 	}))
 }
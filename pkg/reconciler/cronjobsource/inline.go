@@ -0,0 +1,269 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobsource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/robfig/cron"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apiconfig "github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/cloudeventclient"
+	"github.com/n3wscott/sources/pkg/reconciler"
+)
+
+const (
+	// inlineEventType is the type of the events sent for inline CronJobSources.
+	inlineEventType = "dev.knative.sources.cronjobsource.tick"
+
+	// sendTimeout bounds how long a sink may take to accept an event.
+	sendTimeout = 30 * time.Second
+)
+
+// InlineSender sends the events of inline CronJobSources on their schedule.
+type InlineSender interface {
+	// Schedule starts or updates sending the events of the CronJobSource,
+	// in the CloudEvents spec version configured in ctx.
+	Schedule(ctx context.Context, s *v1alpha1.CronJobSource) error
+
+	// Unschedule stops sending the events of the CronJobSource with the
+	// given namespace/name key.
+	Unschedule(key string)
+
+	// Sends returns the results of sending the events of the CronJobSource
+	// with the given key, or nil if none were sent or this replica is not
+	// the leader.
+	Sends(key string) *InlineSends
+}
+
+// InlineSends are the results of sending the events of a CronJobSource.
+type InlineSends struct {
+	Last                *v1alpha1.CronJobSourceSend
+	LastSuccessfulTime  *metav1.Time
+	ConsecutiveFailures int32
+}
+
+// inlineConfig is everything that goes into sending the events of an inline
+// CronJobSource.
+type inlineConfig struct {
+	uid         string
+	schedule    string
+	timeZone    string
+	source      string
+	sink        string
+	format      v1alpha1.OutputFormatType
+	specVersion string
+	data        string
+	contentType string
+}
+
+type inlineTicker struct {
+	config inlineConfig
+	cancel context.CancelFunc
+}
+
+// inlineSender runs a goroutine per inline CronJobSource that waits for the
+// next tick of its schedule and, if this replica is the leader, sends the
+// event.
+type inlineSender struct {
+	ctx    context.Context
+	logger *zap.SugaredLogger
+
+	// isLeader is true on the one replica that sends events.
+	isLeader func() bool
+
+	// enqueue is called with the key of a CronJobSource after its event was
+	// sent, so that the result reaches its status.
+	enqueue func(key string)
+
+	mu      sync.Mutex
+	tickers map[string]*inlineTicker
+	sends   map[string]*InlineSends
+}
+
+var _ InlineSender = (*inlineSender)(nil)
+
+func newInlineSender(ctx context.Context, logger *zap.SugaredLogger, isLeader func() bool, enqueue func(string)) *inlineSender {
+	return &inlineSender{
+		ctx:      ctx,
+		logger:   logger,
+		isLeader: isLeader,
+		enqueue:  enqueue,
+		tickers:  make(map[string]*inlineTicker),
+		sends:    make(map[string]*InlineSends),
+	}
+}
+
+// Schedule implements InlineSender.
+func (i *inlineSender) Schedule(ctx context.Context, s *v1alpha1.CronJobSource) error {
+	schedule, err := cron.ParseStandard(s.Spec.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q: %v", s.Spec.Schedule, err)
+	}
+	loc, err := s.Spec.Location()
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q: %v", s.Spec.TimeZone, err)
+	}
+	config := inlineConfig{
+		uid:         string(s.UID),
+		schedule:    s.Spec.Schedule,
+		timeZone:    s.Spec.TimeZone,
+		source:      reconciler.CloudEventSource(s),
+		sink:        s.Status.SinkURI,
+		format:      s.Spec.OutputFormat,
+		specVersion: apiconfig.FromContextOrDefaults(ctx).ForNamespace(s.Namespace).CloudEventsSpecVersion,
+		data:        s.Spec.Data,
+		contentType: s.Spec.ContentType,
+	}
+	key, err := cache.MetaNamespaceKeyFunc(s)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if t, ok := i.tickers[key]; ok {
+		if t.config == config {
+			return nil
+		}
+		t.cancel()
+	}
+	if _, ok := i.sends[key]; !ok && s.Status.LastSend != nil {
+		// Pick up where the previous leader left off.
+		i.sends[key] = &InlineSends{
+			Last:                s.Status.LastSend.DeepCopy(),
+			LastSuccessfulTime:  s.Status.LastSuccessfulTime.DeepCopy(),
+			ConsecutiveFailures: s.Status.ConsecutiveFailures,
+		}
+	}
+	ctx, cancel := context.WithCancel(i.ctx)
+	i.tickers[key] = &inlineTicker{config: config, cancel: cancel}
+	go i.run(ctx, key, schedule, loc, config)
+	return nil
+}
+
+// Unschedule implements InlineSender.
+func (i *inlineSender) Unschedule(key string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if t, ok := i.tickers[key]; ok {
+		t.cancel()
+		delete(i.tickers, key)
+	}
+	delete(i.sends, key)
+}
+
+// Sends implements InlineSender.
+func (i *inlineSender) Sends(key string) *InlineSends {
+	if !i.isLeader() {
+		// A former leader's results are out of date.
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if sends, ok := i.sends[key]; ok {
+		return &InlineSends{
+			Last:                sends.Last.DeepCopy(),
+			LastSuccessfulTime:  sends.LastSuccessfulTime.DeepCopy(),
+			ConsecutiveFailures: sends.ConsecutiveFailures,
+		}
+	}
+	return nil
+}
+
+func (i *inlineSender) run(ctx context.Context, key string, schedule cron.Schedule, loc *time.Location, config inlineConfig) {
+	for {
+		next := schedule.Next(time.Now().In(loc))
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if !i.isLeader() {
+			continue
+		}
+		send := i.send(ctx, config, next)
+		if ctx.Err() != nil {
+			// Unscheduled while sending; the result no longer matters.
+			return
+		}
+		i.record(key, send)
+		i.enqueue(key)
+	}
+}
+
+// send sends the event for the tick of the schedule at scheduled.
+func (i *inlineSender) send(ctx context.Context, config inlineConfig, scheduled time.Time) v1alpha1.CronJobSourceSend {
+	// The ID is the same for every replica, so that sinks can drop
+	// duplicates around a change of leader.
+	id := fmt.Sprintf("%s-%d", config.uid, scheduled.Unix())
+	send := v1alpha1.CronJobSourceSend{
+		ScheduledTime: metav1.NewTime(scheduled),
+		EventID:       id,
+	}
+
+	client, err := cloudeventclient.New(config.format, config.sink)
+	if err != nil {
+		send.Message = err.Error()
+		return send
+	}
+	event := cloudevents.NewEvent(config.specVersion)
+	event.SetID(id)
+	event.SetType(inlineEventType)
+	event.SetSource(config.source)
+	event.SetTime(scheduled)
+	event.SetDataContentType(config.contentType)
+	event.Data = []byte(config.data)
+	event.DataEncoded = true
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	if _, err := client.Send(ctx, event); err != nil {
+		i.logger.Warnw("Failed to send inline event", zap.String("source", config.source), zap.Error(err))
+		send.Message = err.Error()
+		return send
+	}
+	send.Succeeded = true
+	return send
+}
+
+func (i *inlineSender) record(key string, send v1alpha1.CronJobSourceSend) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	sends, ok := i.sends[key]
+	if !ok {
+		sends = &InlineSends{}
+		i.sends[key] = sends
+	}
+	sends.Last = &send
+	if send.Succeeded {
+		sends.LastSuccessfulTime = send.ScheduledTime.DeepCopy()
+		sends.ConsecutiveFailures = 0
+	} else {
+		sends.ConsecutiveFailures++
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobsource
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestInlineSenderSend(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   *InlineSends
+	}{{
+		name:   "accepted",
+		status: http.StatusAccepted,
+		want: &InlineSends{
+			Last: &v1alpha1.CronJobSourceSend{
+				ScheduledTime: metav1.NewTime(scheduleTime(10)),
+				EventID:       sUID + "-1561983000",
+				Succeeded:     true,
+			},
+			LastSuccessfulTime: &metav1.Time{Time: scheduleTime(10)},
+		},
+	}, {
+		name:   "rejected",
+		status: http.StatusBadRequest,
+		want: &InlineSends{
+			Last: &v1alpha1.CronJobSourceSend{
+				ScheduledTime: metav1.NewTime(scheduleTime(10)),
+				EventID:       sUID + "-1561983000",
+				Message:       "error sending cloudevent: 400 Bad Request",
+			},
+			ConsecutiveFailures: 1,
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got http.Header
+			var body string
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header
+				b, _ := ioutil.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(test.status)
+			}))
+			defer sink.Close()
+
+			i := newInlineSender(context.Background(), logtesting.TestLogger(t), isLeader(true), nil)
+			config := inlineConfig{
+				uid:         sUID,
+				source:      "/apis/v1/namespaces/default/cronjobsources/" + sName,
+				sink:        sink.URL,
+				format:      v1alpha1.OutputFormatBinary,
+				specVersion: "0.3",
+				data:        `{"hello":"world"}`,
+				contentType: "application/json",
+			}
+			i.record(key, i.send(context.Background(), config, scheduleTime(10)))
+
+			if got.Get("Ce-Id") != sUID+"-1561983000" || got.Get("Ce-Type") != inlineEventType ||
+				got.Get("Ce-Source") != config.source || got.Get("Content-Type") != "application/json" {
+				t.Errorf("Unexpected headers: %v", got)
+			}
+			if got.Get("Ce-Specversion") != config.specVersion {
+				t.Errorf("Ce-Specversion = %q, want %q", got.Get("Ce-Specversion"), config.specVersion)
+			}
+			if body != config.data {
+				t.Errorf("Body = %q, want %q", body, config.data)
+			}
+			if diff := cmp.Diff(test.want, i.Sends(key)); diff != "" {
+				t.Errorf("Unexpected sends (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestInlineSenderSendsNotLeader(t *testing.T) {
+	leading := true
+	i := newInlineSender(context.Background(), logtesting.TestLogger(t), func() bool { return leading }, nil)
+	i.record(key, v1alpha1.CronJobSourceSend{
+		ScheduledTime: metav1.NewTime(scheduleTime(10)),
+		EventID:       sUID + "-1561983000",
+	})
+	if i.Sends(key) == nil {
+		t.Fatal("Sends() = nil while leading")
+	}
+
+	// The results of a former leader would overwrite those of the new one.
+	leading = false
+	if got := i.Sends(key); got != nil {
+		t.Errorf("Sends() = %v after leadership was lost, wanted nil", got)
+	}
+}
+
+func isLeader(leading bool) func() bool {
+	return func() bool { return leading }
+}
@@ -24,7 +24,6 @@ import (
 	"knative.dev/pkg/kmeta"
 
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,7 +37,7 @@ func MakeCronJob(s *v1alpha1.CronJobSource) *batchv1beta1.CronJob {
 			Name:            CronJobName(s.GetObjectMeta()),
 			Namespace:       s.GetObjectMeta().GetNamespace(),
 			Labels:          reconciler.Labels(s, labelKey),
-			Annotations:     reconciler.Annotations(s),
+			Annotations:     annotations(s),
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(s)},
 		},
 	}
@@ -47,7 +46,7 @@ func MakeCronJob(s *v1alpha1.CronJobSource) *batchv1beta1.CronJob {
 	s.Spec.CronJobSpec.DeepCopyInto(&cronjob.Spec)
 	podTemplate := &cronjob.Spec.JobTemplate.Spec.Template
 	podTemplate.Labels = reconciler.Labels(s, labelKey)
-	podTemplate.Annotations = reconciler.Annotations(s)
+	podTemplate.Annotations = annotations(s)
 
 	// TODO(spencer-p) Eliminate extra copying here
 	containers := podTemplate.Spec.Containers
@@ -55,13 +54,22 @@ func MakeCronJob(s *v1alpha1.CronJobSource) *batchv1beta1.CronJob {
 		if containers[i].Name == "" {
 			containers[i].Name = fmt.Sprintf("cronjobsource%d", i)
 		}
-		containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: "K_SINK", Value: s.Status.SinkURI})
-		containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: "K_OUTPUT_FORMAT", Value: string(s.Spec.OutputFormat)})
+		containers[i].Env = append(containers[i].Env, reconciler.SinkEnv(s.Status.SinkURI, s.Spec.OutputFormat)...)
+		containers[i].Env = append(containers[i].Env, reconciler.SourceEnv(s)...)
 	}
 
 	return cronjob
 }
 
+// annotations returns the CronJobSource's annotations without the run
+// requests, which are meant for the controller alone.
+func annotations(s *v1alpha1.CronJobSource) map[string]string {
+	atns := reconciler.Annotations(s)
+	delete(atns, v1alpha1.TriggerRunAnnotation)
+	delete(atns, v1alpha1.BackfillAnnotation)
+	return atns
+}
+
 func CronJobName(owner metav1.Object) string {
 	// Reuse the owner's name.
 	return owner.GetName()
@@ -38,6 +38,7 @@ func TestMakeCronJob(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "Steve",
 			Namespace: "default",
+			UID:       "1234",
 		},
 		Spec: v1alpha1.CronJobSourceSpec{
 			CronJobSpec: batchv1beta1.CronJobSpec{
@@ -93,6 +94,11 @@ func TestMakeCronJob(t *testing.T) {
 									Env: []corev1.EnvVar{
 										corev1.EnvVar{Name: "K_SINK", Value: in.Status.SinkURI},
 										corev1.EnvVar{Name: "K_OUTPUT_FORMAT", Value: string(in.Spec.OutputFormat)},
+										corev1.EnvVar{Name: "K_CE_SOURCE", Value: "/apis/v1/namespaces/default/cronjobsources/Steve"},
+										corev1.EnvVar{Name: "K_SOURCE_KIND", Value: "CronJobSource"},
+										corev1.EnvVar{Name: "K_SOURCE_NAME", Value: "Steve"},
+										corev1.EnvVar{Name: "K_SOURCE_NAMESPACE", Value: "default"},
+										corev1.EnvVar{Name: "K_SOURCE_UID", Value: "1234"},
 									},
 								},
 							},
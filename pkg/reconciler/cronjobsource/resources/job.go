@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"knative.dev/pkg/kmeta"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ScheduledTimestampAnnotation holds the time a Job started by a CronJob
+	// was scheduled for. Newer CronJob controllers set it as well.
+	ScheduledTimestampAnnotation = "batch.kubernetes.io/cronjob-scheduled-timestamp"
+)
+
+// MakeRunJob makes a Job from the CronJob's template for a run scheduled at
+// the given time, the way the CronJob would. The containers are told the
+// scheduled time through K_SCHEDULED_TIME.
+func MakeRunJob(cronjob *batchv1beta1.CronJob, name string, scheduled time.Time) *batchv1.Job {
+	template := cronjob.Spec.JobTemplate.DeepCopy()
+
+	annotations := make(map[string]string, len(template.Annotations)+1)
+	for k, v := range template.Annotations {
+		annotations[k] = v
+	}
+	annotations[ScheduledTimestampAnnotation] = scheduled.UTC().Format(time.RFC3339)
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       cronjob.Namespace,
+			Labels:          template.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronjob, cronJobGVK(cronjob))},
+		},
+		Spec: template.Spec,
+	}
+
+	containers := job.Spec.Template.Spec.Containers
+	for i := range containers {
+		containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: "K_SCHEDULED_TIME", Value: scheduled.UTC().Format(time.RFC3339)})
+	}
+
+	return job
+}
+
+// ScheduledJobName returns the name of the Job for the run scheduled at the
+// given time. It is the name the CronJob itself gives that run, so a run is
+// not repeated while its Job is still around.
+func ScheduledJobName(cronjob metav1.Object, scheduled time.Time) string {
+	return fmt.Sprintf("%s-%d", cronjob.GetName(), scheduled.Unix()/60)
+}
+
+// TriggeredJobName returns the name of the Job for a run requested with the
+// given value of the trigger-run annotation.
+func TriggeredJobName(cronjob metav1.Object, trigger string) string {
+	h := fnv.New32a()
+	h.Write([]byte(trigger))
+	return kmeta.ChildName(cronjob.GetName(), fmt.Sprintf("-trigger-%08x", h.Sum32()))
+}
+
+// cronJobGVK returns the kind of the CronJob in the version of the batch API
+// it was read through. The typed client leaves it empty for batch/v1beta1.
+func cronJobGVK(cronjob *batchv1beta1.CronJob) schema.GroupVersionKind {
+	if gvk := cronjob.GroupVersionKind(); !gvk.Empty() {
+		return gvk
+	}
+	return batchv1beta1.SchemeGroupVersion.WithKind("CronJob")
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMakeRunJob(t *testing.T) {
+	cronjob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "Steve",
+			Namespace: "default",
+			UID:       "1234",
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "steve"},
+					Annotations: map[string]string{"note": "hi"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Name:  "SteveImage",
+								Image: "example-img",
+								Env:   []corev1.EnvVar{{Name: "K_SINK", Value: "http://example.com/"}},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	scheduled := time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC)
+
+	want := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "Steve-26033040",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "steve"},
+			Annotations: map[string]string{
+				"note":                       "hi",
+				ScheduledTimestampAnnotation: "2019-07-01T12:00:00Z",
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronjob, batchv1beta1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "SteveImage",
+						Image: "example-img",
+						Env: []corev1.EnvVar{
+							{Name: "K_SINK", Value: "http://example.com/"},
+							{Name: "K_SCHEDULED_TIME", Value: "2019-07-01T12:00:00Z"},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	got := MakeRunJob(cronjob, ScheduledJobName(cronjob, scheduled), scheduled)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected job (-want, +got) = %v", diff)
+	}
+
+	if len(cronjob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env) != 1 {
+		t.Errorf("MakeRunJob modified the CronJob's template")
+	}
+}
+
+func TestTriggeredJobName(t *testing.T) {
+	cronjob := &metav1.ObjectMeta{Name: "Steve"}
+
+	if a, b := TriggeredJobName(cronjob, "1"), TriggeredJobName(cronjob, "1"); a != b {
+		t.Errorf("TriggeredJobName is not stable: %q != %q", a, b)
+	}
+	if a, b := TriggeredJobName(cronjob, "1"), TriggeredJobName(cronjob, "2"); a == b {
+		t.Errorf("TriggeredJobName does not depend on the trigger: %q", a)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+const (
+	minutesPerDay = 24 * 60
+
+	// starBit marks a field of a cron.SpecSchedule that was given as "*".
+	starBit = 1 << 63
+)
+
+// TranslateSchedule returns a standard cron schedule that, run in UTC, ticks
+// at the same instants as schedule does in loc while loc is at the UTC offset
+// it has at the given time. Schedules whose ticks cannot be expressed in a
+// single UTC schedule, e.g. because some of them fall on the previous day in
+// UTC and others don't while the days are restricted, are an error.
+func TranslateSchedule(schedule string, loc *time.Location, at time.Time) (string, error) {
+	_, offset := at.In(loc).Zone()
+	if offset == 0 {
+		return schedule, nil
+	}
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return "", err
+	}
+	spec, ok := parsed.(*cron.SpecSchedule)
+	if !ok {
+		// Constant delays are the same in every time zone.
+		return schedule, nil
+	}
+
+	var hours, minutes uint64
+	pairs := make(map[int]bool)
+	shifts := make(map[int]bool)
+	for h := 0; h < 24; h++ {
+		if spec.Hour&(1<<uint(h)) == 0 {
+			continue
+		}
+		for m := 0; m < 60; m++ {
+			if spec.Minute&(1<<uint(m)) == 0 {
+				continue
+			}
+			utc := h*60 + m - offset/60
+			shift := 0
+			if utc < 0 {
+				shift = -1
+			} else if utc >= minutesPerDay {
+				shift = 1
+			}
+			utc -= shift * minutesPerDay
+			hours |= 1 << uint(utc/60)
+			minutes |= 1 << uint(utc%60)
+			pairs[utc] = true
+			shifts[shift] = true
+		}
+	}
+	if len(pairs) != bitCount(hours)*bitCount(minutes) {
+		return "", fmt.Errorf("the times of %q at UTC%s cannot be expressed in UTC", schedule, formatOffset(offset))
+	}
+
+	// Unlike for days, "*" means no more than every value for these.
+	if bitCount(minutes) == 60 {
+		minutes |= starBit
+	}
+	if bitCount(hours) == 24 {
+		hours |= starBit
+	}
+
+	dow := spec.Dow
+	if len(shifts) > 1 || !shifts[0] {
+		daysRestricted := spec.Dom&starBit == 0 || spec.Month&starBit == 0 || spec.Dow&starBit == 0
+		switch {
+		case !daysRestricted:
+		case len(shifts) == 1 && spec.Dom&starBit != 0 && spec.Month&starBit != 0:
+			// Only the days of the week are restricted, and every tick
+			// moves to the same other day.
+			for shift := range shifts {
+				dow = shiftWeekdays(spec.Dow, shift)
+			}
+		default:
+			return "", fmt.Errorf("the days of %q at UTC%s cannot be expressed in UTC", schedule, formatOffset(offset))
+		}
+	}
+
+	return strings.Join([]string{
+		formatField(minutes, 0, 59),
+		formatField(hours, 0, 23),
+		formatField(spec.Dom, 1, 31),
+		formatField(spec.Month, 1, 12),
+		formatField(dow, 0, 6),
+	}, " "), nil
+}
+
+// shiftWeekdays moves each day of the week in bits by shift days.
+func shiftWeekdays(bits uint64, shift int) uint64 {
+	var shifted uint64
+	for d := 0; d < 7; d++ {
+		if bits&(1<<uint(d)) != 0 {
+			shifted |= 1 << uint((d+shift+7)%7)
+		}
+	}
+	return shifted
+}
+
+// formatField returns the cron field that sets the given bits.
+func formatField(bits uint64, min, max int) string {
+	if bits&starBit != 0 {
+		return "*"
+	}
+	var values []string
+	for v := min; v <= max; v++ {
+		if bits&(1<<uint(v)) != 0 {
+			values = append(values, strconv.Itoa(v))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func bitCount(bits uint64) int {
+	count := 0
+	for ; bits != 0; bits &= bits - 1 {
+		count++
+	}
+	return count
+}
+
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, offset/3600, offset/60%60)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTranslateSchedule(t *testing.T) {
+	summer := time.Date(2019, time.July, 1, 0, 0, 0, 0, time.UTC)
+	winter := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule string
+		zone     string
+		at       time.Time
+		want     string
+		wantErr  bool
+	}{{
+		name:     "utc is unchanged",
+		schedule: "0 9 * * 1-5",
+		zone:     "UTC",
+		at:       summer,
+		want:     "0 9 * * 1-5",
+	}, {
+		name:     "summer time",
+		schedule: "0 9 * * 1-5",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		want:     "0 7 * * 1,2,3,4,5",
+	}, {
+		name:     "winter time",
+		schedule: "0 9 * * 1-5",
+		zone:     "Europe/Berlin",
+		at:       winter,
+		want:     "0 8 * * 1,2,3,4,5",
+	}, {
+		name:     "every hour",
+		schedule: "15 * * * *",
+		zone:     "America/New_York",
+		at:       summer,
+		want:     "15 * * * *",
+	}, {
+		name:     "half hour offset",
+		schedule: "0 * * * *",
+		zone:     "Asia/Kolkata",
+		at:       summer,
+		want:     "30 * * * *",
+	}, {
+		name:     "days of the week move",
+		schedule: "0 1 * * 1",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		want:     "0 23 * * 0",
+	}, {
+		name:     "unrestricted days",
+		schedule: "0 1,3 * * *",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		want:     "0 1,23 * * *",
+	}, {
+		name:     "constant delay",
+		schedule: "@every 1h",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		want:     "@every 1h",
+	}, {
+		name:     "some days move",
+		schedule: "0 1,3 * * 1",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		wantErr:  true,
+	}, {
+		name:     "days of the month move",
+		schedule: "0 1 1 * *",
+		zone:     "Europe/Berlin",
+		at:       summer,
+		wantErr:  true,
+	}, {
+		name:     "times do not line up",
+		schedule: "0,45 9,10 * * *",
+		zone:     "Asia/Kolkata",
+		at:       summer,
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(test.zone)
+			if err != nil {
+				t.Fatalf("LoadLocation() = %v", err)
+			}
+			got, err := TranslateSchedule(test.schedule, loc, test.at)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("TranslateSchedule() = %v, wanted error %t", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("TranslateSchedule() = %q, wanted %q", got, test.want)
+			}
+		})
+	}
+}
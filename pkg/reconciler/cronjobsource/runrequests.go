@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobsource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler/cronjobsource/resources"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// reconcileRunRequests starts the one-off runs requested through the
+// trigger-run and backfill annotations. Each value of an annotation is acted
+// on once.
+func (r *Reconciler) reconcileRunRequests(ctx context.Context, s *v1alpha1.CronJobSource, cronjob *batchv1beta1.CronJob) error {
+	if trigger, ok := s.Annotations[v1alpha1.TriggerRunAnnotation]; ok && trigger != s.Status.LastTriggerRun {
+		job := resources.MakeRunJob(cronjob, resources.TriggeredJobName(cronjob, trigger), r.Clock.Now())
+		if created, err := r.createRunJob(job); err != nil {
+			return err
+		} else if created {
+			r.Recorder.Eventf(s, corev1.EventTypeNormal, "RunTriggered", "Created Job %q", job.Name)
+		}
+		s.Status.LastTriggerRun = trigger
+	}
+
+	if backfill, ok := s.Annotations[v1alpha1.BackfillAnnotation]; ok && backfill != s.Status.LastBackfill {
+		times, err := s.Spec.BackfillTimes(backfill)
+		if err != nil {
+			// The webhook rejects bad ranges, but the schedule may have
+			// changed since the backfill was requested.
+			r.Recorder.Eventf(s, corev1.EventTypeWarning, "InvalidBackfill", "Ignoring backfill %q: %v", backfill, err)
+			s.Status.LastBackfill = backfill
+			return nil
+		}
+
+		count := 0
+		for _, t := range times {
+			job := resources.MakeRunJob(cronjob, resources.ScheduledJobName(cronjob, t), t)
+			if created, err := r.createRunJob(job); err != nil {
+				return err
+			} else if created {
+				count++
+			}
+		}
+		r.Recorder.Eventf(s, corev1.EventTypeNormal, "Backfilled", "Created %d of %d Jobs for backfill %q", count, len(times), backfill)
+		s.Status.LastBackfill = backfill
+	}
+
+	return nil
+}
+
+// createRunJob creates the Job for a requested run. It reports whether the
+// Job was created; a Job that already exists means the run already happened.
+func (r *Reconciler) createRunJob(job *batchv1.Job) (bool, error) {
+	_, err := r.KubeClientSet.BatchV1().Jobs(job.Namespace).Create(job)
+	if apierrs.IsAlreadyExists(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to create Job: %s", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjobsource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler/cronjobsource/resources"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// reconcileRuns records the outcome of the Jobs started by the CronJob in the
+// CronJobSource's status.
+func (r *Reconciler) reconcileRuns(ctx context.Context, s *v1alpha1.CronJobSource, cronjob *batchv1beta1.CronJob) error {
+	jobs, err := r.JobLister.Jobs(s.Namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list Jobs: %s", err)
+	}
+
+	runs := []v1alpha1.CronJobSourceRun{}
+	for _, job := range jobs {
+		if owner := metav1.GetControllerOf(job); owner == nil || owner.UID != cronjob.UID {
+			continue
+		}
+		runs = append(runs, makeRun(job))
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].ScheduledTime.Equal(runs[j].ScheduledTime) {
+			return runs[i].JobName < runs[j].JobName
+		}
+		return runs[i].ScheduledTime.Before(runs[j].ScheduledTime)
+	})
+
+	s.Status.RecordRuns(runs, s.Spec.GetRecentRunsLimit(), s.Spec.GetFailureThreshold())
+	return nil
+}
+
+// makeRun describes a Job started by a CronJob as a CronJobSourceRun.
+func makeRun(job *batchv1.Job) v1alpha1.CronJobSourceRun {
+	run := v1alpha1.CronJobSourceRun{
+		JobName:       job.Name,
+		ScheduledTime: scheduledTime(job),
+		StartTime:     job.Status.StartTime,
+		Result:        v1alpha1.CronJobSourceRunRunning,
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			run.Result = v1alpha1.CronJobSourceRunSucceeded
+			run.CompletionTime = job.Status.CompletionTime
+		case batchv1.JobFailed:
+			run.Result = v1alpha1.CronJobSourceRunFailed
+			run.Reason = c.Reason
+			run.Message = c.Message
+		default:
+			continue
+		}
+		if run.CompletionTime == nil && !c.LastTransitionTime.IsZero() {
+			run.CompletionTime = c.LastTransitionTime.DeepCopy()
+		}
+		break
+	}
+	return run
+}
+
+// scheduledTime returns the time the CronJob scheduled the Job for. Older
+// CronJob controllers only encode it in the Job's name, as minutes since the
+// epoch; failing that, the Job's creation time is a close approximation.
+func scheduledTime(job *batchv1.Job) *metav1.Time {
+	if ts, ok := job.Annotations[resources.ScheduledTimestampAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			return &metav1.Time{Time: t}
+		}
+	}
+	if i := strings.LastIndex(job.Name, "-"); i >= 0 {
+		if minutes, err := strconv.ParseInt(job.Name[i+1:], 10, 64); err == nil {
+			return &metav1.Time{Time: time.Unix(minutes*60, 0).UTC()}
+		}
+	}
+	return job.CreationTimestamp.DeepCopy()
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+const (
+	eventTypeLabelKey = "sources.knative.dev/eventtypes"
+)
+
+// EventTypeLister lists EventTypes from the cache of an informer made by
+// NewEventTypeInformer. The EventTypes it returns must not be modified.
+type EventTypeLister interface {
+	List(namespace string, selector labels.Selector) ([]*eventingv1alpha1.EventType, error)
+	Get(namespace, name string) (*eventingv1alpha1.EventType, error)
+}
+
+// NewEventTypeInformer returns an informer on the EventTypes in all
+// namespaces. The vendored eventing has no injected informer for them.
+func NewEventTypeInformer(client eventingclientset.Interface, resync time.Duration) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.EventingV1alpha1().EventTypes(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.EventingV1alpha1().EventTypes(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&eventingv1alpha1.EventType{},
+		resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+// NewEventTypeLister returns a lister on the indexer of an informer made by
+// NewEventTypeInformer.
+func NewEventTypeLister(indexer cache.Indexer) EventTypeLister {
+	return eventTypeLister{indexer: indexer}
+}
+
+type eventTypeLister struct {
+	indexer cache.Indexer
+}
+
+func (l eventTypeLister) List(namespace string, selector labels.Selector) ([]*eventingv1alpha1.EventType, error) {
+	var eventTypes []*eventingv1alpha1.EventType
+	err := cache.ListAllByNamespace(l.indexer, namespace, selector, func(obj interface{}) {
+		eventTypes = append(eventTypes, obj.(*eventingv1alpha1.EventType))
+	})
+	return eventTypes, err
+}
+
+func (l eventTypeLister) Get(namespace, name string) (*eventingv1alpha1.EventType, error) {
+	obj, exists, err := l.indexer.GetByKey(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrs.NewNotFound(eventingv1alpha1.Resource("eventtype"), name)
+	}
+	return obj.(*eventingv1alpha1.EventType), nil
+}
+
+func init() {
+	injection.Default.RegisterInformerFactory(withEventTypeInformer)
+}
+
+// eventTypeInformerKey is the context key of the sharedEventTypeInformer of
+// a controller process.
+type eventTypeInformerKey struct{}
+
+// sharedEventTypeInformer is the informer on EventTypes that the controllers
+// of a process share. The first of them to watch EventTypes makes and
+// starts it.
+type sharedEventTypeInformer struct {
+	once     sync.Once
+	informer cache.SharedIndexInformer
+}
+
+func withEventTypeInformer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, eventTypeInformerKey{}, &sharedEventTypeInformer{})
+}
+
+// getEventTypeInformer returns the started informer on EventTypes shared by
+// the controllers of the process, or a new one if the context has none.
+func getEventTypeInformer(ctx context.Context, client eventingclientset.Interface) cache.SharedIndexInformer {
+	shared, ok := ctx.Value(eventTypeInformerKey{}).(*sharedEventTypeInformer)
+	if !ok {
+		shared = &sharedEventTypeInformer{}
+	}
+	shared.once.Do(func() {
+		shared.informer = NewEventTypeInformer(client, controller.GetResyncPeriod(ctx))
+		// Don't wait for the cache to sync: the controller starts without
+		// EventTypes, for clusters that don't have them installed, and
+		// reconciles them once it synced.
+		go shared.informer.Run(ctx.Done())
+	})
+	return shared.informer
+}
+
+// WatchEventTypes makes the informer on EventTypes that the controllers of
+// the process share enqueue the sources of the given kind that own them, and
+// reads the EventTypes of sources from it once it synced.
+func (r *SourceReconciler) WatchEventTypes(ctx context.Context, impl *controller.Impl, kind string) {
+	informer := getEventTypeInformer(ctx, r.EventingClientSet)
+	informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind(kind)),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+	r.EventTypeLister = NewEventTypeLister(informer.GetIndexer())
+	r.EventTypesSynced = informer.HasSynced
+}
+
+// CloudEventSource returns the canonical CloudEvents source of the events
+// of a source, /apis/v1/namespaces/<namespace>/<plural of kind>/<name>.
+func CloudEventSource(source SourceObject) string {
+	plural := strings.ToLower(source.GetGroupVersionKind().Kind) + "s"
+	return fmt.Sprintf("/apis/v1/namespaces/%s/%s/%s", source.GetNamespace(), plural, source.GetName())
+}
+
+// MakeEventTypes returns the EventTypes that register the event types of
+// the source with the given Broker, which is in the namespace of the source.
+func MakeEventTypes(source SourceObject, broker string) []*eventingv1alpha1.EventType {
+	var eventTypes []*eventingv1alpha1.EventType
+	for _, et := range source.GetEventTypes() {
+		eventTypes = append(eventTypes, &eventingv1alpha1.EventType{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            EventTypeName(source, et.Type),
+				Namespace:       source.GetNamespace(),
+				Labels:          Labels(source, eventTypeLabelKey),
+				OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+			},
+			Spec: eventingv1alpha1.EventTypeSpec{
+				Type:        et.Type,
+				Source:      CloudEventSource(source),
+				Schema:      et.Schema,
+				Broker:      broker,
+				Description: et.Description,
+			},
+		})
+	}
+	return eventTypes
+}
+
+// EventTypeName returns the name of the EventType of the source for the
+// given CloudEvents type. Types may hold characters that names can't, so
+// the name is made from a hash of the type.
+func EventTypeName(source metav1.Object, eventType string) string {
+	return kmeta.ChildName(source.GetName(), "-"+SpecHash(eventType)[:10])
+}
+
+// reconcileEventTypes makes the EventTypes of a source match the event types
+// it declares, if its sink is a Broker in its namespace. Otherwise it
+// deletes the EventTypes of the source.
+func (r *SourceReconciler) reconcileEventTypes(ctx context.Context, source SourceObject) error {
+	if r.EventTypeLister == nil {
+		return nil
+	}
+	if r.EventTypesSynced != nil && !r.EventTypesSynced() {
+		// Until the cache synced, EventTypes that exist are missing from it.
+		r.EnqueueAfter(source, CacheLag)
+		return nil
+	}
+
+	var desired []*eventingv1alpha1.EventType
+	if broker := sinkBroker(source); broker != "" {
+		desired = MakeEventTypes(source, broker)
+	}
+
+	existing, err := r.EventTypeLister.List(source.GetNamespace(), labels.SelectorFromSet(map[string]string{
+		eventTypeLabelKey: source.GetName(),
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to list EventTypes: %s", err)
+	}
+	owned := make(map[string]*eventingv1alpha1.EventType)
+	for _, et := range existing {
+		if metav1.IsControlledBy(et, source) {
+			owned[et.Name] = et
+		}
+	}
+
+	client := r.EventingClientSet.EventingV1alpha1().EventTypes(source.GetNamespace())
+	for _, want := range desired {
+		have, ok := owned[want.Name]
+		delete(owned, want.Name)
+		if !ok {
+			if _, err := client.Create(want); apierrs.IsAlreadyExists(err) {
+				if _, err := r.EventTypeLister.Get(source.GetNamespace(), want.Name); apierrs.IsNotFound(err) {
+					// The cache hasn't seen the EventType yet.
+					return ErrNotCached("EventType", want.Name)
+				}
+				// Something else made an EventType of that name.
+				r.Recorder.Eventf(source, corev1.EventTypeWarning, "EventTypeNotOwned", "There is an existing EventType %q that the source does not own", want.Name)
+			} else if err != nil {
+				return fmt.Errorf("failed to create EventType: %s", err)
+			}
+			continue
+		}
+		if equality.Semantic.DeepEqual(have.Spec, want.Spec) {
+			continue
+		}
+		update := have.DeepCopy()
+		update.Spec = want.Spec
+		if _, err := client.Update(update); err != nil {
+			return fmt.Errorf("failed to update EventType: %s", err)
+		}
+	}
+
+	for name := range owned {
+		if err := client.Delete(name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete EventType: %s", err)
+		}
+	}
+	return nil
+}
+
+// sinkBroker returns the name of the Broker that the sink of the source
+// refers to, if it is one in the namespace of the source.
+func sinkBroker(source SourceObject) string {
+	ref := source.GetSink().ObjectReference
+	if ref == nil || ref.Kind != "Broker" {
+		return ""
+	}
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != eventingv1alpha1.SchemeGroupVersion.Group {
+		return ""
+	}
+	if ref.Namespace != "" && ref.Namespace != source.GetNamespace() {
+		// EventTypes can only refer to Brokers in their own namespace.
+		return ""
+	}
+	return ref.Name
+}
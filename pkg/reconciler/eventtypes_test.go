@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	eventingreconciler "knative.dev/eventing/pkg/reconciler"
+	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+func TestEventTypeInformerIsShared(t *testing.T) {
+	ctx, cancel := context.WithCancel(withEventTypeInformer(context.Background()))
+	defer cancel()
+	client := fake.NewSimpleClientset()
+
+	informer := getEventTypeInformer(ctx, client)
+	if got := getEventTypeInformer(ctx, client); got != informer {
+		t.Error("getEventTypeInformer() made a second informer for the same process")
+	}
+	// It is started, without getEventTypeInformer waiting for it to sync.
+	if !cache.WaitForCacheSync(stopAfter(time.Minute), informer.HasSynced) {
+		t.Error("The EventType informer never synced")
+	}
+}
+
+func TestReconcileEventTypesWaitsForSync(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	var enqueued []time.Duration
+	r := &SourceReconciler{
+		Base:             &Base{Base: &eventingreconciler.Base{EventingClientSet: client}},
+		EnqueueAfter:     func(_ interface{}, after time.Duration) { enqueued = append(enqueued, after) },
+		EventTypeLister:  NewEventTypeLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		EventTypesSynced: func() bool { return false },
+	}
+	source := &v1alpha1.JobSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-jobsource"},
+		Spec: v1alpha1.JobSourceSpec{
+			BaseSourceSpec: v1alpha1.BaseSourceSpec{
+				Sink: apisv1alpha1.Destination{ObjectReference: &corev1.ObjectReference{
+					APIVersion: "eventing.knative.dev/v1alpha1", Kind: "Broker", Name: "default",
+				}},
+				EventTypes: []v1alpha1.SourceEventType{{Type: "dev.knative.test"}},
+			},
+		},
+	}
+
+	if err := r.reconcileEventTypes(context.Background(), source); err != nil {
+		t.Fatalf("reconcileEventTypes() = %v", err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("reconcileEventTypes() wrote EventTypes before the cache synced: %v", actions)
+	}
+	if len(enqueued) != 1 || enqueued[0] != CacheLag {
+		t.Errorf("Enqueued after %v, want [%v]", enqueued, CacheLag)
+	}
+}
+
+// stopAfter returns a channel that is closed after d.
+func stopAfter(d time.Duration) <-chan struct{} {
+	stop := make(chan struct{})
+	time.AfterFunc(d, func() { close(stop) })
+	return stop
+}
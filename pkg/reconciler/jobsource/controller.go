@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
 )
 
 const (
@@ -43,10 +44,22 @@ func NewController(
 	jobInformer := jobinformer.Get(ctx)
 
 	r := &Reconciler{
-		Base:   reconciler.NewBase(ctx, "JobSource", cmw),
-		Lister: jsInformer.Lister(),
+		Base:      reconciler.NewBase(ctx, "JobSource", cmw),
+		Lister:    jsInformer.Lister(),
+		JobLister: jobInformer.Lister(),
+		Clock:     system.RealClock{},
 	}
-	impl := controller.NewImpl(r, r.Logger, "JobSources")
+	sr := &reconciler.SourceReconciler{
+		Base:        r.Base,
+		Kind:        r,
+		JobLister:   r.JobLister,
+		Clock:       r.Clock,
+		ConfigStore: reconciler.NewConfigStore(ctx, cmw),
+	}
+	impl := controller.NewImpl(sr, r.Logger, "JobSources")
+	r.EnqueueAfter = impl.EnqueueAfter
+	sr.EnqueueAfter = impl.EnqueueAfter
+	sr.WatchEventTypes(ctx, impl, "JobSource")
 
 	r.Logger.Info("Setting up event handlers for JobSources")
 
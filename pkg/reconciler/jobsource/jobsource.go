@@ -20,30 +20,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"reflect"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
 	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/jobsource/resources"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
 
 	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
 	"go.uber.org/zap"
-	"knative.dev/pkg/controller"
-	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
 )
 
 var (
 	errSinkMissing = errors.New("Sink missing from spec")
 )
 
-// Reconciler implements controller.Reconciler for JobSource resources.
+// Reconciler is the reconciler.Kind for JobSource resources.
 type Reconciler struct {
 	// +required
 	*reconciler.Base
@@ -51,79 +52,64 @@ type Reconciler struct {
 	// Lister allows us to query for JobSources
 	// +required
 	Lister listers.JobSourceLister
-}
 
-// Check that our Reconciler implements controller.Reconciler
-var _ controller.Reconciler = (*Reconciler)(nil)
+	// JobLister allows us to query for the Jobs of JobSources
+	// +required
+	JobLister batchv1listers.JobLister
 
-// Reconcile implements controller.Reconciler
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
-	logger := logging.FromContext(ctx)
+	// Clock is used to decide when a finished JobSource's TTL has expired.
+	// +required
+	Clock system.Clock
 
-	// Convert the namespace/name string into a distinct namespace and name
-	namespace, name, err := cache.SplitMetaNamespaceKey(key)
-	if err != nil {
-		logger.Errorf("invalid resource key: %s", key)
-		return nil
-	}
+	// EnqueueAfter requeues a JobSource so it is reconciled again once its
+	// TTL expires.
+	// +required
+	EnqueueAfter func(interface{}, time.Duration)
+}
 
-	// If our controller has configuration state, we'd "freeze" it and
-	// attach the frozen configuration to the context.
-	//    ctx = r.configStore.ToContext(ctx)
+// Check that our Reconciler is a kind of source.
+var (
+	_ reconciler.Kind     = (*Reconciler)(nil)
+	_ reconciler.SinkGate = (*Reconciler)(nil)
+)
 
-	// Get the resource with this namespace/name.
-	original, err := r.Lister.JobSources(namespace).Get(name)
-	if apierrs.IsNotFound(err) {
-		// The resource may no longer exist, in which case we stop processing.
-		logger.Errorf("resource %q no longer exists", key)
-		return nil
-	} else if err != nil {
-		return err
-	}
-	// Don't modify the informers copy.
-	resource := original.DeepCopy()
-
-	// Reconcile this copy of the resource and then write back any status
-	// updates regardless of whether the reconciliation errored out.
-	reconcileErr := r.reconcile(ctx, resource)
-	if equality.Semantic.DeepEqual(original.Status, resource.Status) {
-		// If we didn't change anything then don't call updateStatus.
-		// This is important because the copy we loaded from the informer's
-		// cache may be stale and we don't want to overwrite a prior update
-		// to status with this stale state.
-	} else if _, err = r.updateStatus(resource); err != nil {
-		logger.Warnw("Failed to update resource status", zap.Error(err))
-		r.Recorder.Eventf(resource, corev1.EventTypeWarning, "UpdateFailed",
-			"Failed to update status for %q: %v", resource.Name, err)
-		return err
-	}
-	if reconcileErr != nil {
-		r.Logger.Warnw("Internal error reconciling:", zap.Error(reconcileErr))
-		r.Recorder.Event(resource, corev1.EventTypeWarning, "InternalError", reconcileErr.Error())
-	}
-	return reconcileErr
+// SourceClient implements reconciler.Kind
+func (r *Reconciler) SourceClient() reconciler.SourceClient {
+	return jobSourceClient{lister: r.Lister, client: r.SourcesClientSet}
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, js *v1alpha1.JobSource) error {
+// ReconcileBeforeSink implements reconciler.SinkGate. It starts the run that
+// the spec requests, and is done with JobSources whose run has finished.
+func (r *Reconciler) ReconcileBeforeSink(ctx context.Context, source reconciler.SourceObject) (bool, error) {
+	js := source.(*v1alpha1.JobSource)
 
-	if js.GetDeletionTimestamp() != nil {
-		// Check for a DeletionTimestamp.  If present, elide the normal reconcile logic.
-		// When a controller needs finalizer handling, it would go here.
-		return nil
+	// A run that is in progress finishes before the next one starts.
+	if js.Spec.RunGeneration > js.Status.RunGeneration && !js.Status.IsJobRunning() {
+		if err := r.startRun(ctx, js); err != nil {
+			return true, err
+		}
 	}
-	js.Status.InitializeConditions()
 
-	// Having a sink is a prereq for starting the job, so we reconcile the sink first
-	if err := r.ReconcileSink(ctx, js); err != nil {
-		return err
+	if js.Status.IsFinished() {
+		// The Job has already finished and may have been cleaned up since.
+		// JobSources are one-shot, so neither the sink nor the Job matter
+		// anymore; only the TTL does.
+		js.Status.ObservedGeneration = js.Generation
+		return true, r.reconcileTTL(ctx, js)
 	}
+	return false, nil
+}
+
+// ReconcileKind implements reconciler.Kind. Having a sink is a prereq for
+// starting the job, so the sink is resolved by then.
+func (r *Reconciler) ReconcileKind(ctx context.Context, source reconciler.SourceObject) error {
+	js := source.(*v1alpha1.JobSource)
 
 	if err := r.reconcileJob(ctx, js); err != nil {
 		return err
 	}
 
-	js.Status.ObservedGeneration = js.Generation
-	return nil
+	return r.reconcileTTL(ctx, js)
 }
 
 // reconcileJob enforces the creation and lifecycle of the job. It assumes that the sink exists and is valid.
@@ -135,7 +121,10 @@ func (r *Reconciler) reconcileJob(ctx context.Context, js *v1alpha1.JobSource) e
 		job = resources.MakeJob(js)
 
 		job, err := r.KubeClientSet.BatchV1().Jobs(js.Namespace).Create(job)
-		if err != nil || job == nil {
+		if apierrs.IsAlreadyExists(err) {
+			// The cache hasn't seen the Job yet.
+			return reconciler.ErrNotCached("Job", resources.JobName(js))
+		} else if err != nil || job == nil {
 			msg := "Failed to make Job."
 			if err != nil {
 				msg = msg + " " + err.Error()
@@ -155,8 +144,10 @@ func (r *Reconciler) reconcileJob(ctx context.Context, js *v1alpha1.JobSource) e
 	// Job exists, check if it is done
 	if cond := getJobCompletedCondition(job); cond != nil && jobConditionSucceeded(cond) {
 		js.Status.MarkJobSucceeded()
+		r.markCompletionTime(js, job, cond)
 	} else if cond != nil && jobConditionFailed(cond) {
 		js.Status.MarkJobFailed(cond.Reason, cond.Message)
+		r.markCompletionTime(js, job, cond)
 	} else {
 		// Job is not finished, make sure the status reflects that
 		if !js.Status.IsJobRunning() {
@@ -167,25 +158,145 @@ func (r *Reconciler) reconcileJob(ctx context.Context, js *v1alpha1.JobSource) e
 	return nil
 }
 
-func (r *Reconciler) getJob(ctx context.Context, owner metav1.Object) (*batchv1.Job, error) {
-	return r.KubeClientSet.BatchV1().Jobs(owner.GetNamespace()).Get(resources.JobName(owner), metav1.GetOptions{})
+// startRun resets the JobSource's status for the run requested by its spec,
+// recording the outcome of the previous run in the run history. Jobs of runs
+// that no longer fit in the history are deleted.
+func (r *Reconciler) startRun(ctx context.Context, js *v1alpha1.JobSource) error {
+	limit := js.Spec.GetRunHistoryLimit()
+
+	// Only touch the status once the old Jobs are gone, so that a failed
+	// delete is retried rather than forgotten.
+	status := js.Status.DeepCopy()
+	evicted := status.StartRun(js.Spec.RunGeneration, resources.JobName(js), limit)
+	for _, run := range evicted {
+		if deleted, err := r.deleteJob(js, run.JobName); err != nil {
+			return err
+		} else if deleted {
+			r.Recorder.Eventf(js, corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q of run %d", run.JobName, run.RunGeneration)
+		}
+	}
+	js.Status = *status
+
+	r.Recorder.Eventf(js, corev1.EventTypeNormal, "RunStarted", "Starting run %d", js.Status.RunGeneration)
+	return nil
+}
+
+// markCompletionTime records when the Job finished, preferring the time
+// reported by the Job itself.
+func (r *Reconciler) markCompletionTime(js *v1alpha1.JobSource, job *batchv1.Job, cond *batchv1.JobCondition) {
+	if js.Status.CompletionTime != nil {
+		return
+	}
+	switch {
+	case job.Status.CompletionTime != nil:
+		js.Status.CompletionTime = job.Status.CompletionTime.DeepCopy()
+	case !cond.LastTransitionTime.IsZero():
+		js.Status.CompletionTime = cond.LastTransitionTime.DeepCopy()
+	default:
+		js.Status.CompletionTime = &metav1.Time{Time: r.Clock.Now()}
+	}
 }
 
-// Update the Status of the resource.  Caller is responsible for checking
-// for semantic differences before calling.
-func (r *Reconciler) updateStatus(desired *v1alpha1.JobSource) (*v1alpha1.JobSource, error) {
-	actual, err := r.Lister.JobSources(desired.Namespace).Get(desired.Name)
+// reconcileTTL deletes the Job, and optionally the JobSource, once the
+// JobSource has been finished for longer than its TTL. Before then it
+// requeues the JobSource for when the TTL expires.
+func (r *Reconciler) reconcileTTL(ctx context.Context, js *v1alpha1.JobSource) error {
+	if js.Spec.SourceTTLSecondsAfterFinished == nil || js.Status.CompletionTime == nil {
+		return nil
+	}
+
+	ttl := time.Duration(*js.Spec.SourceTTLSecondsAfterFinished) * time.Second
+	remaining := js.Status.CompletionTime.Add(ttl).Sub(r.Clock.Now())
+
+	// Only clean up once the completion time has been persisted, otherwise a
+	// failed status update would make the deleted Job look like it never ran.
+	if persisted, err := r.Lister.JobSources(js.Namespace).Get(js.Name); err != nil {
+		return err
+	} else if persisted.Status.CompletionTime == nil || persisted.Status.RunGeneration != js.Status.RunGeneration {
+		if remaining < 0 {
+			remaining = 0
+		}
+		r.EnqueueAfter(js, remaining)
+		return nil
+	}
+
+	if remaining > 0 {
+		r.EnqueueAfter(js, remaining)
+		return nil
+	}
+
+	if deleted, err := r.deleteJob(js, resources.JobName(js)); err != nil {
+		return err
+	} else if deleted {
+		r.Recorder.Eventf(js, corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q after TTL expired", resources.JobName(js))
+	}
+
+	if js.Spec.DeleteSourceAfterTTL {
+		err := r.SourcesClientSet.SourcesV1alpha1().JobSources(js.Namespace).Delete(js.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete JobSource: %s", err)
+		}
+	}
+	return nil
+}
+
+// deleteJob deletes the named Job and its Pods. It reports whether the Job
+// still existed.
+func (r *Reconciler) deleteJob(js *v1alpha1.JobSource, name string) (bool, error) {
+	// Jobs orphan their Pods by default; take the Pods with them.
+	propagation := metav1.DeletePropagationBackground
+	err := r.KubeClientSet.BatchV1().Jobs(js.Namespace).Delete(name, &metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if apierrs.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to delete Job: %s", err)
+	}
+	return true, nil
+}
+
+func (r *Reconciler) getJob(ctx context.Context, js *v1alpha1.JobSource) (*batchv1.Job, error) {
+	return r.JobLister.Jobs(js.Namespace).Get(resources.JobName(js))
+}
+
+// jobSourceClient reads JobSources and writes them for the SourceReconciler.
+type jobSourceClient struct {
+	lister listers.JobSourceLister
+	client clientset.Interface
+}
+
+var _ reconciler.SourceClient = jobSourceClient{}
+
+func (c jobSourceClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	var (
+		source *v1alpha1.JobSource
+		err    error
+	)
+	if fresh {
+		source, err = c.client.SourcesV1alpha1().JobSources(namespace).Get(name, metav1.GetOptions{})
+	} else {
+		source, err = c.lister.JobSources(namespace).Get(name)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// If there's nothing to update, just return.
-	if reflect.DeepEqual(actual.Status, desired.Status) {
-		return actual, nil
-	}
-	// Don't modify the informers copy
-	existing := actual.DeepCopy()
-	existing.Status = desired.Status
-	return r.SourcesClientSet.SourcesV1alpha1().JobSources(desired.Namespace).UpdateStatus(existing)
+	return source, nil
+}
+
+func (c jobSourceClient) UpdateStatus(source runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(source.(*v1alpha1.JobSource).Namespace).UpdateStatus(source.(*v1alpha1.JobSource))
+	return err
+}
+
+func (c jobSourceClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c jobSourceClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(namespace).Patch(name, types.MergePatchType, patch)
+	return err
 }
 
 // getJobCompletedCondition finds a JobCondition of the Job that has information about its completedness.
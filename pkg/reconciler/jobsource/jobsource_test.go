@@ -20,21 +20,22 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/n3wscott/sources/pkg/apis/config"
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/jobsource/resources"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 	"knative.dev/pkg/apis"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
-	"knative.dev/pkg/configmap"
-	"knative.dev/pkg/controller"
 	"knative.dev/pkg/ptr"
 
 	. "github.com/n3wscott/sources/pkg/reconciler/testing"
@@ -45,6 +46,8 @@ const (
 	jsName         = "my-jobsource"
 	jsUID          = "1234"
 	jsJobFixedName = jsName + "-jobsource-" + jsUID
+	jsJobRun1Name  = jsJobFixedName + "-1"
+	jsJobRun2Name  = jsJobFixedName + "-2"
 	sinkName       = "my-sink"
 	ns             = "default"
 	key            = ns + "/" + jsName
@@ -61,6 +64,9 @@ var (
 		APIVersion: "v1",
 		Kind:       "Service",
 	}))
+
+	now            = time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC)
+	completionTime = metav1.NewTime(now)
 )
 
 func init() {
@@ -77,6 +83,18 @@ func namedTestSink(name string) apisv1alpha1.Destination {
 	}))
 }
 
+// otherNamespaceSink is a sink in a namespace other than that of the
+// JobSource.
+func otherNamespaceSink() apisv1alpha1.Destination {
+	dest := namedTestSink(sinkName)
+	dest.ObjectReference.Namespace = "other"
+	return dest
+}
+
+// denyCrossNamespaceSinks are configured defaults that deny sinks in other
+// namespaces.
+var denyCrossNamespaceSinks, _ = config.NewDefaultsFromMap(map[string]string{"cross-namespace-sinks": "deny"})
+
 // destMust eats errors related to destination creation, which should not happen for our known test inputs.
 func destMust(dest *apisv1alpha1.Destination, err error) apisv1alpha1.Destination {
 	if err != nil {
@@ -85,6 +103,13 @@ func destMust(dest *apisv1alpha1.Destination, err error) apisv1alpha1.Destinatio
 	return *dest
 }
 
+// withoutRunHistoryLimit clears the RunHistoryLimit that NewJobSource
+// defaults, like that of a JobSource stored before it was defaulted.
+func withoutRunHistoryLimit(js *v1alpha1.JobSource) *v1alpha1.JobSource {
+	js.Spec.RunHistoryLimit = nil
+	return js
+}
+
 func newUnstructuredSink(scheme, hostname string) *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -115,7 +140,7 @@ func TestJobSource(t *testing.T) {
 	}, {
 		Name: "missing sink in spec causes errors",
 		Objects: []runtime.Object{
-			NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Status.InitializeConditions()
 			}),
@@ -123,7 +148,7 @@ func TestJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 
 				js.Status.InitializeConditions()
@@ -136,7 +161,7 @@ func TestJobSource(t *testing.T) {
 	}, {
 		Name: "sink not existing causes errors",
 		Objects: []runtime.Object{
-			NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Status.InitializeConditions()
 				js.Spec.Sink = namedTestSink("dne") // does not exist
@@ -146,7 +171,7 @@ func TestJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Spec.Sink = namedTestSink("dne")
 
@@ -157,10 +182,58 @@ func TestJobSource(t *testing.T) {
 		WantEvents: []string{
 			Eventf(corev1.EventTypeWarning, "InternalError", `failed to get ref %+v: sinks.testing.eventing.knative.dev "dne" not found`, namedTestSink("dne")),
 		},
+	}, {
+		Name: "sink in a namespace that is not permitted causes errors",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Status.InitializeConditions()
+				js.Spec.Sink = otherNamespaceSink()
+			}),
+		},
+		Key:     key,
+		Ctx:     config.ToContext(context.Background(), &config.Config{Defaults: denyCrossNamespaceSinks}),
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = otherNamespaceSink()
+
+				js.Status.InitializeConditions()
+				js.Status.MarkNoSink("SinkNotPermitted", `Sinks in namespace "other" are not permitted for sources in namespace %q`, ns)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", `sink in namespace "other" is not permitted`),
+		},
+	}, {
+		Name: "sink uri of a service in a namespace that is not permitted causes errors",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Status.InitializeConditions()
+				js.Spec.Sink = apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "sink.other.svc.cluster.local"}}
+			}),
+		},
+		Key:     key,
+		Ctx:     config.ToContext(context.Background(), &config.Config{Defaults: denyCrossNamespaceSinks}),
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = apisv1alpha1.Destination{URI: &apis.URL{Scheme: "http", Host: "sink.other.svc.cluster.local"}}
+
+				js.Status.InitializeConditions()
+				js.Status.MarkNoSink("SinkNotPermitted", `Sinks in namespace "other" are not permitted for sources in namespace %q`, ns)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", `sink in namespace "other" is not permitted`),
+		},
 	}, {
 		Name: "sink with bad address causes errors",
 		Objects: []runtime.Object{
-			NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Status.InitializeConditions()
 				js.Spec.Sink = namedTestSink(sinkName)
@@ -172,7 +245,7 @@ func TestJobSource(t *testing.T) {
 		Key:     key,
 		WantErr: true,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Spec.Sink = namedTestSink(sinkName)
 
@@ -211,6 +284,36 @@ func TestJobSource(t *testing.T) {
 				js.Status.MarkSink(sinkURI)
 			}),
 		)},
+	}, {
+		Name: "job missing from the cache is not created again",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Status.InitializeConditions()
+				js.Spec.Sink = svcSink
+			}),
+		},
+		Key: key,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceAlreadyExists("jobs"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}),
+		}},
+		WantCreates: []runtime.Object{resources.MakeJob(
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}),
+		)},
 	}, {
 		Name: "having uri sink starts a job",
 		Objects: []runtime.Object{
@@ -302,19 +405,20 @@ func TestJobSource(t *testing.T) {
 				js.Status.InitializeConditions()
 				js.Status.MarkSink(sinkURI)
 				js.Status.MarkJobSucceeded()
+				js.Status.CompletionTime = &completionTime
 			}),
 		}},
 	}, {
 		Name: "job failed implies jobsource failed",
 		Objects: []runtime.Object{
-			NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Spec.Sink = svcSink
 				js.Status.InitializeConditions()
 				js.Status.MarkSink(sinkURI)
 				js.Status.MarkJobRunning("Created Job %q.", jsJobFixedName)
 			}),
-			NewJob(NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Spec.Sink = svcSink
 				js.Status.InitializeConditions()
@@ -332,13 +436,14 @@ func TestJobSource(t *testing.T) {
 		},
 		Key: key,
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: NewJobSource(jsName, func(js *v1alpha1.JobSource) {
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
 				js.UID = jsUID
 				js.Spec.Sink = svcSink
 
 				js.Status.InitializeConditions()
 				js.Status.MarkSink(sinkURI)
 				js.Status.MarkJobFailed(failreason, failmessage)
+				js.Status.CompletionTime = &completionTime
 			}),
 		}},
 	}, {
@@ -390,12 +495,344 @@ func TestJobSource(t *testing.T) {
 		},
 		Key: key,
 		// Expect nothing to happen
+	}, {
+		Name: "job completion time is copied from the job",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobFixedName)
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}), WithJobCompleted(metav1.NewTime(now.Add(-time.Minute)))),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobSucceeded()
+				js.Status.CompletionTime = &metav1.Time{Time: now.Add(-time.Minute)}
+			}),
+		}},
+	}, {
+		Name: "finished job is not recreated after it is cleaned up",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+			}),
+			// No Job
+		},
+		Key: key,
+		// Expect nothing to happen
+	}, {
+		Name: "ttl not yet expired keeps the job",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-30*time.Second)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(60)
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}), WithJobCompleted(metav1.NewTime(now.Add(-30*time.Second)))),
+		},
+		Key: key,
+		// Expect nothing to happen; the JobSource is requeued instead.
+	}, {
+		Name: "ttl expired deletes the job",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-2*time.Minute)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(60)
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}), WithJobCompleted(metav1.NewTime(now.Add(-2*time.Minute)))),
+		},
+		Key: key,
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			Name: jsJobFixedName,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q after TTL expired", jsJobFixedName),
+		},
+	}, {
+		Name: "ttl expired deletes the job and the jobsource",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-2*time.Minute)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(60)
+				js.Spec.DeleteSourceAfterTTL = true
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}), WithJobCompleted(metav1.NewTime(now.Add(-2*time.Minute)))),
+		},
+		Key: key,
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			Name: jsName,
+		}, {
+			Name: jsJobFixedName,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q after TTL expired", jsJobFixedName),
+		},
+	}, {
+		Name: "ttl does not clean up before the completion time is persisted",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(0)
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobFixedName)
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}), WithJobCompleted(completionTime)),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(0)
+
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobSucceeded()
+				js.Status.CompletionTime = &completionTime
+			}),
+		}},
+	}, {
+		Name: "increasing the run generation starts a new job",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+
+				js.Status.StartRun(1, jsJobFixedName, 5)
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobRun1Name)
+			}),
+		}},
+		WantCreates: []runtime.Object{resources.MakeJob(
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+				js.Status.RunGeneration = 1
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}),
+		)},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "RunStarted", "Starting run 1"),
+		},
+	}, {
+		Name: "a jobsource stored without a run history limit keeps the default history",
+		Objects: []runtime.Object{
+			withoutRunHistoryLimit(NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+			})),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+			}), WithJobCompleted(metav1.NewTime(now.Add(-time.Hour)))),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withoutRunHistoryLimit(NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+
+				js.Status.StartRun(1, jsJobFixedName, v1alpha1.DefaultRunHistoryLimit)
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobRun1Name)
+			})),
+		}},
+		WantCreates: []runtime.Object{resources.MakeJob(
+			withoutRunHistoryLimit(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+				js.Status.RunGeneration = 1
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			})),
+		)},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "RunStarted", "Starting run 1"),
+		},
+	}, {
+		Name: "a running job finishes before the next run starts",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 1
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobFixedName)
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			})),
+		},
+		Key: key,
+		// Expect nothing to happen
+	}, {
+		Name: "runs that fall out of the history have their job deleted",
+		Objects: []runtime.Object{
+			NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-time.Hour)), func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 2
+				js.Spec.RunHistoryLimit = ptr.Int32(1)
+				js.Status.RunGeneration = 1
+				js.Status.RunHistory = []v1alpha1.JobSourceRun{{
+					RunGeneration:  0,
+					JobName:        jsJobFixedName,
+					CompletionTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+					Succeeded:      true,
+				}}
+			}),
+			NewJob(NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+			}), WithJobCompleted(metav1.NewTime(now.Add(-2*time.Hour)))),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 2
+				js.Spec.RunHistoryLimit = ptr.Int32(1)
+				js.Status.RunGeneration = 2
+				js.Status.RunHistory = []v1alpha1.JobSourceRun{{
+					RunGeneration:  1,
+					JobName:        jsJobRun1Name,
+					CompletionTime: &metav1.Time{Time: now.Add(-time.Hour)},
+					Succeeded:      true,
+				}}
+
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+				js.Status.MarkJobRunning("Created Job %q.", jsJobRun2Name)
+			}),
+		}},
+		WantCreates: []runtime.Object{resources.MakeJob(
+			NewJobSource(jsName, WithFakeJobContainer, func(js *v1alpha1.JobSource) {
+				js.UID = jsUID
+				js.Spec.Sink = svcSink
+				js.Spec.RunGeneration = 2
+				js.Spec.RunHistoryLimit = ptr.Int32(1)
+				js.Status.RunGeneration = 2
+				js.Status.InitializeConditions()
+				js.Status.MarkSink(sinkURI)
+			}),
+		)},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			Name: jsJobFixedName,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q of run %d", jsJobFixedName, 0),
+			Eventf(corev1.EventTypeNormal, "RunStarted", "Starting run 2"),
+		},
 	}}
 
-	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+	table.Test(t, MakeSourceFactory("JobSource", FakeClock{Time: now}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
 		return &Reconciler{
-			Base:   reconciler.NewBase(ctx, "JobSource", cmw),
-			Lister: listers.GetJobSourceLister(),
+			Base:         base,
+			Lister:       listers.GetJobSourceLister(),
+			JobLister:    listers.GetJobLister(),
+			Clock:        FakeClock{Time: now},
+			EnqueueAfter: func(interface{}, time.Duration) {},
 		}
 	}))
 }
+
+func TestReconcileTTLRequeue(t *testing.T) {
+	finished := NewJobSource(jsName, WithFakeJobContainer, WithJobSourceFinishedAt(now.Add(-20*time.Second)), func(js *v1alpha1.JobSource) {
+		js.UID = jsUID
+		js.Spec.SourceTTLSecondsAfterFinished = ptr.Int32(60)
+	})
+	unpersisted := finished.DeepCopy()
+	unpersisted.Status.CompletionTime = nil
+
+	tests := []struct {
+		name      string
+		persisted *v1alpha1.JobSource
+		want      time.Duration
+	}{{
+		name:      "requeued for the rest of the ttl",
+		persisted: finished,
+		want:      40 * time.Second,
+	}, {
+		name:      "requeued for the rest of the ttl until the completion time is persisted",
+		persisted: unpersisted,
+		want:      40 * time.Second,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			listers := NewListers([]runtime.Object{test.persisted})
+			var requeues []time.Duration
+			r := &Reconciler{
+				Lister: listers.GetJobSourceLister(),
+				Clock:  FakeClock{Time: now},
+				EnqueueAfter: func(_ interface{}, d time.Duration) {
+					requeues = append(requeues, d)
+				},
+			}
+
+			if err := r.reconcileTTL(context.Background(), finished.DeepCopy()); err != nil {
+				t.Fatalf("reconcileTTL() = %v", err)
+			}
+			if len(requeues) != 1 || requeues[0] != test.want {
+				t.Errorf("EnqueueAfter() called with %v, want [%v]", requeues, test.want)
+			}
+		})
+	}
+}
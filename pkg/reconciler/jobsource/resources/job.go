@@ -44,15 +44,15 @@ func MakeJob(js *v1alpha1.JobSource) *batchv1.Job {
 		if c.Name == "" {
 			c.Name = fmt.Sprintf("jobsource%d", i)
 		}
-		c.Env = append(c.Env, corev1.EnvVar{Name: "K_SINK", Value: js.Status.SinkURI})
-		c.Env = append(c.Env, corev1.EnvVar{Name: "K_OUTPUT_FORMAT", Value: string(js.Spec.OutputFormat)})
+		c.Env = append(c.Env, reconciler.SinkEnv(js.Status.SinkURI, js.Spec.OutputFormat)...)
+		c.Env = append(c.Env, reconciler.SourceEnv(js)...)
 		containers = append(containers, c)
 	}
 	podTemplate.Spec.Containers = containers
 
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            JobName(js.GetObjectMeta()),
+			Name:            JobName(js),
 			Namespace:       js.GetObjectMeta().GetNamespace(),
 			Labels:          reconciler.Labels(js, labelKey),
 			Annotations:     reconciler.Annotations(js),
@@ -69,6 +69,17 @@ func MakeJob(js *v1alpha1.JobSource) *batchv1.Job {
 	return job
 }
 
-func JobName(owner metav1.Object) string {
-	return utils.GenerateFixedName(owner, owner.GetName()+"-jobsource-")
+// JobName returns the name of the Job for the JobSource's current run.
+func JobName(js *v1alpha1.JobSource) string {
+	return RunJobName(js, js.Status.RunGeneration)
+}
+
+// RunJobName returns the name of the Job for the given run. The initial run
+// keeps the name used before JobSources could be re-run.
+func RunJobName(owner metav1.Object, run int64) string {
+	name := utils.GenerateFixedName(owner, owner.GetName()+"-jobsource-")
+	if run == 0 {
+		return name
+	}
+	return kmeta.ChildName(name, fmt.Sprintf("-%d", run))
 }
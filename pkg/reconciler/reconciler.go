@@ -19,18 +19,36 @@ package reconciler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 
+	"github.com/n3wscott/sources/pkg/apis/config"
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
 	sourcesclient "github.com/n3wscott/sources/pkg/client/injection/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingreconciler "knative.dev/eventing/pkg/reconciler"
+	nsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 )
 
 var (
 	ErrSinkMissing = errors.New("Sink missing from spec")
+
+	// sinkProber is shared by the controllers of a process, so that sources
+	// of different kinds that share a sink share its probes.
+	sinkProber = NewSinkProber(&http.Client{}, system.RealClock{})
+)
+
+const (
+	// SinkNotPermittedReason is the reason of the SinkProvided condition
+	// of sources whose sink is in a namespace that the cross-namespace
+	// sink policy doesn't permit.
+	SinkNotPermittedReason = "SinkNotPermitted"
 )
 
 // Base is a set of tools that source reconcilers need.
@@ -46,6 +64,11 @@ type Base struct {
 	// Used by all Sources to resolve their sink.
 	// +required
 	SinkResolver *resolver.URIResolver
+
+	// SinkProber probes the sink URIs of sources when the configuration of
+	// sources asks for it. Without it, sinks are not probed.
+	// +optional
+	SinkProber *SinkProber
 }
 
 func NewBase(ctx context.Context, controllerAgentName string, cmw configmap.Watcher) *Base {
@@ -57,10 +80,19 @@ func NewBase(ctx context.Context, controllerAgentName string, cmw configmap.Watc
 	// impl := controller.NewImpl(r, logger, controllerAgentName)
 	// r.sinkReconciler = resolver.NewURIResolver(ctx, impl.EnqueueKey)
 	base.SinkResolver = resolver.NewURIResolver(ctx, func(_ string) {})
+	base.SinkProber = sinkProber
 
 	return base
 }
 
+// NewConfigStore returns a store of the configuration of sources in
+// config-sources, which namespaces override by label, that watches cmw.
+func NewConfigStore(ctx context.Context, cmw configmap.Watcher) *config.Store {
+	store := config.NewStore(logging.FromContext(ctx).Named("config-store"), nsinformer.Get(ctx).Lister())
+	store.WatchConfigs(cmw)
+	return store
+}
+
 func (r *Base) ReconcileSink(ctx context.Context, source v1alpha1.Source) error {
 	dest := source.GetSink()
 
@@ -74,6 +106,17 @@ func (r *Base) ReconcileSink(ctx context.Context, source v1alpha1.Source) error
 		dest.ObjectReference.Namespace = source.GetNamespace()
 	}
 
+	// A sink URI is subject to the policy if it addresses a Service by its
+	// cluster-local host name.
+	sinkNamespace := config.ServiceNamespace(dest.URI)
+	if dest.ObjectReference != nil {
+		sinkNamespace = dest.ObjectReference.Namespace
+	}
+	if !config.FromContextOrDefaults(ctx).SinkPermitted(source.GetNamespace(), sinkNamespace) {
+		source.GetStatus().MarkNoSink(SinkNotPermittedReason, "Sinks in namespace %q are not permitted for sources in namespace %q", sinkNamespace, source.GetNamespace())
+		return fmt.Errorf("sink in namespace %q is not permitted", sinkNamespace)
+	}
+
 	uri, err := r.SinkResolver.URIFromDestination(dest, source)
 	if err != nil {
 		source.GetStatus().MarkNoSink("NotFound", "Could not resolve sink URI: %v", err)
@@ -85,6 +128,21 @@ func (r *Base) ReconcileSink(ctx context.Context, source v1alpha1.Source) error
 	return nil
 }
 
+// Owns reports whether owner controls child, the existing object of the name
+// that owner gives its child. If nothing controls child and owner has the
+// adopt annotation, owner becomes its controller and adopted is true; the
+// caller must then update child.
+func Owns(owner kmeta.OwnerRefable, child metav1.Object) (owns, adopted bool) {
+	if metav1.IsControlledBy(child, owner.GetObjectMeta()) {
+		return true, false
+	}
+	if metav1.GetControllerOf(child) != nil || owner.GetObjectMeta().GetAnnotations()[v1alpha1.AdoptAnnotation] != "true" {
+		return false, false
+	}
+	child.SetOwnerReferences(append(child.GetOwnerReferences(), *kmeta.NewControllerRef(owner)))
+	return true, true
+}
+
 func Labels(owner kmeta.OwnerRefable, labelKey string) map[string]string {
 	labels := make(map[string]string)
 	copyMap(labels, owner.GetObjectMeta().GetLabels())
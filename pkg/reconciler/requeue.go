@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"fmt"
+	"time"
+)
+
+// CacheLag is how long SourceReconciler waits before it reconciles a source
+// again when informer caches don't have an object that the API server
+// already has, such as a child that was just created.
+const CacheLag = time.Second
+
+// RequeueError asks SourceReconciler to reconcile the source again after a
+// while, rather than to report an error. Status changes made before it was
+// returned are still written.
+type RequeueError struct {
+	// After is how long to wait before reconciling the source again.
+	After time.Duration
+
+	// Reason is why the source is reconciled again.
+	Reason string
+}
+
+func (e *RequeueError) Error() string {
+	return fmt.Sprintf("requeue after %v: %s", e.After, e.Reason)
+}
+
+// ErrNotCached returns the RequeueError for a kind of the given name that
+// could not be created because it exists, but is not in the informer cache
+// yet.
+func ErrNotCached(kind, name string) error {
+	return &RequeueError{After: CacheLag, Reason: fmt.Sprintf("%s %q exists but is not in the cache yet", kind, name)}
+}
@@ -22,12 +22,14 @@ import (
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	servicesourceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/servicesource"
 	"github.com/n3wscott/sources/pkg/reconciler"
+	jobinformer "knative.dev/pkg/client/injection/kube/informers/batch/v1/job"
 
+	"go.uber.org/zap"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
 	servingclient "knative.dev/serving/pkg/client/injection/client"
-	serviceinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1alpha1/service"
 )
 
 const (
@@ -41,20 +43,44 @@ func NewController(
 ) *controller.Impl {
 
 	serviceSourceInformer := servicesourceinformer.Get(ctx)
-	svcInformer := serviceinformer.Get(ctx)
+	jobInformer := jobinformer.Get(ctx)
 
 	r := &Reconciler{
-		Base:             reconciler.NewBase(ctx, "ServiceSource", cmw),
-		Lister:           serviceSourceInformer.Lister(),
-		ServingClientSet: servingclient.Get(ctx),
+		Base:   reconciler.NewBase(ctx, "ServiceSource", cmw),
+		Lister: serviceSourceInformer.Lister(),
 	}
-	impl := controller.NewImpl(r, r.Logger, "ServiceSources")
+	r.Services = NewServiceClient(r.KubeClientSet.Discovery(), servingclient.Get(ctx), r.DynamicClientSet)
+	sr := &reconciler.SourceReconciler{
+		Base:        r.Base,
+		Kind:        r,
+		JobLister:   jobInformer.Lister(),
+		Clock:       system.RealClock{},
+		ConfigStore: reconciler.NewConfigStore(ctx, cmw),
+	}
+	impl := controller.NewImpl(sr, r.Logger, "ServiceSources")
+	sr.EnqueueAfter = impl.EnqueueAfter
+	sr.WatchEventTypes(ctx, impl, "ServiceSource")
 
 	r.Logger.Info("Setting up event handlers for ServiceSources")
 
 	serviceSourceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
-	svcInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+	// The informer watches the same version of Services that the reconciler
+	// writes. It is not one of the injected ones, which would wait forever
+	// on a version that isn't served.
+	r.Logger.Infof("Watching Services in %s", r.Services.GroupVersion())
+	svcInformer := r.Services.NewInformer(controller.GetResyncPeriod(ctx))
+	svcInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("ServiceSource")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+	r.ServiceLister = r.Services.Lister(svcInformer.GetIndexer())
+	if err := reconciler.StartInformer(ctx, svcInformer); err != nil {
+		r.Logger.Fatalw("Failed to sync the Service informer", zap.Error(err))
+	}
+
+	// Cleanup Jobs run while ServiceSources are deleted.
+	jobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("ServiceSource")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
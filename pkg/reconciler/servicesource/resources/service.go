@@ -42,8 +42,8 @@ func MakeService(source *v1alpha1.ServiceSource) *servingv1beta1.Service {
 		if c.Name == "" {
 			c.Name = fmt.Sprintf("servicesource%d", i)
 		}
-		c.Env = append(c.Env, corev1.EnvVar{Name: "K_SINK", Value: source.Status.SinkURI})
-		c.Env = append(c.Env, corev1.EnvVar{Name: "K_OUTPUT_FORMAT", Value: string(source.Spec.OutputFormat)})
+		c.Env = append(c.Env, reconciler.SinkEnv(source.Status.SinkURI, source.Spec.OutputFormat)...)
+		c.Env = append(c.Env, reconciler.SourceEnv(source)...)
 		containers = append(containers, c)
 	}
 	podTemplate.Spec.Containers = containers
@@ -0,0 +1,172 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicesource
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
+	servingclientset "knative.dev/serving/pkg/client/clientset/versioned"
+	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1beta1"
+
+	"github.com/n3wscott/sources/pkg/reconciler"
+)
+
+var (
+	// servingV1 serves Services from Knative Serving 0.9 on.
+	servingV1 = schema.GroupVersion{Group: "serving.knative.dev", Version: "v1"}
+
+	// servingV1beta1 serves Services from Knative Serving 0.7 on.
+	servingV1beta1 = servingv1beta1.SchemeGroupVersion
+)
+
+// ServiceClient writes and watches Knative Services in the version of the
+// serving API that the cluster serves them in. Services are
+// servingv1beta1.Service in memory either way; v1 has the same shape.
+type ServiceClient interface {
+	// GroupVersion is the version of the serving API in use.
+	GroupVersion() schema.GroupVersion
+
+	Create(service *servingv1beta1.Service) (*servingv1beta1.Service, error)
+	Update(service *servingv1beta1.Service) (*servingv1beta1.Service, error)
+
+	// NewInformer returns an informer on the Services in all namespaces.
+	NewInformer(resync time.Duration) cache.SharedIndexInformer
+
+	// Lister returns a lister on the indexer of an informer made by
+	// NewInformer.
+	Lister(indexer cache.Indexer) ServiceLister
+}
+
+// ServiceLister gets Services from the cache of an informer. The Services
+// it returns must not be modified.
+type ServiceLister interface {
+	Get(namespace, name string) (*servingv1beta1.Service, error)
+}
+
+// NewServiceClient asks the cluster through discovery whether it serves v1
+// Services, and falls back to v1beta1 if it doesn't.
+func NewServiceClient(discovery discovery.DiscoveryInterface, servingClient servingclientset.Interface, dynamicClient dynamic.Interface) ServiceClient {
+	if reconciler.ServesResource(discovery, servingV1, "services") {
+		return &dynamicServiceClient{resource: reconciler.DynamicResource{
+			GVR:    servingV1.WithResource("services"),
+			Kind:   "Service",
+			Client: dynamicClient,
+		}}
+	}
+	return &v1beta1ServiceClient{client: servingClient}
+}
+
+// v1beta1ServiceClient uses the typed client for v1beta1.
+type v1beta1ServiceClient struct {
+	client servingclientset.Interface
+}
+
+var _ ServiceClient = (*v1beta1ServiceClient)(nil)
+
+func (c *v1beta1ServiceClient) GroupVersion() schema.GroupVersion {
+	return servingV1beta1
+}
+
+func (c *v1beta1ServiceClient) Create(service *servingv1beta1.Service) (*servingv1beta1.Service, error) {
+	return c.client.ServingV1beta1().Services(service.Namespace).Create(service)
+}
+
+func (c *v1beta1ServiceClient) Update(service *servingv1beta1.Service) (*servingv1beta1.Service, error) {
+	return c.client.ServingV1beta1().Services(service.Namespace).Update(service)
+}
+
+func (c *v1beta1ServiceClient) NewInformer(resync time.Duration) cache.SharedIndexInformer {
+	services := c.client.ServingV1beta1().Services(metav1.NamespaceAll)
+	return reconciler.NewListWatchInformer(
+		func(options metav1.ListOptions) (runtime.Object, error) {
+			return services.List(options)
+		},
+		func(options metav1.ListOptions) (watch.Interface, error) {
+			return services.Watch(options)
+		},
+		&servingv1beta1.Service{}, resync)
+}
+
+func (c *v1beta1ServiceClient) Lister(indexer cache.Indexer) ServiceLister {
+	return v1beta1ServiceLister{lister: servinglisters.NewServiceLister(indexer)}
+}
+
+type v1beta1ServiceLister struct {
+	lister servinglisters.ServiceLister
+}
+
+func (l v1beta1ServiceLister) Get(namespace, name string) (*servingv1beta1.Service, error) {
+	return l.lister.Services(namespace).Get(name)
+}
+
+// dynamicServiceClient goes through the dynamic client, for versions of the
+// serving API that the vendored serving client has no types for.
+type dynamicServiceClient struct {
+	resource reconciler.DynamicResource
+}
+
+var _ ServiceClient = (*dynamicServiceClient)(nil)
+
+func (c *dynamicServiceClient) GroupVersion() schema.GroupVersion {
+	return c.resource.GVR.GroupVersion()
+}
+
+func (c *dynamicServiceClient) Create(service *servingv1beta1.Service) (*servingv1beta1.Service, error) {
+	created := &servingv1beta1.Service{}
+	if err := c.resource.Create(service.Namespace, service, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (c *dynamicServiceClient) Update(service *servingv1beta1.Service) (*servingv1beta1.Service, error) {
+	updated := &servingv1beta1.Service{}
+	if err := c.resource.Update(service.Namespace, service, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (c *dynamicServiceClient) NewInformer(resync time.Duration) cache.SharedIndexInformer {
+	return c.resource.NewInformer(resync)
+}
+
+func (c *dynamicServiceClient) Lister(indexer cache.Indexer) ServiceLister {
+	return dynamicServiceLister{resource: &c.resource, indexer: indexer}
+}
+
+// dynamicServiceLister converts the unstructured Services in the cache.
+type dynamicServiceLister struct {
+	resource *reconciler.DynamicResource
+	indexer  cache.Indexer
+}
+
+func (l dynamicServiceLister) Get(namespace, name string) (*servingv1beta1.Service, error) {
+	service := &servingv1beta1.Service{}
+	if err := l.resource.Get(l.indexer, namespace, name, service); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
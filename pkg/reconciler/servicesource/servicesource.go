@@ -19,27 +19,21 @@ package servicesource
 import (
 	"context"
 	"errors"
-	"fmt"
-	"reflect"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
 	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
 	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/servicesource/resources"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
-	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/tools/cache"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
-	"github.com/google/go-cmp/cmp"
-	"go.uber.org/zap"
 	"knative.dev/pkg/apis"
-	"knative.dev/pkg/controller"
 	_ "knative.dev/pkg/logging"
 	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
-	servingclientset "knative.dev/serving/pkg/client/clientset/versioned"
 )
 
 const (
@@ -51,7 +45,7 @@ var (
 	ErrServiceReadyConditionMissing = errors.New("service does not have a ready condition")
 )
 
-// Reconciler implements controller.Reconciler for ServiceSource resources.
+// Reconciler is the reconciler.Kind for ServiceSource resources.
 type Reconciler struct {
 	// +required
 	*reconciler.Base
@@ -60,118 +54,80 @@ type Reconciler struct {
 	// +required
 	Lister listers.ServiceSourceLister
 
-	// ServingClientSet is for querying Knative Serving Services
-	ServingClientSet servingclientset.Interface
-}
-
-// Check that our Reconciler implements controller.Reconciler
-var _ controller.Reconciler = (*Reconciler)(nil)
+	// Services writes Knative Services in the version of the serving API
+	// that the cluster serves.
+	// +required
+	Services ServiceClient
 
-// Reconcile implements controller.Reconciler
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	// ServiceLister reads Services from the informer on the version of the
+	// serving API that Services uses.
+	// +required
+	ServiceLister ServiceLister
+}
 
-	// Convert the namespace/name string into a distinct namespace and name
-	namespace, name, err := cache.SplitMetaNamespaceKey(key)
-	if err != nil {
-		r.Logger.Errorf("invalid resource key: %s", key)
-		return nil
-	}
-	r.Logger.Warnf("ns/name is %s/%s", namespace, name)
+// Check that our Reconciler is a kind of source with a child.
+var (
+	_ reconciler.Kind      = (*Reconciler)(nil)
+	_ reconciler.ChildKind = (*Reconciler)(nil)
+)
 
-	// If our controller has configuration state, we'd "freeze" it and
-	// attach the frozen configuration to the context.
-	//    ctx = r.configStore.ToContext(ctx)
+// SourceClient implements reconciler.Kind
+func (r *Reconciler) SourceClient() reconciler.SourceClient {
+	return serviceSourceClient{lister: r.Lister, client: r.SourcesClientSet}
+}
 
-	// Get the resource with this namespace/name.
-	original, err := r.Lister.ServiceSources(namespace).Get(name)
-	if apierrs.IsNotFound(err) {
-		// The resource may no longer exist, in which case we stop processing.
-		r.Logger.Errorf("resource %q no longer exists", key)
-		return nil
-	} else if err != nil {
-		return err
-	}
-	// Don't modify the informers copy.
-	resource := original.DeepCopy()
-
-	// Reconcile this copy of the resource and then write back any status
-	// updates regardless of whether the reconciliation errored out.
-	reconcileErr := r.reconcile(ctx, resource)
-	if equality.Semantic.DeepEqual(original.Status, resource.Status) {
-		// If we didn't change anything then don't call updateStatus.
-		// This is important because the copy we loaded from the informer's
-		// cache may be stale and we don't want to overwrite a prior update
-		// to status with this stale state.
-	} else if _, err = r.updateStatus(resource); err != nil {
-		r.Logger.Warnw("Failed to update resource status", zap.Error(err))
-		r.Recorder.Eventf(resource, corev1.EventTypeWarning, "UpdateFailed",
-			"Failed to update status for %q: %v", resource.Name, err)
-		return err
-	}
-	if reconcileErr != nil {
-		r.Logger.Warnw("Internal error reconciling:", zap.Error(reconcileErr))
-		r.Recorder.Event(resource, corev1.EventTypeWarning, "InternalError", reconcileErr.Error())
-	}
-	return reconcileErr
+// ReconcileKind implements reconciler.Kind. It enforces the creation and
+// lifecycle of the service, once the sink exists and is valid.
+func (r *Reconciler) ReconcileKind(ctx context.Context, source reconciler.SourceObject) error {
+	_, err := reconciler.ReconcileChild(ctx, r, source)
+	return err
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, source *v1alpha1.ServiceSource) error {
+// MakeChild implements reconciler.ChildKind
+func (r *Reconciler) MakeChild(source reconciler.SourceObject) reconciler.ChildObject {
+	return resources.MakeService(source.(*v1alpha1.ServiceSource))
+}
 
-	if source.GetDeletionTimestamp() != nil {
-		// Check for a DeletionTimestamp.  If present, elide the normal reconcile logic.
-		// When a controller needs finalizer handling, it would go here.
-		return nil
+// GetChild implements reconciler.ChildKind
+func (r *Reconciler) GetChild(namespace, name string) (reconciler.ChildObject, error) {
+	service, err := r.ServiceLister.Get(namespace, name)
+	if err != nil {
+		return nil, err
 	}
-	source.Status.InitializeConditions()
+	return service, nil
+}
 
-	if err := r.ReconcileSink(ctx, source); err != nil {
-		return err
+// CreateChild implements reconciler.ChildKind
+func (r *Reconciler) CreateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	service, err := r.Services.Create(child.(*servingv1beta1.Service))
+	if err != nil {
+		return nil, err
 	}
+	return service, nil
+}
 
-	if err := r.reconcileService(ctx, source); err != nil {
-		return err
+// UpdateChild implements reconciler.ChildKind
+func (r *Reconciler) UpdateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	service, err := r.Services.Update(child.(*servingv1beta1.Service))
+	if err != nil {
+		return nil, err
 	}
-
-	source.Status.ObservedGeneration = source.Generation
-	return nil
+	return service, nil
 }
 
-// reconcileService enforces the creation and lifecycle of the service. It assumes that the sink exists and is valid.
-func (r *Reconciler) reconcileService(ctx context.Context, source *v1alpha1.ServiceSource) error {
-	service, err := r.getService(ctx, source)
-
-	// The state we would like to see show up in K8s eventually
-	desired := resources.MakeService(source)
-
-	if apierrs.IsNotFound(err) {
-		// No service, must create it
-		service, err := r.ServingClientSet.ServingV1beta1().Services(source.Namespace).Create(desired)
-		if err != nil || service == nil {
-			msg := "Failed to make Service."
-			if err != nil {
-				msg = msg + " " + err.Error()
-			}
-			source.Status.MarkServiceNotReady("FailedCreate", msg)
-			return fmt.Errorf("failed to create Service: %s", err)
-		}
+// MarkChildFailed implements reconciler.ChildKind
+func (r *Reconciler) MarkChildFailed(source reconciler.SourceObject, reason, messageFormat string, messageA ...interface{}) {
+	source.(*v1alpha1.ServiceSource).Status.MarkServiceNotReady(reason, messageFormat, messageA...)
+}
 
+// PropagateStatus implements reconciler.ChildKind
+func (r *Reconciler) PropagateStatus(s reconciler.SourceObject, child reconciler.ChildObject, changed bool) error {
+	source := s.(*v1alpha1.ServiceSource)
+	if changed {
 		source.Status.MarkServiceDeploying()
 		return nil
-	} else if err != nil {
-		r.Logger.Warnw("Failed get:", zap.Error(err))
-		source.Status.MarkServiceNotReady("FailedGet", err.Error())
-		return fmt.Errorf("failed to get Service: %s", err)
-	}
-
-	// The service exists; check if it looks like we expect
-	if diff := cmp.Diff(desired.Spec, service.Spec); diff != "" {
-		service.Spec = desired.Spec
-		service, err := r.ServingClientSet.ServingV1beta1().Services(source.Namespace).Update(service)
-		r.Logger.Desugar().Info("Service updated.",
-			zap.Error(err), zap.Any("service", service), zap.String("diff", diff))
-		source.Status.MarkServiceDeploying()
-		return err
 	}
+	service := child.(*servingv1beta1.Service)
 
 	// Service exists and looks fine, propagate its status
 	cond := serviceReadyCondition(service)
@@ -195,25 +151,43 @@ func (r *Reconciler) reconcileService(ctx context.Context, source *v1alpha1.Serv
 	return nil
 }
 
-func (r *Reconciler) getService(ctx context.Context, owner metav1.Object) (*servingv1beta1.Service, error) {
-	return r.ServingClientSet.ServingV1beta1().Services(owner.GetNamespace()).Get(resources.ServiceName(owner), metav1.GetOptions{})
+// serviceSourceClient reads ServiceSources and writes them for the SourceReconciler.
+type serviceSourceClient struct {
+	lister listers.ServiceSourceLister
+	client clientset.Interface
 }
 
-// Update the Status of the resource.  Caller is responsible for checking
-// for semantic differences before calling.
-func (r *Reconciler) updateStatus(desired *v1alpha1.ServiceSource) (*v1alpha1.ServiceSource, error) {
-	actual, err := r.Lister.ServiceSources(desired.Namespace).Get(desired.Name)
+var _ reconciler.SourceClient = serviceSourceClient{}
+
+func (c serviceSourceClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	var (
+		source *v1alpha1.ServiceSource
+		err    error
+	)
+	if fresh {
+		source, err = c.client.SourcesV1alpha1().ServiceSources(namespace).Get(name, metav1.GetOptions{})
+	} else {
+		source, err = c.lister.ServiceSources(namespace).Get(name)
+	}
 	if err != nil {
 		return nil, err
 	}
-	// If there's nothing to update, just return.
-	if reflect.DeepEqual(actual.Status, desired.Status) {
-		return actual, nil
-	}
-	// Don't modify the informers copy
-	existing := actual.DeepCopy()
-	existing.Status = desired.Status
-	return r.SourcesClientSet.SourcesV1alpha1().ServiceSources(desired.Namespace).UpdateStatus(existing)
+	return source, nil
+}
+
+func (c serviceSourceClient) UpdateStatus(source runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().ServiceSources(source.(*v1alpha1.ServiceSource).Namespace).UpdateStatus(source.(*v1alpha1.ServiceSource))
+	return err
+}
+
+func (c serviceSourceClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().ServiceSources(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c serviceSourceClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().ServiceSources(namespace).Patch(name, types.MergePatchType, patch)
+	return err
 }
 
 // serviceReadyCondition retrieves the ready condition for a service.
@@ -18,24 +18,29 @@ package servicesource
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/servicesource/resources"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
 	"knative.dev/pkg/apis"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
-	"knative.dev/pkg/configmap"
-	"knative.dev/pkg/controller"
-	_ "knative.dev/pkg/ptr"
+	"knative.dev/pkg/ptr"
 	servingv1beta1 "knative.dev/serving/pkg/apis/serving/v1beta1"
 	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
 
@@ -47,10 +52,14 @@ const (
 	sName             = "my-servicesource"
 	sUID              = "1234"
 	sServiceFixedName = sName + "-source-" + sUID
+	cleanupJobName    = sName + "-cleanup-" + sUID
 	sinkName          = "my-sink"
 	ns                = "default"
 	key               = ns + "/" + sName
 	sinkURI           = "http://" + sinkName + "." + ns + ".svc.cluster.local/"
+	brokerName        = "default"
+	brokerHost        = brokerName + "-broker." + ns + ".svc.cluster.local"
+	brokerURI         = "http://" + brokerHost
 
 	notReadyReason  = "not ready reason"
 	notReadyMessage = "not ready message"
@@ -69,6 +78,20 @@ var (
 		Host:   sinkName + "." + ns + ".svc.cluster.local",
 		Path:   "/",
 	}))
+
+	brokerDest = destMust(apisv1alpha1.NewDestination(&corev1.ObjectReference{
+		Name:       brokerName,
+		APIVersion: "eventing.knative.dev/v1alpha1",
+		Kind:       "Broker",
+	}))
+
+	serviceAddress = &duckv1beta1.Addressable{URL: &apis.URL{
+		Host:   fmt.Sprintf("%s.%s.cluster.local", sName, ns),
+		Scheme: "http",
+	}}
+
+	// now is the time according to the reconciler's clock.
+	now = time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC)
 )
 
 func init() {
@@ -111,6 +134,35 @@ func newUnstructuredSink(scheme, hostname string) *unstructured.Unstructured {
 	}
 }
 
+// foreignController controls children that the source does not own.
+var foreignController = metav1.OwnerReference{
+	APIVersion: "apps/v1",
+	Kind:       "Deployment",
+	Name:       "someone-else",
+	UID:        "5678",
+	Controller: ptr.Bool(true),
+}
+
+// withServerDefaults fills in a default of the API server after the Service
+// was stamped with the hash of its spec.
+func withServerDefaults(service *servingv1beta1.Service) *servingv1beta1.Service {
+	for i := range service.Spec.Template.Spec.Containers {
+		service.Spec.Template.Spec.Containers[i].TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	return service
+}
+
+// servedServices is a discovery client for a cluster that serves Services in
+// the given version of the serving API.
+func servedServices(gv schema.GroupVersion) discovery.DiscoveryInterface {
+	return &fakediscovery.FakeDiscovery{Fake: &clientgotesting.Fake{
+		Resources: []*metav1.APIResourceList{{
+			GroupVersion: gv.String(),
+			APIResources: []metav1.APIResource{{Name: "services", Namespaced: true, Kind: "Service"}},
+		}},
+	}}
+}
+
 func TestServiceSource(t *testing.T) {
 	table := TableTest{{
 		Name: "bad workqueue key",
@@ -212,7 +264,37 @@ func TestServiceSource(t *testing.T) {
 			}),
 		}},
 		WantCreates: []runtime.Object{
-			resources.MakeService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+			NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+			})),
+		},
+	}, {
+		Name: "service missing from the cache is not created again",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = refDest
+			}),
+		},
+		Key: key,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceAlreadyExists("services"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+			}),
+		}},
+		WantCreates: []runtime.Object{
+			NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
 				s.UID = sUID
 				s.Spec.Sink = refDest
 				s.Status.InitializeConditions()
@@ -240,7 +322,7 @@ func TestServiceSource(t *testing.T) {
 			}),
 		}},
 		WantCreates: []runtime.Object{
-			resources.MakeService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+			NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
 				s.UID = sUID
 				s.Spec.Sink = uriDest
 				s.Status.InitializeConditions()
@@ -288,6 +370,28 @@ func TestServiceSource(t *testing.T) {
 				}})
 			}),
 		}},
+	}, {
+		Name: "defaults filled in by the server do not update the service",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceReady()
+			}),
+			withServerDefaults(NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.MarkSink(sinkURI)
+			}), func(service *servingv1beta1.Service) {
+				service.Status.Conditions = append(service.Status.Conditions, apis.Condition{
+					Type:   servingv1beta1.ServiceConditionReady,
+					Status: corev1.ConditionTrue,
+				})
+			})),
+		},
+		Key: key,
 	}, {
 		Name: "service ready means servicesource ready",
 		Objects: []runtime.Object{
@@ -358,6 +462,70 @@ func TestServiceSource(t *testing.T) {
 				s.Status.MarkServiceNotReady(notReadyReason, notReadyMessage)
 			}),
 		}},
+	}, {
+		Name: "service owned by something else is left alone",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+			}),
+			NewService(NewServiceSource(sName, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+			}), WithServiceOwnerReferences(foreignController)),
+		},
+		Key:     key,
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkChildNotOwned("Service", sName)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", "ServiceSource %q does not own Service %q", sName, sName),
+		},
+	}, {
+		Name: "adopt annotation adopts a service that nothing controls",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.InitializeConditions()
+			}),
+			NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.MarkSink(sinkURI)
+			}), WithServiceOwnerReferences()),
+		},
+		Key: key,
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+				s.Status.MarkSink(sinkURI)
+			})),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Annotations = map[string]string{v1alpha1.AdoptAnnotation: "true"}
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceDeploying()
+			}),
+		}},
 	}, {
 		Name: "sink updates restart service",
 		Objects: []runtime.Object{
@@ -399,13 +567,433 @@ func TestServiceSource(t *testing.T) {
 				})),
 			},
 		},
+	}, {
+		Name: "cleanup adds the finalizer",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, WithServiceSourceCleanup, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = refDest
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, WithServiceSourceCleanup, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceDeploying()
+			}),
+		}},
+		WantCreates: []runtime.Object{
+			NewService(NewServiceSource(sName, WithMinServiceSpec, WithServiceSourceCleanup, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+			})),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			finalizersPatch(v1alpha1.CleanupFinalizer),
+		},
+	}, {
+		Name: "deleted source starts its cleanup job",
+		Objects: []runtime.Object{
+			deletedSource(),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupRunning(cleanupJobName)
+			}),
+		}},
+		WantCreates: []runtime.Object{
+			NewCleanupJob(deletedSource(), sinkURI),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "CleanupStarted", "Started cleanup Job %q", cleanupJobName),
+		},
+	}, {
+		Name: "cleanup job that succeeded removes the finalizer",
+		Objects: []runtime.Object{
+			deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupRunning(cleanupJobName)
+			}),
+			NewCleanupJob(deletedSource(), sinkURI, WithJobCreated(metav1.NewTime(now.Add(-time.Minute))), WithJobCompleted(metav1.NewTime(now))),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanedUp()
+			}),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			finalizersPatch(),
+		},
+	}, {
+		Name: "failed cleanup job waits for the timeout",
+		Objects: []runtime.Object{
+			deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupRunning(cleanupJobName)
+			}),
+			NewCleanupJob(deletedSource(), sinkURI, WithJobCreated(metav1.NewTime(now.Add(-time.Minute))), WithJobFailed(metav1.NewTime(now), "BackoffLimitExceeded", "Job has reached the specified backoff limit")),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupFailed("CleanupFailed", "Cleanup Job %q failed: %s", cleanupJobName, "Job has reached the specified backoff limit")
+			}),
+		}},
+	}, {
+		Name: "cleanup job that timed out removes the finalizer",
+		Objects: []runtime.Object{
+			deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupRunning(cleanupJobName)
+			}),
+			NewCleanupJob(deletedSource(), sinkURI, WithJobCreated(metav1.NewTime(now.Add(-time.Hour)))),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deletedSource(func(s *v1alpha1.ServiceSource) {
+				s.Status.MarkCleanupFailed("CleanupTimedOut", "Cleanup Job %q did not succeed within %v", cleanupJobName, 5*time.Minute)
+			}),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			finalizersPatch(),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "CleanupTimedOut", "Cleanup Job %q did not succeed within %v", cleanupJobName, 5*time.Minute),
+		},
+	}, {
+		Name: "broker sink registers event types",
+		Objects: []runtime.Object{
+			brokerSource(withOrderEventType),
+			readyService(brokerSource(withOrderEventType)),
+			newBroker(),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			reconciler.MakeEventTypes(brokerSource(withOrderEventType), brokerName)[0],
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: brokerSource(withOrderEventType, func(s *v1alpha1.ServiceSource) {
+				s.Status.SetEventTypes(s.Spec.EventTypes)
+			}),
+		}},
+	}, {
+		Name: "event types missing from the cache are not created again",
+		Objects: []runtime.Object{
+			brokerSource(withOrderEventType),
+			readyService(brokerSource(withOrderEventType)),
+			newBroker(),
+		},
+		Key: key,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceAlreadyExists("eventtypes"),
+		},
+		WantCreates: []runtime.Object{
+			reconciler.MakeEventTypes(brokerSource(withOrderEventType), brokerName)[0],
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: brokerSource(withOrderEventType, func(s *v1alpha1.ServiceSource) {
+				s.Status.SetEventTypes(s.Spec.EventTypes)
+			}),
+		}},
+	}, {
+		Name: "event types made by something else are left alone",
+		Objects: []runtime.Object{
+			brokerSource(withOrderEventType),
+			readyService(brokerSource(withOrderEventType)),
+			newBroker(),
+			notOwnedEventType(),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			reconciler.MakeEventTypes(brokerSource(withOrderEventType), brokerName)[0],
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: brokerSource(withOrderEventType, func(s *v1alpha1.ServiceSource) {
+				s.Status.SetEventTypes(s.Spec.EventTypes)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "EventTypeNotOwned", "There is an existing EventType %q that the source does not own", reconciler.EventTypeName(brokerSource(), orderEventType.Type)),
+		},
+	}, {
+		Name: "event types that are no longer declared are deleted",
+		Objects: []runtime.Object{
+			brokerSource(),
+			readyService(brokerSource()),
+			newBroker(),
+			reconciler.MakeEventTypes(brokerSource(withOrderEventType), brokerName)[0],
+		},
+		Key: key,
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ns,
+				Verb:      "delete",
+				Resource:  eventingv1alpha1.SchemeGroupVersion.WithResource("eventtypes"),
+			},
+			Name: reconciler.EventTypeName(brokerSource(), orderEventType.Type),
+		}},
+	}, {
+		Name: "event types are not registered with sinks that are not brokers",
+		Objects: []runtime.Object{
+			brokerSource(withOrderEventType, withURISink, func(s *v1alpha1.ServiceSource) {
+				s.Status.SetEventTypes(s.Spec.EventTypes)
+			}),
+			readyService(brokerSource(withOrderEventType, withURISink)),
+		},
+		Key: key,
+	}}
+
+	table.Test(t, MakeSourceFactory("ServiceSource", FakeClock{Time: now}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
+		r := &Reconciler{
+			Base:   base,
+			Lister: listers.GetServiceSourceLister(),
+		}
+		r.Services = NewServiceClient(servedServices(servingV1beta1), fakeservingclient.Get(ctx), r.DynamicClientSet)
+		r.ServiceLister = r.Services.Lister(listers.GetIndexer(&servingv1beta1.Service{}))
+		return r
+	}))
+}
+
+// notOwnedEventType returns an EventType of the name that the ServiceSource
+// gives the EventType of orderEventType, which something else made.
+func notOwnedEventType() *eventingv1alpha1.EventType {
+	et := reconciler.MakeEventTypes(brokerSource(withOrderEventType), brokerName)[0]
+	et.OwnerReferences = nil
+	et.Labels = nil
+	return et
+}
+
+// orderEventType is a type of event that a ServiceSource may declare.
+var orderEventType = v1alpha1.SourceEventType{
+	Type:        "dev.example.order.created",
+	Schema:      "https://example.com/schemas/order.json",
+	Description: "An order was placed",
+}
+
+func withOrderEventType(s *v1alpha1.ServiceSource) {
+	s.Spec.EventTypes = []v1alpha1.SourceEventType{orderEventType}
+}
+
+func withURISink(s *v1alpha1.ServiceSource) {
+	s.Spec.Sink = uriDest
+	s.Status.MarkSink(sinkURI)
+}
+
+// brokerSource is a ready ServiceSource that sends to the default Broker.
+func brokerSource(options ...ServiceSourceOption) *v1alpha1.ServiceSource {
+	return NewServiceSource(sName, append([]ServiceSourceOption{
+		WithMinServiceSpec,
+		func(s *v1alpha1.ServiceSource) {
+			s.UID = sUID
+			s.Spec.Sink = brokerDest
+			s.Status.InitializeConditions()
+			s.Status.MarkSink(brokerURI)
+			s.Status.MarkServiceReady()
+			s.Status.MarkAddress(serviceAddress)
+		},
+	}, options...)...)
+}
+
+// readyService is the ready Service of the ServiceSource.
+func readyService(s *v1alpha1.ServiceSource) *servingv1beta1.Service {
+	return NewService(s, func(svc *servingv1beta1.Service) {
+		svc.Status.Conditions = append(svc.Status.Conditions, apis.Condition{
+			Type:   servingv1beta1.ServiceConditionReady,
+			Status: corev1.ConditionTrue,
+		})
+		svc.Status.Address = serviceAddress
+	})
+}
+
+// newBroker is the default Broker. It is unstructured so that the fake
+// dynamic client can resolve it as a sink.
+func newBroker() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "eventing.knative.dev/v1alpha1",
+			"kind":       "Broker",
+			"metadata": map[string]interface{}{
+				"namespace": ns,
+				"name":      brokerName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"url": brokerURI,
+				},
+			},
+		},
+	}
+}
+
+// deletedSource is a ready ServiceSource with a cleanup Job that is being
+// deleted.
+func deletedSource(options ...ServiceSourceOption) *v1alpha1.ServiceSource {
+	return NewServiceSource(sName, append([]ServiceSourceOption{
+		WithMinServiceSpec,
+		WithServiceSourceCleanup,
+		WithServiceSourceDeleted(metav1.NewTime(now)),
+		func(s *v1alpha1.ServiceSource) {
+			s.UID = sUID
+			s.Spec.Sink = refDest
+			s.Status.InitializeConditions()
+			s.Status.MarkSink(sinkURI)
+			s.Status.MarkServiceReady()
+		},
+	}, options...)...)
+}
+
+// finalizersPatch is the patch that sets the finalizers of the ServiceSource.
+func finalizersPatch(finalizers ...string) clientgotesting.PatchActionImpl {
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": "",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return clientgotesting.PatchActionImpl{
+		ActionImpl: clientgotesting.ActionImpl{Namespace: ns},
+		Name:       sName,
+		Patch:      patch,
+	}
+}
+
+// asServingV1 returns the Service as served by serving.knative.dev/v1.
+func asServingV1(service *servingv1beta1.Service) *unstructured.Unstructured {
+	u, err := reconciler.ToUnstructured(service, servingV1.WithKind("Service"))
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func TestServiceSourceServingV1(t *testing.T) {
+	table := TableTest{{
+		Name: "having sink creates a service",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = refDest
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceDeploying()
+			}),
+		}},
+		WantCreates: []runtime.Object{
+			asServingV1(NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+			}))),
+		},
+	}, {
+		Name: "service ready propagates to servicesource",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = refDest
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceDeploying()
+			}),
+			asServingV1(NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+			}), func(svc *servingv1beta1.Service) {
+				svc.Status.Conditions = append(svc.Status.Conditions, apis.Condition{
+					Type:   servingv1beta1.ServiceConditionReady,
+					Status: corev1.ConditionTrue,
+				})
+				svc.Status.Address = &duckv1beta1.Addressable{URL: &apis.URL{
+					Host:   fmt.Sprintf("%s.%s.cluster.local", svc.Name, svc.Namespace),
+					Scheme: "http",
+				}}
+			})),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = refDest
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceReady()
+				s.Status.MarkAddress(&duckv1beta1.Addressable{URL: &apis.URL{
+					Host:   fmt.Sprintf("%s.%s.cluster.local", resources.ServiceName(s), ns),
+					Scheme: "http",
+				}})
+			}),
+		}},
+	}, {
+		Name: "sink updates restart service",
+		Objects: []runtime.Object{
+			NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = namedTestSink(sinkName)
+				s.Status.MarkSink(sinkURI)
+				s.Status.MarkServiceReady()
+			}),
+			asServingV1(NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = namedTestSink(sinkName)
+				s.Status.MarkSink(sinkURI)
+			}))),
+			newUnstructuredSink("http", "garbage"),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Spec.Sink = namedTestSink(sinkName)
+
+				s.Status.InitializeConditions()
+				s.Status.MarkSink("http://garbage")
+				s.Status.MarkServiceDeploying()
+			}),
+		}},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: asServingV1(NewService(NewServiceSource(sName, WithMinServiceSpec, func(s *v1alpha1.ServiceSource) {
+				s.UID = sUID
+				s.Status.InitializeConditions()
+				s.Spec.Sink = namedTestSink(sinkName)
+				s.Status.MarkSink("http://garbage")
+			}))),
+		}},
 	}}
 
-	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
-		return &Reconciler{
-			Base:             reconciler.NewBase(ctx, "ServiceSource", cmw),
-			Lister:           listers.GetServiceSourceLister(),
-			ServingClientSet: fakeservingclient.Get(ctx),
+	table.Test(t, MakeSourceFactory("ServiceSource", FakeClock{Time: now}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
+		r := &Reconciler{
+			Base:   base,
+			Lister: listers.GetServiceSourceLister(),
 		}
+		r.Services = NewServiceClient(servedServices(servingV1), fakeservingclient.Get(ctx), r.DynamicClientSet)
+		r.ServiceLister = r.Services.Lister(listers.GetIndexer(&unstructured.Unstructured{}))
+		return r
 	}))
 }
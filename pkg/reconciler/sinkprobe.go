@@ -0,0 +1,213 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"knative.dev/pkg/system"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/cloudeventclient"
+)
+
+const (
+	// SinkUnreachableReason is the reason of the SinkReachable condition of
+	// sources whose sink failed the last probe.
+	SinkUnreachableReason = "ProbeFailed"
+
+	// ProbeEventType is the type of the CloudEvents that probe sinks.
+	ProbeEventType = "dev.knative.sources.probe"
+
+	// ProbeExtension is the extension of the CloudEvents that probe sinks,
+	// with the value "true". Sinks can ignore events that have it.
+	ProbeExtension = "knativeprobe"
+
+	// probeTimeout is how long a probe may take.
+	probeTimeout = 5 * time.Second
+)
+
+// ProbeResult is the outcome of a probe of a sink URI.
+type ProbeResult struct {
+	// Time is when the probe was sent.
+	Time time.Time
+
+	// Err is why the probe failed, or nil if the sink responded.
+	Err error
+
+	// Latency is how long the sink took to respond to the probe that
+	// found it reachable or unreachable. Later probes with the same
+	// outcome don't change it, so that the status of sources doesn't
+	// change with every probe.
+	Latency time.Duration
+}
+
+// SinkProber probes sink URIs to tell whether they accept events. The
+// results are cached per URI, so sources that share a sink, such as a
+// Broker, share its probes. Probes are sent in the background, so that
+// reconciles never wait for sinks.
+type SinkProber struct {
+	// Client sends the OPTIONS and HEAD probes.
+	Client *http.Client
+
+	// Clock tells when results expire.
+	Clock system.Clock
+
+	mu      sync.Mutex
+	entries map[probeKey]*probeEntry
+}
+
+type probeKey struct {
+	probe config.SinkProbe
+	uri   string
+}
+
+// probeEntry is the last result of the probes of a URI, guarded by
+// SinkProber.mu.
+type probeEntry struct {
+	result ProbeResult
+
+	// probing is whether a probe of the URI is in flight, so that callers
+	// don't probe it again meanwhile.
+	probing bool
+
+	// used is when the entry was last asked for.
+	used time.Time
+
+	// watchers are told when the sink becomes reachable or unreachable,
+	// by the keys that they asked for the result with.
+	watchers map[string]func()
+}
+
+// NewSinkProber returns a SinkProber that sends probes with client.
+func NewSinkProber(client *http.Client, clock system.Clock) *SinkProber {
+	return &SinkProber{
+		Client:  client,
+		Clock:   clock,
+		entries: make(map[probeKey]*probeEntry),
+	}
+}
+
+// Result returns the last result of probing uri the given way, and false if
+// the probe of the URI hasn't finished yet. When there is no result younger
+// than maxAge, a probe is sent in the background, in the given version of the
+// spec for CloudEvents probes. Whenever a probe finds that the sink became
+// reachable or unreachable, notify is called for every key that asked for the
+// result of the URI, so that they can ask again.
+func (p *SinkProber) Result(probe config.SinkProbe, uri string, maxAge time.Duration, specVersion, key string, notify func()) (ProbeResult, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := p.entry(probeKey{probe: probe, uri: uri}, maxAge)
+	entry.watchers[key] = notify
+	if !entry.probing && (entry.result.Time.IsZero() || p.Clock.Now().Sub(entry.result.Time) >= maxAge) {
+		entry.probing = true
+		go p.probe(probe, uri, specVersion, entry)
+	}
+	return entry.result, !entry.result.Time.IsZero()
+}
+
+// probe sends a probe of uri and records its result in entry, telling the
+// watchers of entry if the sink became reachable or unreachable.
+func (p *SinkProber) probe(probe config.SinkProbe, uri, specVersion string, entry *probeEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	start := p.Clock.Now()
+	err := p.send(ctx, probe, uri, specVersion)
+	latency := p.Clock.Now().Sub(start)
+
+	p.mu.Lock()
+	changed := entry.result.Time.IsZero() || (entry.result.Err == nil) != (err == nil)
+	if !changed {
+		latency = entry.result.Latency
+	}
+	entry.result = ProbeResult{Time: start, Err: err, Latency: latency}
+	entry.probing = false
+	var notify []func()
+	if changed {
+		for _, n := range entry.watchers {
+			notify = append(notify, n)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, n := range notify {
+		n()
+	}
+}
+
+// entry returns the entry of key, and drops the entries that nothing asked
+// for in a while, such as those of sinks that went away. p.mu must be held.
+func (p *SinkProber) entry(key probeKey, maxAge time.Duration) *probeEntry {
+	now := p.Clock.Now()
+	entry, ok := p.entries[key]
+	if !ok {
+		for k, e := range p.entries {
+			if now.Sub(e.used) > 2*maxAge {
+				delete(p.entries, k)
+			}
+		}
+		entry = &probeEntry{watchers: make(map[string]func())}
+		p.entries[key] = entry
+	}
+	entry.used = now
+	return entry
+}
+
+func (p *SinkProber) send(ctx context.Context, probe config.SinkProbe, uri, specVersion string) error {
+	switch probe {
+	case config.SinkProbeOptions, config.SinkProbeHead:
+		method := http.MethodOptions
+		if probe == config.SinkProbeHead {
+			method = http.MethodHead
+		}
+		req, err := http.NewRequest(method, uri, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := p.Client.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		// Sinks need not support the method, so any response but a server
+		// error will do.
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s %s: %s", method, uri, resp.Status)
+		}
+		return nil
+
+	case config.SinkProbeCloudEvent:
+		client, err := cloudeventclient.New(v1alpha1.OutputFormatBinary, uri)
+		if err != nil {
+			return err
+		}
+		event := cloudevents.NewEvent(specVersion)
+		event.SetType(ProbeEventType)
+		event.SetSource("/apis/v1/sinkprobes")
+		event.SetExtension(ProbeExtension, "true")
+		_, err = client.Send(ctx, event)
+		return err
+	}
+	return fmt.Errorf("unknown sink probe %q", probe)
+}
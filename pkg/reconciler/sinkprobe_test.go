@@ -0,0 +1,403 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+// probedSink records the probes it gets, and responds to them with status.
+type probedSink struct {
+	mu     sync.Mutex
+	probes []*http.Request
+	status int
+}
+
+func (s *probedSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.probes = append(s.probes, r)
+	w.WriteHeader(s.status)
+}
+
+func (s *probedSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.probes)
+}
+
+func (s *probedSink) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// notifier counts the calls of its notify, and signals them on called.
+type notifier struct {
+	calls  int32
+	called chan struct{}
+}
+
+func newNotifier() *notifier {
+	return &notifier{called: make(chan struct{}, 1)}
+}
+
+func (n *notifier) notify() {
+	atomic.AddInt32(&n.calls, 1)
+	select {
+	case n.called <- struct{}{}:
+	default:
+	}
+}
+
+func (n *notifier) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-n.called:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the probe to notify")
+	}
+}
+
+// waitProbed waits until no probe is in flight.
+func waitProbed(t *testing.T, p *SinkProber) {
+	t.Helper()
+	err := wait.PollImmediate(10*time.Millisecond, 10*time.Second, func() (bool, error) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		for _, e := range p.entries {
+			if e.probing {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("timed out waiting for the probes to finish: %v", err)
+	}
+}
+
+func TestSinkProber(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   config.SinkProbe
+		status  int
+		method  string
+		wantErr bool
+	}{{
+		name:   "options",
+		probe:  config.SinkProbeOptions,
+		status: http.StatusOK,
+		method: http.MethodOptions,
+	}, {
+		name:   "head of a sink that only takes posts",
+		probe:  config.SinkProbeHead,
+		status: http.StatusMethodNotAllowed,
+		method: http.MethodHead,
+	}, {
+		name:    "head of a failing sink",
+		probe:   config.SinkProbeHead,
+		status:  http.StatusServiceUnavailable,
+		method:  http.MethodHead,
+		wantErr: true,
+	}, {
+		name:   "cloudevent",
+		probe:  config.SinkProbeCloudEvent,
+		status: http.StatusAccepted,
+		method: http.MethodPost,
+	}, {
+		name:    "cloudevent refused",
+		probe:   config.SinkProbeCloudEvent,
+		status:  http.StatusBadRequest,
+		method:  http.MethodPost,
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := &probedSink{status: test.status}
+			server := httptest.NewServer(sink)
+			defer server.Close()
+
+			clock := &FakeClock{Time: time.Date(2019, time.October, 1, 12, 0, 0, 0, time.UTC)}
+			prober := NewSinkProber(server.Client(), clock)
+			n := newNotifier()
+
+			if _, ok := prober.Result(test.probe, server.URL, time.Minute, "0.3", "source", n.notify); ok {
+				t.Fatal("Result() before the probe finished = true, wanted false")
+			}
+			n.wait(t)
+			result, ok := prober.Result(test.probe, server.URL, time.Minute, "0.3", "source", n.notify)
+			if !ok {
+				t.Fatal("Result() after the probe finished = false, wanted true")
+			}
+			if (result.Err != nil) != test.wantErr {
+				t.Fatalf("Result() = %v, wantErr %v", result.Err, test.wantErr)
+			}
+			if got := sink.count(); got != 1 {
+				t.Fatalf("sink got %d probes, wanted 1", got)
+			}
+			if got := sink.probes[0].Method; got != test.method {
+				t.Errorf("probe method = %s, wanted %s", got, test.method)
+			}
+			if test.probe == config.SinkProbeCloudEvent {
+				// The SDK may send the value JSON encoded.
+				if got := sink.probes[0].Header.Get("Ce-" + ProbeExtension); strings.Trim(got, `"`) != "true" {
+					t.Errorf("probe extension = %q, wanted true", got)
+				}
+				if got := sink.probes[0].Header.Get("Ce-Type"); got != ProbeEventType {
+					t.Errorf("probe type = %q, wanted %s", got, ProbeEventType)
+				}
+			}
+		})
+	}
+}
+
+func TestSinkProberCache(t *testing.T) {
+	sink := &probedSink{status: http.StatusOK}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	clock := &FakeClock{Time: time.Date(2019, time.October, 1, 12, 0, 0, 0, time.UTC)}
+	prober := NewSinkProber(server.Client(), clock)
+	n := newNotifier()
+	result := func(key string) (ProbeResult, bool) {
+		return prober.Result(config.SinkProbeHead, server.URL, time.Minute, "0.3", key, n.notify)
+	}
+
+	// Sources sharing a sink share its probes.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result(fmt.Sprintf("source-%d", i))
+		}(i)
+	}
+	wg.Wait()
+	waitProbed(t, prober)
+	if got := sink.count(); got != 1 {
+		t.Errorf("sink got %d probes, wanted 1", got)
+	}
+	if got := atomic.LoadInt32(&n.calls); got != 10 {
+		t.Errorf("got %d notifications of the first result, wanted one per source", got)
+	}
+
+	clock.Time = clock.Time.Add(30 * time.Second)
+	if r, ok := result("source-0"); !ok || r.Err != nil {
+		t.Errorf("Result() = %v, %v, wanted the cached success", r.Err, ok)
+	}
+	waitProbed(t, prober)
+	if got := sink.count(); got != 1 {
+		t.Errorf("sink got %d probes before the result expired, wanted 1", got)
+	}
+
+	// Expired results are still returned while the sink is probed again,
+	// and the sources are not told when the sink stays reachable.
+	clock.Time = clock.Time.Add(time.Minute)
+	if r, ok := result("source-0"); !ok || r.Err != nil {
+		t.Errorf("Result() = %v, %v, wanted the expired success", r.Err, ok)
+	}
+	waitProbed(t, prober)
+	if got := sink.count(); got != 2 {
+		t.Errorf("sink got %d probes after the result expired, wanted 2", got)
+	}
+	if got := atomic.LoadInt32(&n.calls); got != 10 {
+		t.Errorf("got %d notifications, wanted none for a sink that stayed reachable", got-10)
+	}
+
+	// The sources are told when the sink becomes unreachable.
+	<-n.called
+	sink.setStatus(http.StatusServiceUnavailable)
+	clock.Time = clock.Time.Add(time.Minute)
+	result("source-0")
+	n.wait(t)
+	if r, ok := result("source-0"); !ok || r.Err == nil {
+		t.Errorf("Result() = %v, %v, wanted the failure", r.Err, ok)
+	}
+	if got := atomic.LoadInt32(&n.calls); got != 20 {
+		t.Errorf("got %d notifications of the failure, wanted one per source", got-10)
+	}
+
+	// Other ways of probing don't share results.
+	if _, ok := prober.Result(config.SinkProbeOptions, server.URL, time.Minute, "0.3", "source-0", n.notify); ok {
+		t.Error("Result() of an OPTIONS probe = true, wanted false")
+	}
+	waitProbed(t, prober)
+	if got := sink.count(); got != 4 {
+		t.Errorf("sink got %d probes, wanted 4", got)
+	}
+}
+
+// steppingClock advances by step whenever it is read, so that every probe
+// takes step.
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func (c *steppingClock) set(step, advance time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.step = step
+	c.now = c.now.Add(advance)
+}
+
+func TestSinkProberLatency(t *testing.T) {
+	sink := &probedSink{status: http.StatusOK}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	clock := &steppingClock{now: time.Date(2019, time.October, 1, 12, 0, 0, 0, time.UTC), step: 10 * time.Millisecond}
+	prober := NewSinkProber(server.Client(), clock)
+	n := newNotifier()
+	result := func() ProbeResult {
+		t.Helper()
+		prober.Result(config.SinkProbeHead, server.URL, time.Minute, "0.3", "source", n.notify)
+		waitProbed(t, prober)
+		r, ok := prober.Result(config.SinkProbeHead, server.URL, time.Minute, "0.3", "source", n.notify)
+		if !ok {
+			t.Fatal("Result() = false after the probe finished")
+		}
+		return r
+	}
+
+	if got, want := result().Latency, 10*time.Millisecond; got != want {
+		t.Errorf("Latency of the first probe = %v, want %v", got, want)
+	}
+
+	// Probes that find the sink reachable again keep the latency.
+	clock.set(50*time.Millisecond, time.Minute)
+	if got, want := result().Latency, 10*time.Millisecond; got != want {
+		t.Errorf("Latency of a probe with the same result = %v, want %v", got, want)
+	}
+
+	// A probe that finds the sink unreachable records its own.
+	sink.setStatus(http.StatusServiceUnavailable)
+	clock.set(50*time.Millisecond, time.Minute)
+	r := result()
+	if got, want := r.Latency, 50*time.Millisecond; r.Err == nil || got != want {
+		t.Errorf("Result() = %v in %v, want a failure in %v", r.Err, got, want)
+	}
+}
+
+func TestProbeSink(t *testing.T) {
+	up := httptest.NewServer(&probedSink{status: http.StatusOK})
+	defer up.Close()
+	down := httptest.NewServer(&probedSink{status: http.StatusBadGateway})
+	defer down.Close()
+
+	clock := &FakeClock{Time: time.Date(2019, time.October, 1, 12, 0, 0, 0, time.UTC)}
+	n := newNotifier()
+	r := &SourceReconciler{
+		Base: &Base{SinkProber: NewSinkProber(up.Client(), clock)},
+		EnqueueAfter: func(_ interface{}, after time.Duration) {
+			if after == 0 {
+				n.notify()
+			}
+		},
+	}
+
+	withProbe := func(probe string) context.Context {
+		defaults, err := config.NewDefaultsFromMap(map[string]string{"sink-probe": probe})
+		if err != nil {
+			t.Fatalf("NewDefaultsFromMap() = %v", err)
+		}
+		return config.ToContext(context.Background(), &config.Config{Defaults: defaults})
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		uri        string
+		want       corev1.ConditionStatus
+		wantReason string
+	}{{
+		name: "reachable",
+		ctx:  withProbe("options"),
+		uri:  up.URL,
+		want: corev1.ConditionTrue,
+	}, {
+		name:       "unreachable",
+		ctx:        withProbe("options"),
+		uri:        down.URL,
+		want:       corev1.ConditionFalse,
+		wantReason: SinkUnreachableReason,
+	}, {
+		name: "not probed",
+		ctx:  withProbe("none"),
+		uri:  up.URL,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := &v1alpha1.JobSource{}
+			source.Status.InitializeConditions()
+			source.Status.MarkSink(test.uri)
+			// Left over from an earlier sink.
+			source.Status.MarkSinkUnreachable("Leftover", "")
+
+			r.probeSink(test.ctx, source)
+			cond := source.Status.GetCondition(v1alpha1.SourceConditionSinkReachable)
+			if test.want == "" {
+				if cond != nil {
+					t.Errorf("SinkReachable = %v, wanted none", cond)
+				}
+				return
+			}
+			if cond == nil || cond.Reason != "Leftover" {
+				t.Errorf("SinkReachable before the probe finished = %v, wanted it left as it was", cond)
+			}
+
+			n.wait(t)
+			r.probeSink(test.ctx, source)
+			cond = source.Status.GetCondition(v1alpha1.SourceConditionSinkReachable)
+			if cond == nil || cond.Status != test.want || cond.Reason != test.wantReason {
+				t.Fatalf("SinkReachable = %v, wanted %s with reason %q", cond, test.want, test.wantReason)
+			}
+			if cond.Severity != "Info" {
+				t.Errorf("SinkReachable severity = %q, wanted Info", cond.Severity)
+			}
+			if test.want == corev1.ConditionTrue && cond.Message != "The sink responded to a probe in 0s" {
+				t.Errorf("SinkReachable message = %q, wanted the latency", cond.Message)
+			}
+		})
+	}
+}
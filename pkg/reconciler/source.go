@@ -0,0 +1,255 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+// SourceObject is a source that SourceReconciler can reconcile. Sources
+// generated from the types in pkg/apis satisfy it.
+type SourceObject interface {
+	v1alpha1.Source
+	kmeta.Accessor
+	kmeta.OwnerRefable
+}
+
+// Kind is what a kind of source supplies to SourceReconciler.
+//
+// A Kind may also implement DeletionReconciler, SinkGate and Forgetter to
+// hook into the steps that SourceReconciler takes.
+type Kind interface {
+	// SourceClient reads sources of the kind and writes their status.
+	SourceClient() SourceClient
+
+	// ReconcileKind makes the world match the source once its sink is
+	// resolved, and records what it finds in the status of the source.
+	ReconcileKind(ctx context.Context, source SourceObject) error
+}
+
+// DeletionReconciler is a Kind with work to do for sources that are being
+// deleted. SourceReconciler does nothing else for them.
+type DeletionReconciler interface {
+	ReconcileDeletion(ctx context.Context, source SourceObject) error
+}
+
+// SinkGate is a Kind with work to do before the sink of a source is
+// resolved. If done is true, the sink isn't resolved and ReconcileKind is not
+// called; the gate is then responsible for the observed generation.
+type SinkGate interface {
+	ReconcileBeforeSink(ctx context.Context, source SourceObject) (done bool, err error)
+}
+
+// Forgetter is a Kind that keeps state about sources outside of the API
+// server, which it drops when the source of the key is gone.
+type Forgetter interface {
+	Forget(key string)
+}
+
+// SourceReconciler implements controller.Reconciler for a Kind. It parses the
+// key, reads the source, initializes its conditions, resolves its sink, and
+// writes its status when it changed, leaving the rest to the Kind. Sources
+// with spec.cleanup get a finalizer that runs their cleanup Job on deletion,
+// and the event types in spec.eventTypes are registered with a Broker sink.
+type SourceReconciler struct {
+	// +required
+	*Base
+
+	// +required
+	Kind Kind
+
+	// JobLister reads the cleanup Jobs of sources.
+	// +required
+	JobLister batchv1listers.JobLister
+
+	// Clock decides when cleanup Jobs time out.
+	// +required
+	Clock system.Clock
+
+	// EnqueueAfter requeues a source that is being deleted for when its
+	// cleanup Job times out, and sources whose sink is probed for when the
+	// result of the probe expires.
+	// +required
+	EnqueueAfter func(interface{}, time.Duration)
+
+	// EventTypeLister reads the EventTypes that register the event types of
+	// sources. Without it, sources don't register their event types.
+	// +optional
+	EventTypeLister EventTypeLister
+
+	// EventTypesSynced reports whether the cache of EventTypeLister synced.
+	// Until it did, the event types of sources are registered later.
+	// Without it, the cache is taken to be synced.
+	// +optional
+	EventTypesSynced func() bool
+
+	// ConfigStore attaches the configuration of sources, such as the one
+	// made by NewConfigStore, to the context of every reconcile. Without
+	// it, sources get the built-in defaults.
+	// +optional
+	ConfigStore ConfigStore
+}
+
+// ConfigStore attaches configuration to contexts.
+type ConfigStore interface {
+	ToContext(context.Context) context.Context
+}
+
+// Check that SourceReconciler implements controller.Reconciler
+var _ controller.Reconciler = (*SourceReconciler)(nil)
+
+// Reconcile implements controller.Reconciler
+func (r *SourceReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+	if r.ConfigStore != nil {
+		ctx = r.ConfigStore.ToContext(ctx)
+	}
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	// Get the resource with this namespace/name.
+	client := r.Kind.SourceClient()
+	obj, err := client.Get(namespace, name, false)
+	if apierrs.IsNotFound(err) {
+		// The resource may no longer exist, in which case we stop processing.
+		logger.Errorf("resource %q no longer exists", key)
+		if f, ok := r.Kind.(Forgetter); ok {
+			f.Forget(key)
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	original := obj.(SourceObject)
+	// Don't modify the informers copy.
+	resource := original.DeepCopyObject().(SourceObject)
+
+	// Reconcile this copy of the resource and then write back any status
+	// updates regardless of whether the reconciliation errored out.
+	reconcileErr := r.reconcile(ctx, resource)
+	if equality.Semantic.DeepEqual(statusOf(original).Interface(), statusOf(resource).Interface()) {
+		// If we didn't change anything then don't call UpdateStatus.
+		// This is important because the copy we loaded from the informer's
+		// cache may be stale and we don't want to overwrite a prior update
+		// to status with this stale state.
+	} else if err = r.UpdateStatus(ctx, client, resource); apierrs.IsNotFound(err) && resource.GetDeletionTimestamp() != nil {
+		// The source is gone now that it has no finalizers left.
+	} else if err != nil {
+		logger.Warnw("Failed to update resource status", zap.Error(err))
+		r.Recorder.Eventf(resource, corev1.EventTypeWarning, "UpdateFailed",
+			"Failed to update status for %q: %v", resource.GetName(), err)
+		return err
+	}
+	if requeue, ok := reconcileErr.(*RequeueError); ok {
+		logger.Debugw("Reconciling again later", zap.Error(requeue))
+		r.EnqueueAfter(resource, requeue.After)
+		return nil
+	}
+	if reconcileErr != nil {
+		r.Logger.Warnw("Internal error reconciling:", zap.Error(reconcileErr))
+		r.Recorder.Event(resource, corev1.EventTypeWarning, "InternalError", reconcileErr.Error())
+	}
+	return reconcileErr
+}
+
+func (r *SourceReconciler) reconcile(ctx context.Context, source SourceObject) error {
+	if source.GetDeletionTimestamp() != nil {
+		// Check for a DeletionTimestamp.  If present, elide the normal reconcile logic.
+		if d, ok := r.Kind.(DeletionReconciler); ok {
+			if err := d.ReconcileDeletion(ctx, source); err != nil {
+				return err
+			}
+		}
+		return r.finalize(ctx, source)
+	}
+
+	if err := r.reconcileFinalizer(source); err != nil {
+		return err
+	}
+	source.GetStatus().InitializeConditions()
+	source.GetStatus().SetEventTypes(source.GetEventTypes())
+
+	if g, ok := r.Kind.(SinkGate); ok {
+		if done, err := g.ReconcileBeforeSink(ctx, source); err != nil || done {
+			return err
+		}
+	}
+
+	if err := r.ReconcileSink(ctx, source); err != nil {
+		return err
+	}
+	r.probeSink(ctx, source)
+
+	if err := r.reconcileEventTypes(ctx, source); err != nil {
+		return err
+	}
+
+	if err := r.Kind.ReconcileKind(ctx, source); err != nil {
+		return err
+	}
+
+	source.GetStatus().SetObservedGeneration(source.GetGeneration())
+	return nil
+}
+
+// probeSink sets the SinkReachable condition of the source from the last
+// probe of its sink URI, or removes it if sinks are not probed. Until the
+// first probe of the URI finishes, the condition is left as it is. The source
+// is reconciled again when its sink becomes reachable or unreachable, and
+// when the result expires, to probe the sink again.
+func (r *SourceReconciler) probeSink(ctx context.Context, source SourceObject) {
+	defaults := config.FromContextOrDefaults(ctx).Defaults
+	uri := source.GetStatus().GetSinkURI()
+	if r.SinkProber == nil || defaults.SinkProbe == config.SinkProbeNone || uri == "" {
+		source.GetStatus().ClearSinkReachable()
+		return
+	}
+
+	key := fmt.Sprintf("%T/%s/%s", source, source.GetNamespace(), source.GetName())
+	notify := func() { r.EnqueueAfter(source, 0) }
+	result, ok := r.SinkProber.Result(defaults.SinkProbe, uri, defaults.SinkProbeInterval, defaults.CloudEventsSpecVersion, key, notify)
+	r.EnqueueAfter(source, defaults.SinkProbeInterval)
+	if !ok {
+		return
+	}
+	if result.Err != nil {
+		source.GetStatus().MarkSinkUnreachable(SinkUnreachableReason, "Probe of the sink failed: %v", result.Err)
+		return
+	}
+	source.GetStatus().MarkSinkReachable(result.Latency)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceinstance
+
+import (
+	"context"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	templateinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/clustersourcetemplate"
+	sourceinstanceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/sourceinstance"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	jobinformer "knative.dev/pkg/client/injection/kube/informers/batch/v1/job"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/system"
+)
+
+const (
+	controllerAgentName = "sourceinstance-controller"
+)
+
+// NewController returns a new SourceInstance reconcile controller.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+
+	sourceInstanceInformer := sourceinstanceinformer.Get(ctx)
+	templateInformer := templateinformer.Get(ctx)
+	jobInformer := jobinformer.Get(ctx)
+	deploymentInformer := deploymentinformer.Get(ctx)
+
+	r := &Reconciler{
+		Base:             reconciler.NewBase(ctx, "SourceInstance", cmw),
+		Lister:           sourceInstanceInformer.Lister(),
+		TemplateLister:   templateInformer.Lister(),
+		JobLister:        jobInformer.Lister(),
+		DeploymentLister: deploymentInformer.Lister(),
+	}
+	r.CronJobs = reconciler.NewCronJobClient(r.KubeClientSet.Discovery(), r.KubeClientSet, r.DynamicClientSet)
+	sr := &reconciler.SourceReconciler{
+		Base:        r.Base,
+		Kind:        r,
+		JobLister:   r.JobLister,
+		Clock:       system.RealClock{},
+		ConfigStore: reconciler.NewConfigStore(ctx, cmw),
+	}
+	impl := controller.NewImpl(sr, r.Logger, "SourceInstances")
+	sr.EnqueueAfter = impl.EnqueueAfter
+	sr.WatchEventTypes(ctx, impl, "SourceInstance")
+
+	r.Logger.Info("Setting up event handlers for SourceInstances")
+
+	sourceInstanceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	// A change to a template is a change to every SourceInstance of it.
+	templateInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		template, err := kmeta.DeletionHandlingAccessor(obj)
+		if err != nil {
+			return
+		}
+		impl.FilteredGlobalResync(func(obj interface{}) bool {
+			source, ok := obj.(*v1alpha1.SourceInstance)
+			return ok && source.Spec.Template == template.GetName()
+		}, sourceInstanceInformer.Informer())
+	}))
+
+	// The Jobs of SourceInstances in Job mode, and their cleanup Jobs.
+	jobInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("SourceInstance")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("SourceInstance")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// Clusters serve CronJobs in batch/v1, batch/v1beta1 or both, so the
+	// informer is not one of the injected ones.
+	cronJobInformer := r.CronJobs.NewInformer(controller.GetResyncPeriod(ctx))
+	cronJobInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("SourceInstance")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+	r.CronJobLister = r.CronJobs.Lister(cronJobInformer.GetIndexer())
+	if err := reconciler.StartInformer(ctx, cronJobInformer); err != nil {
+		r.Logger.Fatalw("Failed to sync the CronJob informer", zap.Error(err))
+	}
+
+	return impl
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	"knative.dev/pkg/kmeta"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeCronJob returns the CronJob of a SourceInstance whose template is in
+// CronJob mode.
+func MakeCronJob(s *v1alpha1.SourceInstance, schedule string, rendered *corev1.PodTemplateSpec) *batchv1beta1.CronJob {
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            CronJobName(s),
+			Namespace:       s.Namespace,
+			Labels:          reconciler.Labels(s, labelKey),
+			Annotations:     reconciler.Annotations(s),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(s)},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule: schedule,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: makePodTemplate(s, rendered, corev1.RestartPolicyNever),
+				},
+			},
+		},
+	}
+}
+
+func CronJobName(owner metav1.Object) string {
+	// Reuse the owner's name.
+	return owner.GetName()
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	"knative.dev/pkg/kmeta"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeDeployment returns the Deployment of a SourceInstance whose template
+// is in Deployment mode.
+func MakeDeployment(s *v1alpha1.SourceInstance, rendered *corev1.PodTemplateSpec) *appsv1.Deployment {
+	replicas := int32(1)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            DeploymentName(s),
+			Namespace:       s.Namespace,
+			Labels:          reconciler.Labels(s, labelKey),
+			Annotations:     reconciler.Annotations(s),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(s)},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: Selector(s),
+			},
+			Template: makePodTemplate(s, rendered, corev1.RestartPolicyAlways),
+		},
+	}
+}
+
+func DeploymentName(owner metav1.Object) string {
+	// Reuse the owner's name.
+	return owner.GetName()
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	"knative.dev/pkg/kmeta"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MakeJob returns the Job of a SourceInstance whose template is in Job mode.
+// Jobs can't be changed once they run, so the Job is named after its spec:
+// a SourceInstance whose Job would change gets a new Job instead.
+func MakeJob(s *v1alpha1.SourceInstance, rendered *corev1.PodTemplateSpec) *batchv1.Job {
+	spec := batchv1.JobSpec{
+		Template: makePodTemplate(s, rendered, corev1.RestartPolicyNever),
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            JobName(s, &spec),
+			Namespace:       s.Namespace,
+			Labels:          reconciler.Labels(s, labelKey),
+			Annotations:     reconciler.Annotations(s),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(s)},
+		},
+		Spec: spec,
+	}
+}
+
+// JobName returns the name of the Job of the SourceInstance with the given
+// spec.
+func JobName(owner metav1.Object, spec *batchv1.JobSpec) string {
+	return kmeta.ChildName(owner.GetName(), "-"+reconciler.SpecHash(spec)[:10])
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	labelKey = "sources.knative.dev/sourceinstance"
+)
+
+// Selector returns the labels that the pods of a SourceInstance have.
+func Selector(s *v1alpha1.SourceInstance) map[string]string {
+	return map[string]string{labelKey: s.Name}
+}
+
+// makePodTemplate returns the pod template that the template of the
+// SourceInstance rendered to, with the labels, annotations and environment
+// of the SourceInstance, and the restart policy defaulted.
+func makePodTemplate(s *v1alpha1.SourceInstance, rendered *corev1.PodTemplateSpec, restartPolicy corev1.RestartPolicy) corev1.PodTemplateSpec {
+	template := rendered.DeepCopy()
+
+	// The labels of the template are kept so that platform teams can select
+	// the pods of their sources.
+	labels := reconciler.Labels(s, labelKey)
+	for k, v := range rendered.Labels {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	template.Labels = labels
+	annotations := reconciler.Annotations(s)
+	for k, v := range rendered.Annotations {
+		annotations[k] = v
+	}
+	template.Annotations = annotations
+
+	if template.Spec.RestartPolicy == "" {
+		template.Spec.RestartPolicy = restartPolicy
+	}
+
+	containers := []corev1.Container{}
+	for i, c := range template.Spec.Containers {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("sourceinstance%d", i)
+		}
+		c.Env = append(c.Env, reconciler.SinkEnv(s.Status.SinkURI, s.Spec.OutputFormat)...)
+		c.Env = append(c.Env, reconciler.SourceEnv(s)...)
+		containers = append(containers, c)
+	}
+	template.Spec.Containers = containers
+
+	return *template
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceinstance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
+	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	"github.com/n3wscott/sources/pkg/reconciler/sourceinstance/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+)
+
+const (
+	templateNotFoundReason    = "TemplateNotFound"
+	invalidParametersReason   = "InvalidParameters"
+	invalidTemplateReason     = "InvalidTemplate"
+	jobRunningReason          = "Running"
+	jobFailedReason           = "JobFailed"
+	deploymentDeployingReason = "Deploying"
+)
+
+// Reconciler is the reconciler.Kind for SourceInstance resources.
+type Reconciler struct {
+	// +required
+	*reconciler.Base
+
+	// Lister allows us to query for SourceInstances
+	// +required
+	Lister listers.SourceInstanceLister
+
+	// TemplateLister reads the ClusterSourceTemplates that SourceInstances
+	// instantiate.
+	// +required
+	TemplateLister listers.ClusterSourceTemplateLister
+
+	// JobLister reads the Jobs of SourceInstances in Job mode.
+	// +required
+	JobLister batchv1listers.JobLister
+
+	// DeploymentLister reads the Deployments of SourceInstances in
+	// Deployment mode.
+	// +required
+	DeploymentLister appsv1listers.DeploymentLister
+
+	// CronJobs writes CronJobs in the version of the batch API that the
+	// cluster serves.
+	// +required
+	CronJobs reconciler.CronJobClient
+
+	// CronJobLister reads CronJobs from the informer made by CronJobs.
+	// +required
+	CronJobLister reconciler.CronJobLister
+}
+
+// Check that our Reconciler is a kind of source.
+var _ reconciler.Kind = (*Reconciler)(nil)
+
+// SourceClient implements reconciler.Kind
+func (r *Reconciler) SourceClient() reconciler.SourceClient {
+	return sourceInstanceClient{lister: r.Lister, client: r.SourcesClientSet}
+}
+
+// ReconcileKind implements reconciler.Kind. It renders the template of the
+// SourceInstance with its parameters, runs the result in the mode of the
+// template, and deletes what it ran in other modes before.
+func (r *Reconciler) ReconcileKind(ctx context.Context, s reconciler.SourceObject) error {
+	source := s.(*v1alpha1.SourceInstance)
+
+	template, err := r.TemplateLister.Get(source.Spec.Template)
+	if apierrs.IsNotFound(err) {
+		// The SourceInstance is enqueued again when the template shows up.
+		source.Status.MarkTemplateNotResolved(templateNotFoundReason, "ClusterSourceTemplate %q does not exist", source.Spec.Template)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get ClusterSourceTemplate: %s", err)
+	}
+
+	params, err := template.Spec.ResolveParameters(source.Spec.Parameters)
+	if err != nil {
+		source.Status.MarkTemplateNotResolved(invalidParametersReason, "%v", err)
+		return nil
+	}
+	rendered, err := template.Spec.Render(params)
+	if err != nil {
+		source.Status.MarkTemplateNotResolved(invalidTemplateReason, "%v", err)
+		return nil
+	}
+	source.Status.MarkTemplateResolved(template.Spec.Mode)
+
+	// The webhook defaults the template for no namespace in particular, so
+	// fill in what is configured for the namespace of the SourceInstance.
+	defaults := config.FromContextOrDefaults(ctx).ForNamespace(source.Namespace)
+	for i := range rendered.Spec.Containers {
+		defaults.ApplyResources(&rendered.Spec.Containers[i])
+	}
+	if template.Spec.Mode != v1alpha1.SourceTemplateModeDeployment && rendered.Spec.RestartPolicy == "" {
+		rendered.Spec.RestartPolicy = defaults.RestartPolicy
+	}
+
+	var kind reconciler.ChildKind
+	switch template.Spec.Mode {
+	case v1alpha1.SourceTemplateModeJob:
+		kind = &jobChild{Reconciler: r, rendered: rendered}
+	case v1alpha1.SourceTemplateModeCronJob:
+		kind = &cronJobChild{Reconciler: r, rendered: rendered, schedule: template.Spec.Schedule}
+	case v1alpha1.SourceTemplateModeDeployment:
+		kind = &deploymentChild{Reconciler: r, rendered: rendered}
+	default:
+		source.Status.MarkTemplateNotResolved(invalidTemplateReason, "ClusterSourceTemplate %q has unknown mode %q", template.Name, template.Spec.Mode)
+		return nil
+	}
+
+	child, err := reconciler.ReconcileChild(ctx, kind, source)
+	if err != nil {
+		return err
+	}
+	return r.deleteStaleChildren(source, child)
+}
+
+// MarkChildFailed implements reconciler.ChildKind for every mode.
+func (r *Reconciler) MarkChildFailed(source reconciler.SourceObject, reason, messageFormat string, messageA ...interface{}) {
+	source.(*v1alpha1.SourceInstance).Status.MarkNotDeployed(reason, messageFormat, messageA...)
+}
+
+// deleteStaleChildren deletes the children of the SourceInstance other than
+// child: the Jobs that were made from a different spec, and the
+// Deployment and CronJob it had in other modes.
+func (r *Reconciler) deleteStaleChildren(source *v1alpha1.SourceInstance, child reconciler.ChildObject) error {
+	// Jobs and CronJobs orphan their Pods and Jobs by default; take them
+	// with them.
+	propagation := metav1.DeletePropagationBackground
+	options := &metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	var keep string
+	if job, ok := child.(*batchv1.Job); ok {
+		keep = job.Name
+	}
+	jobs, err := r.JobLister.Jobs(source.Namespace).List(labels.SelectorFromSet(resources.Selector(source)))
+	if err != nil {
+		return fmt.Errorf("failed to list Jobs: %s", err)
+	}
+	for _, job := range jobs {
+		if job.Name == keep || !metav1.IsControlledBy(job, source) {
+			continue
+		}
+		if err := r.KubeClientSet.BatchV1().Jobs(source.Namespace).Delete(job.Name, options); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Job: %s", err)
+		}
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q", job.Name)
+	}
+
+	if _, ok := child.(*appsv1.Deployment); !ok {
+		deployment, err := r.DeploymentLister.Deployments(source.Namespace).Get(resources.DeploymentName(source))
+		if err == nil && metav1.IsControlledBy(deployment, source) {
+			if err := r.KubeClientSet.AppsV1().Deployments(source.Namespace).Delete(deployment.Name, options); err != nil && !apierrs.IsNotFound(err) {
+				return fmt.Errorf("failed to delete Deployment: %s", err)
+			}
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, "DeploymentDeleted", "Deleted Deployment %q", deployment.Name)
+		} else if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to get Deployment: %s", err)
+		}
+	}
+
+	if _, ok := child.(*batchv1beta1.CronJob); !ok {
+		cronjob, err := r.CronJobLister.Get(source.Namespace, resources.CronJobName(source))
+		if err == nil && metav1.IsControlledBy(cronjob, source) {
+			if err := r.CronJobs.Delete(source.Namespace, cronjob.Name, options); err != nil && !apierrs.IsNotFound(err) {
+				return fmt.Errorf("failed to delete CronJob: %s", err)
+			}
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, "CronJobDeleted", "Deleted CronJob %q", cronjob.Name)
+		} else if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to get CronJob: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// jobChild is the reconciler.ChildKind of SourceInstances in Job mode.
+type jobChild struct {
+	*Reconciler
+	rendered *corev1.PodTemplateSpec
+}
+
+func (c *jobChild) MakeChild(source reconciler.SourceObject) reconciler.ChildObject {
+	return resources.MakeJob(source.(*v1alpha1.SourceInstance), c.rendered)
+}
+
+func (c *jobChild) GetChild(namespace, name string) (reconciler.ChildObject, error) {
+	job, err := c.JobLister.Jobs(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (c *jobChild) CreateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	job, err := c.KubeClientSet.BatchV1().Jobs(child.GetNamespace()).Create(child.(*batchv1.Job))
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateChild is only called to adopt a Job, since Jobs are named after
+// their spec.
+func (c *jobChild) UpdateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	job, err := c.KubeClientSet.BatchV1().Jobs(child.GetNamespace()).Update(child.(*batchv1.Job))
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (c *jobChild) PropagateStatus(s reconciler.SourceObject, child reconciler.ChildObject, changed bool) error {
+	source := s.(*v1alpha1.SourceInstance)
+	job := child.(*batchv1.Job)
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			source.Status.MarkDeployed()
+			return nil
+		case batchv1.JobFailed:
+			source.Status.MarkNotDeployed(jobFailedReason, "Job %q failed: %s", job.Name, cond.Message)
+			return nil
+		}
+	}
+	source.Status.MarkDeploying(jobRunningReason, "Job %q is running", job.Name)
+	return nil
+}
+
+// cronJobChild is the reconciler.ChildKind of SourceInstances in CronJob
+// mode.
+type cronJobChild struct {
+	*Reconciler
+	rendered *corev1.PodTemplateSpec
+	schedule string
+}
+
+func (c *cronJobChild) MakeChild(source reconciler.SourceObject) reconciler.ChildObject {
+	return resources.MakeCronJob(source.(*v1alpha1.SourceInstance), c.schedule, c.rendered)
+}
+
+func (c *cronJobChild) GetChild(namespace, name string) (reconciler.ChildObject, error) {
+	cronjob, err := c.CronJobLister.Get(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return cronjob, nil
+}
+
+func (c *cronJobChild) CreateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	cronjob, err := c.CronJobs.Create(child.(*batchv1beta1.CronJob))
+	if err != nil {
+		return nil, err
+	}
+	return cronjob, nil
+}
+
+func (c *cronJobChild) UpdateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	cronjob, err := c.CronJobs.Update(child.(*batchv1beta1.CronJob))
+	if err != nil {
+		return nil, err
+	}
+	return cronjob, nil
+}
+
+// PropagateStatus marks the SourceInstance deployed once it has a CronJob;
+// the runs of the CronJob are not followed.
+func (c *cronJobChild) PropagateStatus(s reconciler.SourceObject, child reconciler.ChildObject, changed bool) error {
+	s.(*v1alpha1.SourceInstance).Status.MarkDeployed()
+	return nil
+}
+
+// deploymentChild is the reconciler.ChildKind of SourceInstances in
+// Deployment mode.
+type deploymentChild struct {
+	*Reconciler
+	rendered *corev1.PodTemplateSpec
+}
+
+func (c *deploymentChild) MakeChild(source reconciler.SourceObject) reconciler.ChildObject {
+	return resources.MakeDeployment(source.(*v1alpha1.SourceInstance), c.rendered)
+}
+
+func (c *deploymentChild) GetChild(namespace, name string) (reconciler.ChildObject, error) {
+	deployment, err := c.DeploymentLister.Deployments(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+func (c *deploymentChild) CreateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	deployment, err := c.KubeClientSet.AppsV1().Deployments(child.GetNamespace()).Create(child.(*appsv1.Deployment))
+	if err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+func (c *deploymentChild) UpdateChild(child reconciler.ChildObject) (reconciler.ChildObject, error) {
+	deployment, err := c.KubeClientSet.AppsV1().Deployments(child.GetNamespace()).Update(child.(*appsv1.Deployment))
+	if err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+func (c *deploymentChild) PropagateStatus(s reconciler.SourceObject, child reconciler.ChildObject, changed bool) error {
+	source := s.(*v1alpha1.SourceInstance)
+	deployment := child.(*appsv1.Deployment)
+	if !changed {
+		for _, cond := range deployment.Status.Conditions {
+			if cond.Type != appsv1.DeploymentAvailable {
+				continue
+			}
+			if cond.Status == corev1.ConditionTrue {
+				source.Status.MarkDeployed()
+			} else {
+				source.Status.MarkDeploying(cond.Reason, "%s", cond.Message)
+			}
+			return nil
+		}
+	}
+	source.Status.MarkDeploying(deploymentDeployingReason, "Deployment %q is not available yet", deployment.Name)
+	return nil
+}
+
+// sourceInstanceClient reads SourceInstances and writes them for the SourceReconciler.
+type sourceInstanceClient struct {
+	lister listers.SourceInstanceLister
+	client clientset.Interface
+}
+
+var _ reconciler.SourceClient = sourceInstanceClient{}
+
+func (c sourceInstanceClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	var (
+		source *v1alpha1.SourceInstance
+		err    error
+	)
+	if fresh {
+		source, err = c.client.SourcesV1alpha1().SourceInstances(namespace).Get(name, metav1.GetOptions{})
+	} else {
+		source, err = c.lister.SourceInstances(namespace).Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (c sourceInstanceClient) UpdateStatus(source runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().SourceInstances(source.(*v1alpha1.SourceInstance).Namespace).UpdateStatus(source.(*v1alpha1.SourceInstance))
+	return err
+}
+
+func (c sourceInstanceClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().SourceInstances(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c sourceInstanceClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().SourceInstances(namespace).Patch(name, types.MergePatchType, patch)
+	return err
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceinstance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	apisv1alpha1 "knative.dev/pkg/apis/v1alpha1"
+
+	. "github.com/n3wscott/sources/pkg/reconciler/testing"
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	sName   = "my-sourceinstance"
+	sUID    = "1234"
+	tName   = "my-template"
+	ns      = "default"
+	key     = ns + "/" + sName
+	sinkURI = "http://example.com/"
+)
+
+var (
+	sink = apisv1alpha1.Destination{
+		URI: &apis.URL{Scheme: "http", Host: "example.com", Path: "/"},
+	}
+
+	jobTemplate        = NewClusterSourceTemplate(tName, v1alpha1.SourceTemplateModeJob)
+	cronJobTemplate    = NewClusterSourceTemplate(tName, v1alpha1.SourceTemplateModeCronJob, WithTemplateSchedule("*/5 * * * *"))
+	deploymentTemplate = NewClusterSourceTemplate(tName, v1alpha1.SourceTemplateModeDeployment)
+)
+
+func init() {
+	// Add types to scheme
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+// instance makes the SourceInstance of the tests, which sends its events to
+// sink and has the given topic. Its status has the resolved sink.
+func instance(topic string, options ...SourceInstanceOption) *v1alpha1.SourceInstance {
+	options = append([]SourceInstanceOption{
+		WithSourceInstanceParameter("topic", topic),
+		func(s *v1alpha1.SourceInstance) {
+			s.UID = sUID
+			s.Spec.Sink = sink
+			s.Status.InitializeConditions()
+			s.Status.MarkSink(sinkURI)
+		},
+	}, options...)
+	return NewSourceInstance(sName, tName, options...)
+}
+
+// jobName returns the name of the Job of the SourceInstance with the given
+// topic in Job mode.
+func jobName(topic string) string {
+	return NewSourceInstanceJob(instance(topic), jobTemplate).Name
+}
+
+// cpuRequestDefaults are configured defaults with a container cpu request.
+var cpuRequestDefaults, _ = config.NewDefaultsFromMap(map[string]string{"container-cpu-request": "100m"})
+
+// withCPURequest returns a copy of the template whose containers request
+// the cpu of cpuRequestDefaults.
+func withCPURequest(t *v1alpha1.ClusterSourceTemplate) *v1alpha1.ClusterSourceTemplate {
+	t = t.DeepCopy()
+	for i := range t.Spec.Template.Spec.Containers {
+		cpuRequestDefaults.ApplyResources(&t.Spec.Template.Spec.Containers[i])
+	}
+	return t
+}
+
+func TestSourceInstance(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "template not found",
+		Objects: []runtime.Object{
+			instance("foo"),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateNotResolved("TemplateNotFound", "ClusterSourceTemplate %q does not exist", tName)
+			}),
+		}},
+	}, {
+		Name: "invalid parameters",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("foo", WithSourceInstanceParameter("partition", "1"), func(s *v1alpha1.SourceInstance) {
+				delete(s.Spec.Parameters, "topic")
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", WithSourceInstanceParameter("partition", "1"), func(s *v1alpha1.SourceInstance) {
+				delete(s.Spec.Parameters, "topic")
+				s.Status.MarkTemplateNotResolved("InvalidParameters", `missing required parameter "topic"; unknown parameter "partition"`)
+			}),
+		}},
+	}, {
+		Name: "job mode creates a job",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("foo"),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			NewSourceInstanceJob(instance("foo"), jobTemplate),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeJob)
+				s.Status.MarkDeploying("Running", "Job %q is running", jobName(s.Spec.Parameters["topic"]))
+			}),
+		}},
+	}, {
+		Name: "job mode applies the configured container resources",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("foo"),
+		},
+		Key: key,
+		Ctx: config.ToContext(context.Background(), &config.Config{Defaults: cpuRequestDefaults}),
+		WantCreates: []runtime.Object{
+			NewSourceInstanceJob(instance("foo"), withCPURequest(jobTemplate)),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeJob)
+				s.Status.MarkDeploying("Running", "Job %q is running", NewSourceInstanceJob(instance("foo"), withCPURequest(jobTemplate)).Name)
+			}),
+		}},
+	}, {
+		Name: "job mode propagates a completed job",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("foo"),
+			NewSourceInstanceJob(instance("foo"), jobTemplate, WithJobCompleted(metav1.Now())),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeJob)
+				s.Status.MarkDeployed()
+			}),
+		}},
+	}, {
+		Name: "job mode propagates a failed job",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("foo"),
+			NewSourceInstanceJob(instance("foo"), jobTemplate, WithJobFailed(metav1.Now(), "BackoffLimitExceeded", "Job has reached the specified backoff limit")),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeJob)
+				s.Status.MarkNotDeployed("JobFailed", "Job %q failed: %s", jobName(s.Spec.Parameters["topic"]), "Job has reached the specified backoff limit")
+			}),
+		}},
+	}, {
+		Name: "job mode replaces the job of other parameters",
+		Objects: []runtime.Object{
+			jobTemplate,
+			instance("bar"),
+			NewSourceInstanceJob(instance("foo"), jobTemplate, WithJobCompleted(metav1.Now())),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			NewSourceInstanceJob(instance("bar"), jobTemplate),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ns,
+				Verb:      "delete",
+				Resource:  batchv1.SchemeGroupVersion.WithResource("jobs"),
+			},
+			Name: jobName("foo"),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("bar", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeJob)
+				s.Status.MarkDeploying("Running", "Job %q is running", jobName(s.Spec.Parameters["topic"]))
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "JobDeleted", "Deleted Job %q", jobName("foo")),
+		},
+	}, {
+		Name: "cronjob mode creates a cronjob",
+		Objects: []runtime.Object{
+			cronJobTemplate,
+			instance("foo"),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			NewSourceInstanceCronJob(instance("foo"), cronJobTemplate),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeCronJob)
+				s.Status.MarkDeployed()
+			}),
+		}},
+	}, {
+		Name: "deployment mode creates a deployment and deletes the cronjob of cronjob mode",
+		Objects: []runtime.Object{
+			deploymentTemplate,
+			instance("foo"),
+			NewSourceInstanceCronJob(instance("foo"), cronJobTemplate),
+		},
+		Key: key,
+		WantCreates: []runtime.Object{
+			NewSourceInstanceDeployment(instance("foo"), deploymentTemplate),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: ns,
+				Verb:      "delete",
+				Resource:  batchv1beta1.SchemeGroupVersion.WithResource("cronjobs"),
+			},
+			Name: sName,
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeDeployment)
+				s.Status.MarkDeploying("Deploying", "Deployment %q is not available yet", sName)
+			}),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "CronJobDeleted", "Deleted CronJob %q", sName),
+		},
+	}, {
+		Name: "deployment mode propagates an available deployment",
+		Objects: []runtime.Object{
+			deploymentTemplate,
+			instance("foo"),
+			NewSourceInstanceDeployment(instance("foo"), deploymentTemplate, WithDeploymentAvailable(corev1.ConditionTrue, "MinimumReplicasAvailable", "")),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("foo", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeDeployment)
+				s.Status.MarkDeployed()
+			}),
+		}},
+	}, {
+		Name: "deployment mode updates a deployment of other parameters",
+		Objects: []runtime.Object{
+			deploymentTemplate,
+			instance("bar"),
+			NewSourceInstanceDeployment(instance("foo"), deploymentTemplate, WithDeploymentAvailable(corev1.ConditionTrue, "MinimumReplicasAvailable", "")),
+		},
+		Key: key,
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewSourceInstanceDeployment(instance("bar"), deploymentTemplate, WithDeploymentAvailable(corev1.ConditionTrue, "MinimumReplicasAvailable", "")),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: instance("bar", func(s *v1alpha1.SourceInstance) {
+				s.Status.MarkTemplateResolved(v1alpha1.SourceTemplateModeDeployment)
+				s.Status.MarkDeploying("Deploying", "Deployment %q is not available yet", sName)
+			}),
+		}},
+	}}
+
+	table.Test(t, MakeSourceFactory("SourceInstance", FakeClock{}, func(ctx context.Context, listers *Listers, base *reconciler.Base) reconciler.Kind {
+		r := &Reconciler{
+			Base:             base,
+			Lister:           listers.GetSourceInstanceLister(),
+			TemplateLister:   listers.GetClusterSourceTemplateLister(),
+			JobLister:        listers.GetJobLister(),
+			DeploymentLister: listers.GetDeploymentLister(),
+		}
+		// The cluster serves CronJobs in batch/v1beta1 only.
+		r.CronJobs = reconciler.NewCronJobClient(&fakediscovery.FakeDiscovery{Fake: &clientgotesting.Fake{}}, r.KubeClientSet, r.DynamicClientSet)
+		r.CronJobLister = r.CronJobs.Lister(listers.GetIndexer(&batchv1beta1.CronJob{}))
+		return r
+	}))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcequota
+
+import (
+	"context"
+
+	cronjobsourceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/cronjobsource"
+	jobsourceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/jobsource"
+	servicesourceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/servicesource"
+	sourceinstanceinformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/sourceinstance"
+	sourcequotainformer "github.com/n3wscott/sources/pkg/client/injection/informers/sources/v1alpha1/sourcequota"
+	"github.com/n3wscott/sources/pkg/quota"
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	controllerAgentName = "sourcequota-controller"
+)
+
+// NewController returns a new SourceQuota reconcile controller.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+
+	quotaInformer := sourcequotainformer.Get(ctx)
+	jobSourceInformer := jobsourceinformer.Get(ctx)
+	cronJobSourceInformer := cronjobsourceinformer.Get(ctx)
+	serviceSourceInformer := servicesourceinformer.Get(ctx)
+	sourceInstanceInformer := sourceinstanceinformer.Get(ctx)
+
+	r := &Reconciler{
+		Base:   reconciler.NewBase(ctx, "SourceQuota", cmw),
+		Lister: quotaInformer.Lister(),
+		Usage: &quota.Usage{
+			QuotaLister:          quotaInformer.Lister(),
+			JobSourceLister:      jobSourceInformer.Lister(),
+			CronJobSourceLister:  cronJobSourceInformer.Lister(),
+			ServiceSourceLister:  serviceSourceInformer.Lister(),
+			SourceInstanceLister: sourceInstanceInformer.Lister(),
+		},
+		ConfigStore: reconciler.NewConfigStore(ctx, cmw),
+	}
+	impl := controller.NewImpl(r, r.Logger, "SourceQuotas")
+
+	r.Logger.Info("Setting up event handlers for SourceQuotas")
+
+	quotaInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	// A change to a source may change the usage of every SourceQuota of its
+	// namespace.
+	enqueueQuotas := func(obj interface{}) {
+		source, err := kmeta.DeletionHandlingAccessor(obj)
+		if err != nil {
+			return
+		}
+		quotas, err := r.Lister.SourceQuotas(source.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, quota := range quotas {
+			impl.Enqueue(quota)
+		}
+	}
+	jobSourceInformer.Informer().AddEventHandler(controller.HandleAll(enqueueQuotas))
+	cronJobSourceInformer.Informer().AddEventHandler(controller.HandleAll(enqueueQuotas))
+	serviceSourceInformer.Informer().AddEventHandler(controller.HandleAll(enqueueQuotas))
+	sourceInstanceInformer.Informer().AddEventHandler(controller.HandleAll(enqueueQuotas))
+
+	return impl
+}
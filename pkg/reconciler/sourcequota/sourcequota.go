@@ -0,0 +1,165 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcequota
+
+import (
+	"context"
+	"strings"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	clientset "github.com/n3wscott/sources/pkg/client/clientset/versioned"
+	listers "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/quota"
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	countFailedReason = "CountFailed"
+)
+
+// Reconciler reports the usage of SourceQuotas in their status.
+type Reconciler struct {
+	// +required
+	*reconciler.Base
+
+	// Lister allows us to query for SourceQuotas
+	// +required
+	Lister listers.SourceQuotaLister
+
+	// Usage counts what the sources of a namespace use.
+	// +required
+	Usage *quota.Usage
+
+	// ConfigStore attaches the configuration of sources to the context of
+	// every reconcile, for the way status is written.
+	ConfigStore reconciler.ConfigStore
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile implements controller.Reconciler
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+	if r.ConfigStore != nil {
+		ctx = r.ConfigStore.ToContext(ctx)
+	}
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorf("invalid resource key: %s", key)
+		return nil
+	}
+
+	original, err := r.Lister.SourceQuotas(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		// The resource may no longer exist, in which case we stop processing.
+		logger.Errorf("resource %q no longer exists", key)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	// Don't modify the informers copy.
+	quota := original.DeepCopy()
+	reconcileErr := r.reconcileStatus(quota)
+
+	if err := r.UpdateStatus(ctx, sourceQuotaClient{lister: r.Lister, client: r.SourcesClientSet}, quota); err != nil {
+		logger.Warnw("Failed to update resource status", zap.Error(err))
+		return err
+	}
+	return reconcileErr
+}
+
+// reconcileStatus counts the usage of the namespace of the quota into its
+// status, and reports when the usage exceeds the quota.
+func (r *Reconciler) reconcileStatus(quota *v1alpha1.SourceQuota) error {
+	quota.Status.InitializeConditions()
+	used, err := r.Usage.Usage(quota.Namespace)
+	if err != nil {
+		quota.Status.MarkUsageUnknown(countFailedReason, "Failed to count the sources: %v", err)
+	} else {
+		quota.Status.MarkUsage(used)
+		r.reconcileExceeded(quota, used)
+	}
+	quota.Status.ObservedGeneration = quota.Generation
+	return err
+}
+
+// reconcileExceeded sets the Exceeded condition of the quota, and emits an
+// event when the usage starts to exceed it. The webhook counts from a cache,
+// so sources created at the same time can exceed the quota.
+func (r *Reconciler) reconcileExceeded(q *v1alpha1.SourceQuota, used v1alpha1.SourceQuotaUsage) {
+	exceeded := quota.Exceeded(q, used)
+	if len(exceeded) == 0 {
+		q.Status.MarkWithinLimits()
+		return
+	}
+	if !q.Status.IsExceeded() {
+		r.Recorder.Eventf(q, corev1.EventTypeWarning, v1alpha1.QuotaExceededReason, "The namespace has %s", strings.Join(exceeded, ", "))
+	}
+	q.Status.MarkExceeded(exceeded)
+}
+
+// sourceQuotaClient lets Base.UpdateStatus write the status of SourceQuotas.
+type sourceQuotaClient struct {
+	lister listers.SourceQuotaLister
+	client clientset.Interface
+}
+
+var _ reconciler.SourceClient = sourceQuotaClient{}
+
+func (c sourceQuotaClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	var (
+		quota *v1alpha1.SourceQuota
+		err   error
+	)
+	if fresh {
+		quota, err = c.client.SourcesV1alpha1().SourceQuotas(namespace).Get(name, metav1.GetOptions{})
+	} else {
+		quota, err = c.lister.SourceQuotas(namespace).Get(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (c sourceQuotaClient) UpdateStatus(quota runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().SourceQuotas(quota.(*v1alpha1.SourceQuota).Namespace).UpdateStatus(quota.(*v1alpha1.SourceQuota))
+	return err
+}
+
+func (c sourceQuotaClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().SourceQuotas(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c sourceQuotaClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().SourceQuotas(namespace).Patch(name, types.MergePatchType, patch)
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcequota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/quota"
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+
+	. "github.com/n3wscott/sources/pkg/reconciler/testing"
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+const (
+	qName = "my-quota"
+	ns    = "default"
+	key   = ns + "/" + qName
+)
+
+func init() {
+	// Add types to scheme
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestSourceQuota(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "no sources",
+		Objects: []runtime.Object{
+			NewSourceQuota(qName),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewSourceQuota(qName, WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources: map[string]int32{"JobSource": 0, "CronJobSource": 0, "ServiceSource": 0, "SourceInstance": 0},
+			})),
+		}},
+	}, {
+		Name: "counts the sources of the namespace",
+		Objects: []runtime.Object{
+			NewSourceQuota(qName),
+			NewJobSource("running"),
+			NewJobSource("finished", WithJobSourceFinishedAt(time.Now())),
+			NewCronJobSource("cron"),
+			NewServiceSource("service"),
+			NewSourceInstance("instance", "template"),
+			NewSourceInstance("elsewhere", "template", func(s *v1alpha1.SourceInstance) {
+				s.Namespace = "other"
+			}),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewSourceQuota(qName, WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources:    map[string]int32{"JobSource": 2, "CronJobSource": 1, "ServiceSource": 1, "SourceInstance": 1},
+				ActiveJobs: 1,
+			})),
+		}},
+	}, {
+		Name: "usage is up to date",
+		Objects: []runtime.Object{
+			NewSourceQuota(qName, WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources:    map[string]int32{"JobSource": 1, "CronJobSource": 0, "ServiceSource": 0, "SourceInstance": 0},
+				ActiveJobs: 1,
+			})),
+			NewJobSource("running"),
+		},
+		Key: key,
+	}, {
+		Name: "usage that exceeds the quota is reported",
+		Objects: []runtime.Object{
+			NewSourceQuota(qName, WithSourceQuotaLimit("JobSource", 1)),
+			NewJobSource("first"),
+			NewJobSource("second"),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewSourceQuota(qName, WithSourceQuotaLimit("JobSource", 1), WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources:    map[string]int32{"JobSource": 2, "CronJobSource": 0, "ServiceSource": 0, "SourceInstance": 0},
+				ActiveJobs: 2,
+			}), WithSourceQuotaExceeded("2 of at most 1 JobSources")),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "QuotaExceeded", "The namespace has 2 of at most 1 JobSources"),
+		},
+	}, {
+		Name: "usage back within the quota",
+		Objects: []runtime.Object{
+			NewSourceQuota(qName, WithSourceQuotaLimit("JobSource", 1), WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources:    map[string]int32{"JobSource": 2, "CronJobSource": 0, "ServiceSource": 0, "SourceInstance": 0},
+				ActiveJobs: 2,
+			}), WithSourceQuotaExceeded("2 of at most 1 JobSources")),
+			NewJobSource("first"),
+		},
+		Key: key,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewSourceQuota(qName, WithSourceQuotaLimit("JobSource", 1), WithSourceQuotaUsage(v1alpha1.SourceQuotaUsage{
+				Sources:    map[string]int32{"JobSource": 1, "CronJobSource": 0, "ServiceSource": 0, "SourceInstance": 0},
+				ActiveJobs: 1,
+			})),
+		}},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		return &Reconciler{
+			Base:   reconciler.NewBase(ctx, controllerAgentName, cmw),
+			Lister: listers.GetSourceQuotaLister(),
+			Usage: &quota.Usage{
+				QuotaLister:          listers.GetSourceQuotaLister(),
+				JobSourceLister:      listers.GetJobSourceLister(),
+				CronJobSourceLister:  listers.GetCronJobSourceLister(),
+				ServiceSourceLister:  listers.GetServiceSourceLister(),
+				SourceInstanceLister: listers.GetSourceInstanceLister(),
+			},
+		}
+	}))
+}
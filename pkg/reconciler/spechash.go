@@ -0,0 +1,53 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SpecHashAnnotation holds the hash of the spec that a source last wrote to
+// its child. Comparing hashes of desired specs, rather than the specs
+// themselves, leaves out the defaults that the API server fills in.
+const SpecHashAnnotation = "sources.knative.dev/spec-hash"
+
+// SpecHash returns the hash of the JSON of spec.
+func SpecHash(spec interface{}) string {
+	// Specs are plain structs, which always marshal, and maps marshal with
+	// sorted keys, so equal specs have equal hashes.
+	b, _ := json.Marshal(spec)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetSpecHash sets the spec-hash annotation of child to the hash of spec.
+func SetSpecHash(child metav1.Object, spec interface{}) {
+	annotations := make(map[string]string)
+	copyMap(annotations, child.GetAnnotations())
+	annotations[SpecHashAnnotation] = SpecHash(spec)
+	child.SetAnnotations(annotations)
+}
+
+// SpecHashChanged reports whether the spec-hash annotations of the existing
+// and desired child differ.
+func SpecHashChanged(existing, desired metav1.Object) bool {
+	return existing.GetAnnotations()[SpecHashAnnotation] != desired.GetAnnotations()[SpecHashAnnotation]
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+)
+
+// SourceClient reads sources of one kind and writes their status and
+// metadata, for Base.UpdateStatus and SourceReconciler.
+type SourceClient interface {
+	// Get returns the source from the lister, or from the API server if
+	// fresh is true.
+	Get(namespace, name string, fresh bool) (runtime.Object, error)
+
+	// UpdateStatus updates the status subresource of the source.
+	UpdateStatus(source runtime.Object) error
+
+	// PatchStatus applies the JSON merge patch to the status subresource of
+	// the source.
+	PatchStatus(namespace, name string, patch []byte) error
+
+	// Patch applies the JSON merge patch to the source.
+	Patch(namespace, name string, patch []byte) error
+}
+
+// UpdateStatus writes the status of desired, a source with a Status field,
+// unless it is semantically equal to the status that is there already.
+//
+// Updates that conflict with another write are retried against a fresh read
+// of the source, as long as its spec is the one that the status of desired
+// was computed from. If the spec changed, the status is not written; the
+// source is reconciled again for the change. With the status-writes key of
+// the configuration of sources set to patch, the status is merge-patched
+// instead, which doesn't conflict with writes to the rest of the source.
+func (r *Base) UpdateStatus(ctx context.Context, client SourceClient, desired kmeta.Accessor) error {
+	patch := config.FromContextOrDefaults(ctx).Defaults.StatusWrites == config.StatusWritesPatch
+	fresh := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Start from the lister, which is likely up to date, and read from
+		// the API server after a conflict, when it wasn't.
+		existing, err := client.Get(desired.GetNamespace(), desired.GetName(), fresh)
+		fresh = true
+		if err != nil {
+			return err
+		}
+		if existing.(kmeta.Accessor).GetGeneration() != desired.GetGeneration() {
+			return nil
+		}
+
+		want := statusOf(desired)
+		got := statusOf(existing)
+		if equality.Semantic.DeepEqual(got.Interface(), want.Interface()) {
+			return nil
+		}
+
+		if patch {
+			patch, err := statusMergePatch(got.Interface(), want.Interface())
+			if err != nil {
+				return err
+			}
+			return client.PatchStatus(desired.GetNamespace(), desired.GetName(), patch)
+		}
+
+		// Don't modify the informer's copy.
+		updated := existing.DeepCopyObject()
+		statusOf(updated).Set(want)
+		return client.UpdateStatus(updated)
+	})
+}
+
+// statusOf returns the Status field of a source.
+func statusOf(source runtime.Object) reflect.Value {
+	return reflect.ValueOf(source).Elem().FieldByName("Status")
+}
+
+// statusMergePatch returns the JSON merge patch that changes the status of a
+// source from got to want.
+func statusMergePatch(got, want interface{}) ([]byte, error) {
+	before, err := json.Marshal(map[string]interface{}{"status": got})
+	if err != nil {
+		return nil, err
+	}
+	after, err := json.Marshal(map[string]interface{}{"status": want})
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(before, after)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/n3wscott/sources/pkg/apis/config"
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/client/clientset/versioned/fake"
+)
+
+// jobSourceClient reads JobSources from a stale copy rather than a
+// lister.
+type jobSourceClient struct {
+	stale  *v1alpha1.JobSource
+	client *fake.Clientset
+}
+
+func (c jobSourceClient) Get(namespace, name string, fresh bool) (runtime.Object, error) {
+	if !fresh {
+		return c.stale, nil
+	}
+	return c.client.SourcesV1alpha1().JobSources(namespace).Get(name, metav1.GetOptions{})
+}
+
+func (c jobSourceClient) UpdateStatus(source runtime.Object) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(source.(*v1alpha1.JobSource).Namespace).UpdateStatus(source.(*v1alpha1.JobSource))
+	return err
+}
+
+func (c jobSourceClient) PatchStatus(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(namespace).Patch(name, types.MergePatchType, patch, "status")
+	return err
+}
+
+func (c jobSourceClient) Patch(namespace, name string, patch []byte) error {
+	_, err := c.client.SourcesV1alpha1().JobSources(namespace).Patch(name, types.MergePatchType, patch)
+	return err
+}
+
+func TestUpdateStatus(t *testing.T) {
+	source := func(generation int64, uri string) *v1alpha1.JobSource {
+		js := &v1alpha1.JobSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-jobsource", ResourceVersion: "1", Generation: generation},
+		}
+		js.Status.SinkURI = uri
+		return js
+	}
+
+	tests := []struct {
+		name        string
+		statusWrite string
+		stale       *v1alpha1.JobSource
+		existing    *v1alpha1.JobSource
+		desired     *v1alpha1.JobSource
+		conflicts   int
+		wantVerbs   []string
+		wantPatch   string
+		wantSinkURI string
+	}{{
+		name:        "no-op",
+		stale:       source(1, "http://a"),
+		existing:    source(1, "http://a"),
+		desired:     source(1, "http://a"),
+		wantSinkURI: "http://a",
+	}, {
+		name:        "update",
+		stale:       source(1, "http://a"),
+		existing:    source(1, "http://a"),
+		desired:     source(1, "http://b"),
+		wantVerbs:   []string{"update"},
+		wantSinkURI: "http://b",
+	}, {
+		name:        "conflict is retried against a fresh read",
+		stale:       source(1, "http://a"),
+		existing:    source(1, "http://a"),
+		desired:     source(1, "http://b"),
+		conflicts:   1,
+		wantVerbs:   []string{"update", "get", "update"},
+		wantSinkURI: "http://b",
+	}, {
+		name:        "fresh read with a changed spec is left for the next reconcile",
+		stale:       source(1, "http://a"),
+		existing:    source(2, "http://a"),
+		desired:     source(1, "http://b"),
+		conflicts:   1,
+		wantVerbs:   []string{"update", "get"},
+		wantSinkURI: "http://a",
+	}, {
+		name:        "fresh read makes the update a no-op",
+		stale:       source(1, "http://a"),
+		existing:    source(1, "http://b"),
+		desired:     source(1, "http://b"),
+		conflicts:   1,
+		wantVerbs:   []string{"update", "get"},
+		wantSinkURI: "http://b",
+	}, {
+		name:        "patch",
+		statusWrite: "patch",
+		stale:       source(1, "http://a"),
+		existing:    source(1, "http://a"),
+		desired:     source(1, "http://b"),
+		wantVerbs:   []string{"patch"},
+		wantPatch:   `{"status":{"sinkUri":"http://b"}}`,
+		wantSinkURI: "http://b",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.existing)
+			conflicts := test.conflicts
+			client.PrependReactor("update", "jobsources", func(clientgotesting.Action) (bool, runtime.Object, error) {
+				if conflicts > 0 {
+					conflicts--
+					return true, nil, apierrs.NewConflict(v1alpha1.Resource("jobsources"), test.desired.Name, nil)
+				}
+				return false, nil, nil
+			})
+			client.ClearActions()
+
+			ctx := context.Background()
+			if test.statusWrite != "" {
+				defaults, err := config.NewDefaultsFromMap(map[string]string{"status-writes": test.statusWrite})
+				if err != nil {
+					t.Fatalf("NewDefaultsFromMap() = %v", err)
+				}
+				ctx = config.ToContext(ctx, &config.Config{Defaults: defaults})
+			}
+
+			r := &Base{}
+			if err := r.UpdateStatus(ctx, jobSourceClient{stale: test.stale, client: client}, test.desired); err != nil {
+				t.Fatalf("UpdateStatus() = %v", err)
+			}
+
+			var verbs []string
+			for _, action := range client.Actions() {
+				verbs = append(verbs, action.GetVerb())
+				if patch, ok := action.(clientgotesting.PatchAction); ok {
+					if got := string(patch.GetPatch()); got != test.wantPatch {
+						t.Errorf("Patch = %s, want %s", got, test.wantPatch)
+					}
+					if got := patch.GetSubresource(); got != "status" {
+						t.Errorf("Patch subresource = %q, want status", got)
+					}
+				}
+			}
+			if diff := cmp.Diff(test.wantVerbs, verbs); diff != "" {
+				t.Errorf("Unexpected actions (-want, +got): %s", diff)
+			}
+
+			got, err := client.SourcesV1alpha1().JobSources(test.desired.Namespace).Get(test.desired.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Get() = %v", err)
+			}
+			if got.Status.SinkURI != test.wantSinkURI {
+				t.Errorf("SinkURI = %q, want %q", got.Status.SinkURI, test.wantSinkURI)
+			}
+		})
+	}
+}
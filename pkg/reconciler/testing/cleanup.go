@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"github.com/n3wscott/sources/pkg/reconciler"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewCleanupJob makes the cleanup Job of the source, which sends events to
+// sinkURI.
+func NewCleanupJob(source reconciler.SourceObject, sinkURI string, options ...JobOption) *batchv1.Job {
+	job := reconciler.MakeCleanupJob(source, sinkURI)
+
+	for _, option := range options {
+		option(job)
+	}
+
+	return job
+}
+
+// WithJobCreated sets the time that the Job was created at.
+func WithJobCreated(t metav1.Time) JobOption {
+	return func(job *batchv1.Job) {
+		job.CreationTimestamp = t
+	}
+}
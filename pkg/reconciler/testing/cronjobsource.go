@@ -18,14 +18,18 @@ package testing
 
 import (
 	"context"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/cronjobsource/resources"
 	"knative.dev/pkg/ptr"
 
+	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type CronJobSourceOption func(*v1alpha1.CronJobSource)
@@ -57,8 +61,17 @@ func WithFakeCronJobSpec(s *v1alpha1.CronJobSource) {
 	})
 }
 
+// WithInlineCronJobSpec makes the controller send the CronJobSource's event
+// every ten minutes.
+func WithInlineCronJobSpec(s *v1alpha1.CronJobSource) {
+	s.Spec.Schedule = "*/10 * * * *"
+	s.Spec.Data = `{"hello":"world"}`
+}
+
 type CronJobOption func(*batchv1beta1.CronJob)
 
+// NewCronJob makes the CronJob of the CronJobSource, stamped with the hash of
+// its spec after the options are applied.
 func NewCronJob(s *v1alpha1.CronJobSource, options ...CronJobOption) *batchv1beta1.CronJob {
 	cronjob := resources.MakeCronJob(s)
 
@@ -66,5 +79,42 @@ func NewCronJob(s *v1alpha1.CronJobSource, options ...CronJobOption) *batchv1bet
 		option(cronjob)
 	}
 
+	reconciler.SetSpecHash(cronjob, cronjob.Spec)
 	return cronjob
 }
+
+// WithCronJobUID sets the UID of the CronJob, which the Jobs it starts refer to.
+func WithCronJobUID(uid types.UID) CronJobOption {
+	return func(cronjob *batchv1beta1.CronJob) {
+		cronjob.UID = uid
+	}
+}
+
+// WithCronJobOwnerReferences replaces the owner references of the CronJob,
+// e.g. to make one that the CronJobSource does not own.
+func WithCronJobOwnerReferences(refs ...metav1.OwnerReference) CronJobOption {
+	return func(cronjob *batchv1beta1.CronJob) {
+		cronjob.OwnerReferences = refs
+	}
+}
+
+// NewCronJobRun makes a Job as the CronJob would start it for the given
+// scheduled time.
+func NewCronJobRun(cronjob *batchv1beta1.CronJob, scheduled time.Time, options ...JobOption) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.ScheduledJobName(cronjob, scheduled),
+			Namespace: cronjob.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronjob, batchv1beta1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronjob.Spec.JobTemplate.Spec,
+	}
+
+	for _, option := range options {
+		option(job)
+	}
+
+	return job
+}
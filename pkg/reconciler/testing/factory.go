@@ -19,17 +19,24 @@ package testing
 import (
 	"context"
 	"testing"
+	"time"
 
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ktesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
 
 	fakesourcesclient "github.com/n3wscott/sources/pkg/client/injection/client/fake"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	eventingv1alpha1 "knative.dev/eventing/pkg/apis/eventing/v1alpha1"
 	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
@@ -48,6 +55,29 @@ const (
 // Ctor functions create a k8s controller with given params.
 type Ctor func(context.Context, *Listers, configmap.Watcher) controller.Reconciler
 
+// KindCtor functions create the reconciler.Kind of a source from a Base with
+// fake clients.
+type KindCtor func(context.Context, *Listers, *reconciler.Base) reconciler.Kind
+
+// MakeSourceFactory creates a reconciler factory with fake clients for a kind
+// of source that is reconciled by reconciler.SourceReconciler, on which clock
+// tells the time. Kinds of sources built outside of this repository can table
+// test with it too.
+func MakeSourceFactory(agentName string, clock system.Clock, ctor KindCtor) Factory {
+	return MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		base := reconciler.NewBase(ctx, agentName, cmw)
+		return &reconciler.SourceReconciler{
+			Base:         base,
+			Kind:         ctor(ctx, listers, base),
+			JobLister:    listers.GetJobLister(),
+			Clock:        clock,
+			EnqueueAfter: func(interface{}, time.Duration) {},
+
+			EventTypeLister: reconciler.NewEventTypeLister(listers.GetIndexer(&eventingv1alpha1.EventType{})),
+		}
+	})
+}
+
 // MakeFactory creates a reconciler factory with fake clients and controller created by `ctor`.
 func MakeFactory(ctor Ctor) Factory {
 	return func(t *testing.T, r *TableRow) (controller.Reconciler, ActionRecorderList, EventList, *FakeStatsReporter) {
@@ -91,6 +121,8 @@ func MakeFactory(ctor Ctor) Factory {
 			kubeClient.PrependReactor("*", "*", reactor)
 			client.PrependReactor("*", "*", reactor)
 			dynamicClient.PrependReactor("*", "*", reactor)
+			eventingclient.PrependReactor("*", "*", reactor)
+			servingclient.PrependReactor("*", "*", reactor)
 		}
 
 		// Validate all Create operations through the serving client.
@@ -100,7 +132,12 @@ func MakeFactory(ctor Ctor) Factory {
 		})
 		client.PrependReactor("update", "*", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
 			// TODO(n3wscott): context.Background is the best we can do at the moment, but it should be set-able.
-			return ValidateUpdates(context.Background(), action)
+			ctx := context.Background()
+			if sr := action.GetSubresource(); sr != "" {
+				// Like the webhook, tell status updates apart.
+				ctx = apis.WithinSubResourceUpdate(ctx, nil, sr)
+			}
+			return ValidateUpdates(ctx, action)
 		})
 
 		actionRecorderList := ActionRecorderList{dynamicClient, client, kubeClient, eventingclient, servingclient}
@@ -110,3 +147,15 @@ func MakeFactory(ctor Ctor) Factory {
 
 	}
 }
+
+// InduceAlreadyExists makes creates of resource fail as if an object of the
+// same name existed that the informer caches don't have yet.
+func InduceAlreadyExists(resource string) ktesting.ReactionFunc {
+	return func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		if !action.Matches("create", resource) {
+			return false, nil, nil
+		}
+		obj := action.(ktesting.CreateAction).GetObject().(metav1.Object)
+		return true, nil, apierrs.NewAlreadyExists(action.GetResource().GroupResource(), obj.GetName())
+	}
+}
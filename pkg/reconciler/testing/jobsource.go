@@ -18,6 +18,7 @@ package testing
 
 import (
 	"context"
+	"time"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
 	"github.com/n3wscott/sources/pkg/reconciler/jobsource/resources"
@@ -45,6 +46,16 @@ func NewJobSource(name string, options ...JobSourceOption) *v1alpha1.JobSource {
 	return js
 }
 
+// WithJobSourceFinishedAt marks the JobSource's Job as having succeeded at the given time.
+func WithJobSourceFinishedAt(t time.Time) JobSourceOption {
+	return func(js *v1alpha1.JobSource) {
+		js.Status.InitializeConditions()
+		js.Status.MarkSink("http://example.com")
+		js.Status.MarkJobSucceeded()
+		js.Status.CompletionTime = &metav1.Time{Time: t}
+	}
+}
+
 func WithFakeJobContainer(js *v1alpha1.JobSource) {
 	js.Spec.Template.Spec.Containers = append(js.Spec.Template.Spec.Containers, corev1.Container{
 		Name:  "Steve",
@@ -63,3 +74,27 @@ func NewJob(js *v1alpha1.JobSource, options ...JobOption) *batchv1.Job {
 
 	return job
 }
+
+// WithJobCompleted marks the Job as having completed successfully at the given time.
+func WithJobCompleted(t metav1.Time) JobOption {
+	return func(job *batchv1.Job) {
+		job.Status.CompletionTime = &t
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		})
+	}
+}
+
+// WithJobFailed marks the Job as having failed at the given time.
+func WithJobFailed(t metav1.Time, reason, message string) JobOption {
+	return func(job *batchv1.Job) {
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type:               batchv1.JobFailed,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: t,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+}
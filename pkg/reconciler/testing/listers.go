@@ -21,6 +21,7 @@ import (
 	fakesourcesclientset "github.com/n3wscott/sources/pkg/client/clientset/versioned/fake"
 	sourceslisters "github.com/n3wscott/sources/pkg/client/listers/sources/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
 	"k8s.io/client-go/tools/cache"
@@ -94,6 +96,12 @@ func (l *Listers) indexerFor(obj runtime.Object) cache.Indexer {
 	return l.sorter.IndexerForObjectType(obj)
 }
 
+// GetIndexer returns the indexer of the objects of obj's type, for listers
+// that are made from an indexer at run time.
+func (l *Listers) GetIndexer(obj runtime.Object) cache.Indexer {
+	return l.indexerFor(obj)
+}
+
 // TODO(spencer-p) Some of these probably need to be changed or are unnecessary
 
 func (l *Listers) GetKubeObjects() []runtime.Object {
@@ -149,10 +157,26 @@ func (l *Listers) GetServiceSourceLister() sourceslisters.ServiceSourceLister {
 	return sourceslisters.NewServiceSourceLister(l.indexerFor(&sourcesv1alpha1.ServiceSource{}))
 }
 
+func (l *Listers) GetSourceInstanceLister() sourceslisters.SourceInstanceLister {
+	return sourceslisters.NewSourceInstanceLister(l.indexerFor(&sourcesv1alpha1.SourceInstance{}))
+}
+
+func (l *Listers) GetSourceQuotaLister() sourceslisters.SourceQuotaLister {
+	return sourceslisters.NewSourceQuotaLister(l.indexerFor(&sourcesv1alpha1.SourceQuota{}))
+}
+
+func (l *Listers) GetClusterSourceTemplateLister() sourceslisters.ClusterSourceTemplateLister {
+	return sourceslisters.NewClusterSourceTemplateLister(l.indexerFor(&sourcesv1alpha1.ClusterSourceTemplate{}))
+}
+
 func (l *Listers) GetDeploymentLister() appsv1listers.DeploymentLister {
 	return appsv1listers.NewDeploymentLister(l.indexerFor(&appsv1.Deployment{}))
 }
 
+func (l *Listers) GetJobLister() batchv1listers.JobLister {
+	return batchv1listers.NewJobLister(l.indexerFor(&batchv1.Job{}))
+}
+
 func (l *Listers) GetK8sServiceLister() corev1listers.ServiceLister {
 	return corev1listers.NewServiceLister(l.indexerFor(&corev1.Service{}))
 }
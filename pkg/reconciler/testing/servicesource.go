@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
 	"github.com/n3wscott/sources/pkg/reconciler/servicesource/resources"
 
 	corev1 "k8s.io/api/core/v1"
@@ -69,6 +70,8 @@ func WithMinServiceSpec(s *v1alpha1.ServiceSource) {
 
 type ServiceOption func(*servingv1beta1.Service)
 
+// NewService makes the Service of the ServiceSource, stamped with the hash of
+// its spec after the options are applied.
 func NewService(s *v1alpha1.ServiceSource, options ...ServiceOption) *servingv1beta1.Service {
 	svc := resources.MakeService(s)
 
@@ -76,5 +79,37 @@ func NewService(s *v1alpha1.ServiceSource, options ...ServiceOption) *servingv1b
 		option(svc)
 	}
 
+	reconciler.SetSpecHash(svc, svc.Spec)
 	return svc
 }
+
+// WithServiceOwnerReferences replaces the owner references of the Service,
+// e.g. to make one that the ServiceSource does not own.
+func WithServiceOwnerReferences(refs ...metav1.OwnerReference) ServiceOption {
+	return func(svc *servingv1beta1.Service) {
+		svc.OwnerReferences = refs
+	}
+}
+
+// WithServiceSourceCleanup gives the ServiceSource a cleanup Job.
+func WithServiceSourceCleanup(s *v1alpha1.ServiceSource) {
+	s.Spec.Cleanup = &v1alpha1.SourceCleanup{
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:  "cleanup",
+					Image: "example.com/cleanup",
+				}},
+			},
+		},
+	}
+}
+
+// WithServiceSourceDeleted marks the ServiceSource as deleted while the
+// cleanup finalizer holds on to it.
+func WithServiceSourceDeleted(t metav1.Time) ServiceSourceOption {
+	return func(s *v1alpha1.ServiceSource) {
+		s.DeletionTimestamp = &t
+		s.Finalizers = []string{v1alpha1.CleanupFinalizer}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+	"github.com/n3wscott/sources/pkg/reconciler"
+	"github.com/n3wscott/sources/pkg/reconciler/sourceinstance/resources"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterSourceTemplateOption func(*v1alpha1.ClusterSourceTemplate)
+
+// NewClusterSourceTemplate makes a template in the given mode whose
+// container takes its topic from the "topic" parameter.
+func NewClusterSourceTemplate(name string, mode v1alpha1.SourceTemplateMode, options ...ClusterSourceTemplateOption) *v1alpha1.ClusterSourceTemplate {
+	t := &v1alpha1.ClusterSourceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1alpha1.ClusterSourceTemplateSpec{
+			Mode: mode,
+			Parameters: []v1alpha1.SourceTemplateParameter{{
+				Name:     "topic",
+				Required: true,
+			}},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "grc.io/fakeimage",
+						Args:  []string{"--topic=$(params.topic)"},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, option := range options {
+		option(t)
+	}
+
+	t.SetDefaults(context.Background())
+	return t
+}
+
+// WithTemplateSchedule sets the schedule of a template in CronJob mode.
+func WithTemplateSchedule(schedule string) ClusterSourceTemplateOption {
+	return func(t *v1alpha1.ClusterSourceTemplate) {
+		t.Spec.Schedule = schedule
+	}
+}
+
+type SourceInstanceOption func(*v1alpha1.SourceInstance)
+
+func NewSourceInstance(name, template string, options ...SourceInstanceOption) *v1alpha1.SourceInstance {
+	s := &v1alpha1.SourceInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: v1alpha1.SourceInstanceSpec{
+			Template: template,
+		},
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.SetDefaults(context.Background())
+	return s
+}
+
+// WithSourceInstanceParameter sets the value of a parameter.
+func WithSourceInstanceParameter(name, value string) SourceInstanceOption {
+	return func(s *v1alpha1.SourceInstance) {
+		if s.Spec.Parameters == nil {
+			s.Spec.Parameters = make(map[string]string)
+		}
+		s.Spec.Parameters[name] = value
+	}
+}
+
+// renderTemplate renders the template with the parameters of the
+// SourceInstance, which must be valid for it.
+func renderTemplate(s *v1alpha1.SourceInstance, t *v1alpha1.ClusterSourceTemplate) *corev1.PodTemplateSpec {
+	params, err := t.Spec.ResolveParameters(s.Spec.Parameters)
+	if err != nil {
+		panic(err)
+	}
+	rendered, err := t.Spec.Render(params)
+	if err != nil {
+		panic(err)
+	}
+	return rendered
+}
+
+// NewSourceInstanceJob makes the Job of the SourceInstance, stamped with
+// the hash of its spec after the options are applied.
+func NewSourceInstanceJob(s *v1alpha1.SourceInstance, t *v1alpha1.ClusterSourceTemplate, options ...JobOption) *batchv1.Job {
+	job := resources.MakeJob(s, renderTemplate(s, t))
+
+	for _, option := range options {
+		option(job)
+	}
+
+	reconciler.SetSpecHash(job, job.Spec)
+	return job
+}
+
+// NewSourceInstanceCronJob makes the CronJob of the SourceInstance,
+// stamped with the hash of its spec after the options are applied.
+func NewSourceInstanceCronJob(s *v1alpha1.SourceInstance, t *v1alpha1.ClusterSourceTemplate, options ...CronJobOption) *batchv1beta1.CronJob {
+	cronjob := resources.MakeCronJob(s, t.Spec.Schedule, renderTemplate(s, t))
+
+	for _, option := range options {
+		option(cronjob)
+	}
+
+	reconciler.SetSpecHash(cronjob, cronjob.Spec)
+	return cronjob
+}
+
+type DeploymentOption func(*appsv1.Deployment)
+
+// NewSourceInstanceDeployment makes the Deployment of the SourceInstance,
+// stamped with the hash of its spec after the options are applied.
+func NewSourceInstanceDeployment(s *v1alpha1.SourceInstance, t *v1alpha1.ClusterSourceTemplate, options ...DeploymentOption) *appsv1.Deployment {
+	deployment := resources.MakeDeployment(s, renderTemplate(s, t))
+
+	for _, option := range options {
+		option(deployment)
+	}
+
+	reconciler.SetSpecHash(deployment, deployment.Spec)
+	return deployment
+}
+
+// WithDeploymentAvailable sets the Available condition of the Deployment.
+func WithDeploymentAvailable(status corev1.ConditionStatus, reason, message string) DeploymentOption {
+	return func(deployment *appsv1.Deployment) {
+		deployment.Status.Conditions = append(deployment.Status.Conditions, appsv1.DeploymentCondition{
+			Type:    appsv1.DeploymentAvailable,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+}
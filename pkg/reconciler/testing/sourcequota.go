@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SourceQuotaOption func(*v1alpha1.SourceQuota)
+
+func NewSourceQuota(name string, options ...SourceQuotaOption) *v1alpha1.SourceQuota {
+	q := &v1alpha1.SourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+	}
+
+	for _, option := range options {
+		option(q)
+	}
+	return q
+}
+
+// WithSourceQuotaUsage sets the usage in the status of the SourceQuota.
+func WithSourceQuotaUsage(used v1alpha1.SourceQuotaUsage) SourceQuotaOption {
+	return func(q *v1alpha1.SourceQuota) {
+		q.Status.InitializeConditions()
+		q.Status.MarkUsage(used)
+	}
+}
+
+// WithSourceQuotaLimit limits the number of sources of the kind.
+func WithSourceQuotaLimit(kind string, limit int32) SourceQuotaOption {
+	return func(q *v1alpha1.SourceQuota) {
+		if q.Spec.Sources == nil {
+			q.Spec.Sources = make(map[string]int32)
+		}
+		q.Spec.Sources[kind] = limit
+	}
+}
+
+// WithSourceQuotaExceeded marks the quota exceeded.
+func WithSourceQuotaExceeded(exceeded ...string) SourceQuotaOption {
+	return func(q *v1alpha1.SourceQuota) {
+		q.Status.MarkExceeded(exceeded)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+)
+
+// Some children, like CronJobs and Knative Services, are served in a newer
+// version of their API that the vendored clients have no types for, in an
+// older one, or in both, depending on the cluster. The helpers below pick the
+// version to use and go through the dynamic client for the newer one. The
+// objects are of the older, typed version in memory either way, which works
+// as long as the versions have the same shape.
+
+// ServesResource asks the cluster through discovery whether it serves the
+// resource, e.g. "cronjobs", in the given group version.
+func ServesResource(client discovery.DiscoveryInterface, gv schema.GroupVersion, resource string) bool {
+	resources, err := client.ServerResourcesForGroupVersion(gv.String())
+	if err != nil || resources == nil {
+		// Most likely the group version is not served at all.
+		return false
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// NewListWatchInformer returns an informer on the objects that list and
+// watch return, in all namespaces, indexed by namespace. exampleObject is
+// of the type of those objects.
+func NewListWatchInformer(
+	list func(metav1.ListOptions) (runtime.Object, error),
+	watch func(metav1.ListOptions) (watch.Interface, error),
+	exampleObject runtime.Object,
+	resync time.Duration,
+) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc:  list,
+		WatchFunc: watch,
+	}, exampleObject, resync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// StartInformer starts an informer that is not one of the injected ones,
+// which sharedmain starts and syncs before running the controllers, and
+// waits for its cache to sync. Until then its lister reports children that
+// exist as missing, and the reconciler would try to create them again.
+func StartInformer(ctx context.Context, informer cache.SharedIndexInformer) error {
+	return controller.StartInformers(ctx.Done(), informer)
+}
+
+// DynamicResource reads and writes typed objects through the dynamic client,
+// as objects of another version of their API.
+type DynamicResource struct {
+	// GVR is the resource in the version of the API in use.
+	GVR schema.GroupVersionResource
+	// Kind is the kind of the objects of the resource.
+	Kind string
+
+	Client dynamic.Interface
+}
+
+// Create creates the object in the version in use and stores the result in
+// out.
+func (d *DynamicResource) Create(namespace string, obj interface{}, out runtime.Object) error {
+	u, err := ToUnstructured(obj, d.GVR.GroupVersion().WithKind(d.Kind))
+	if err != nil {
+		return err
+	}
+	u, err = d.Client.Resource(d.GVR).Namespace(namespace).Create(u, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	return FromUnstructured(u, out)
+}
+
+// Update updates the object in the version in use and stores the result in
+// out.
+func (d *DynamicResource) Update(namespace string, obj interface{}, out runtime.Object) error {
+	u, err := ToUnstructured(obj, d.GVR.GroupVersion().WithKind(d.Kind))
+	if err != nil {
+		return err
+	}
+	u, err = d.Client.Resource(d.GVR).Namespace(namespace).Update(u, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	return FromUnstructured(u, out)
+}
+
+// Delete deletes the named object.
+func (d *DynamicResource) Delete(namespace, name string, options *metav1.DeleteOptions) error {
+	return d.Client.Resource(d.GVR).Namespace(namespace).Delete(name, options)
+}
+
+// NewInformer returns an informer on the unstructured objects in all
+// namespaces.
+func (d *DynamicResource) NewInformer(resync time.Duration) cache.SharedIndexInformer {
+	objects := d.Client.Resource(d.GVR).Namespace(metav1.NamespaceAll)
+	return NewListWatchInformer(
+		func(options metav1.ListOptions) (runtime.Object, error) {
+			return objects.List(options)
+		},
+		func(options metav1.ListOptions) (watch.Interface, error) {
+			return objects.Watch(options)
+		},
+		&unstructured.Unstructured{}, resync)
+}
+
+// Get gets the named object from the indexer of an informer made by
+// NewInformer and stores it in out. It returns a NotFound error for objects
+// of other versions or kinds.
+func (d *DynamicResource) Get(indexer cache.Indexer, namespace, name string, out runtime.Object) error {
+	obj, exists, err := indexer.GetByKey(namespace + "/" + name)
+	if err != nil {
+		return err
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !exists || !ok || u.GroupVersionKind() != d.GVR.GroupVersion().WithKind(d.Kind) {
+		return apierrs.NewNotFound(d.GVR.GroupResource(), name)
+	}
+	return FromUnstructured(u, out)
+}
+
+// ToUnstructured returns the object as one of the given group version and
+// kind.
+func ToUnstructured(obj interface{}, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	return u, nil
+}
+
+// FromUnstructured stores the unstructured object in out.
+func FromUnstructured(u *unstructured.Unstructured, out runtime.Object) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, out)
+}
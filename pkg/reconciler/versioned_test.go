@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStartInformer(t *testing.T) {
+	cronjob := &batchv1beta1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing"}}
+	client := NewCronJobClient(fake.NewSimpleClientset(cronjob).Discovery(), fake.NewSimpleClientset(cronjob), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informer := client.NewInformer(0)
+	if err := StartInformer(ctx, informer); err != nil {
+		t.Fatalf("StartInformer() = %v", err)
+	}
+
+	// The reconciler must not try to create CronJobs that exist.
+	if _, err := client.Lister(informer.GetIndexer()).Get("default", "existing"); err != nil {
+		t.Errorf("Get() = %v right after StartInformer()", err)
+	}
+}
+
+func TestStartInformerStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// An informer that never syncs.
+	informer := NewListWatchInformer(
+		func(metav1.ListOptions) (runtime.Object, error) {
+			return nil, errors.New("the API server is unreachable")
+		}, nil, &batchv1beta1.CronJob{}, 0)
+	if err := StartInformer(ctx, informer); err == nil {
+		t.Error("StartInformer() = nil, wanted an error once ctx is done")
+	}
+}
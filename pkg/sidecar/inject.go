@@ -46,6 +46,7 @@ const (
 
 type SidecarArgs struct {
 	// Always required.
+	SourceContainer             *corev1.Container
 	SinkURIVar, OutputFormatVar *corev1.EnvVar
 	Image                       string
 	Port                        int32
@@ -99,7 +100,7 @@ func Inject(pod *corev1.Pod) {
 func constructArgs(pod *corev1.Pod) (*SidecarArgs, *apis.FieldError) {
 	var errs *apis.FieldError
 
-	_, srcSinkURI, srcOutputFormat, ok := getSourceContainer(pod)
+	srcContainer, srcSinkURI, srcOutputFormat, ok := getSourceContainer(pod)
 	if !ok {
 		if srcSinkURI == nil || srcSinkURI.Value == "" {
 			errs = errs.Also(apis.ErrMissingField("K_SINK").ViaField("spec.containers[i].Env"))
@@ -127,6 +128,7 @@ func constructArgs(pod *corev1.Pod) (*SidecarArgs, *apis.FieldError) {
 	}
 
 	return &SidecarArgs{
+		SourceContainer: srcContainer,
 		SinkURIVar:      srcSinkURI,
 		OutputFormatVar: srcOutputFormat,
 		Image:           img,
@@ -165,8 +167,14 @@ func injectSidecar(pod *corev1.Pod, args *SidecarArgs) {
 		}},
 	}
 
-	// Rewire the source container
-	args.SinkURIVar.Value = "http://127.0.0.1:" + portStr
+	// Rewire the source container, and tell it where the sidecar is so that
+	// it can ask the sidecar to quit.
+	sidecarURI := "http://127.0.0.1:" + portStr
+	args.SinkURIVar.Value = sidecarURI
+	args.SourceContainer.Env = append(args.SourceContainer.Env, corev1.EnvVar{
+		Name:  "K_SIDECAR",
+		Value: sidecarURI,
+	})
 
 	// Add the sidecar container
 	pod.Spec.Containers = append(pod.Spec.Containers, sidecarContainer)
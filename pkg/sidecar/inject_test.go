@@ -17,3 +17,37 @@ func TestGetArgsMissingAll(t *testing.T) {
 // TODO(spencer-p):
 // - Test happy case
 // - Test no ports available (start with 65535 with a container already mapped)
+
+func TestInjectSidecar(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "source",
+				Env: []corev1.EnvVar{
+					{Name: "K_SINK", Value: "http://sink.example.com/"},
+					{Name: "K_OUTPUT_FORMAT", Value: "binary"},
+				},
+			}},
+		},
+	}
+	args := &SidecarArgs{Image: "adapter", Port: SIDECAR_DEFAULT_PORT, EventSource: "source", EventType: "type"}
+	args.SourceContainer, args.SinkURIVar, args.OutputFormatVar, _ = getSourceContainer(&pod)
+
+	injectSidecar(&pod, args)
+
+	if got, want := len(pod.Spec.Containers), 2; got != want {
+		t.Fatalf("len(Containers) = %d, want %d", got, want)
+	}
+	env := map[string]string{}
+	for _, e := range pod.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	for name, want := range map[string]string{
+		"K_SINK":    "http://127.0.0.1:38080",
+		"K_SIDECAR": "http://127.0.0.1:38080",
+	} {
+		if got := env[name]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sourcesdk helps write the containers of sources. It reads the
+// environment that the runtime contract gives them, sends their events to
+// the sink in the format it asks for, and shuts them down the way the
+// contract expects.
+//
+// A container is usually just
+//
+//	func main() {
+//		sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+//			event := cloudevents.NewEvent()
+//			event.SetType("com.example.hello")
+//			_, err := sender.Send(ctx, event)
+//			return err
+//		})
+//	}
+package sourcesdk
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+// Env is the environment that the runtime contract gives the containers of
+// a source.
+type Env struct {
+	// Sink is the URI to send events to.
+	Sink string `envconfig:"K_SINK" required:"true"`
+
+	// OutputFormat is the encoding to send events in.
+	OutputFormat v1alpha1.OutputFormatType `envconfig:"K_OUTPUT_FORMAT" required:"true"`
+
+	// CloudEventOverrides are applied to every event sent.
+	CloudEventOverrides CloudEventOverrides `envconfig:"K_CE_OVERRIDES"`
+
+	// CloudEventSource is the canonical CloudEvents source of the source.
+	// Events sent without a source get this one.
+	CloudEventSource string `envconfig:"K_CE_SOURCE"`
+
+	// SourceKind, SourceName, SourceNamespace and SourceUID identify the
+	// source that the container runs for.
+	SourceKind      string `envconfig:"K_SOURCE_KIND"`
+	SourceName      string `envconfig:"K_SOURCE_NAME"`
+	SourceNamespace string `envconfig:"K_SOURCE_NAMESPACE"`
+	SourceUID       string `envconfig:"K_SOURCE_UID"`
+
+	// ScheduledTime is when the run of a CronJobSource was scheduled, or
+	// zero for other sources.
+	ScheduledTime time.Time `envconfig:"K_SCHEDULED_TIME"`
+
+	// DeliveryRetry is how many more times an event is sent after the sink
+	// fails to accept it.
+	DeliveryRetry int `envconfig:"K_DELIVERY_RETRY" default:"0"`
+
+	// DeliveryBackoffDelay is how long to wait before sending an event
+	// again. It doubles after every retry.
+	DeliveryBackoffDelay time.Duration `envconfig:"K_DELIVERY_BACKOFF_DELAY" default:"1s"`
+
+	// DeliveryTimeout bounds how long the sink may take to accept an event.
+	DeliveryTimeout time.Duration `envconfig:"K_DELIVERY_TIMEOUT" default:"30s"`
+
+	// Sidecar is the URI of the sidecar that was injected into the pod, if
+	// any. It is told to quit when the container is done.
+	Sidecar string `envconfig:"K_SIDECAR"`
+}
+
+// CloudEventOverrides are what K_CE_OVERRIDES changes in the events that a
+// container sends, as JSON such as {"extensions":{"team":"blue"}}.
+type CloudEventOverrides struct {
+	// Extensions are set on every event, replacing those of the same name.
+	Extensions map[string]string `json:"extensions,omitempty"`
+}
+
+// Decode implements envconfig.Decoder.
+func (o *CloudEventOverrides) Decode(value string) error {
+	return json.Unmarshal([]byte(value), o)
+}
+
+// ProcessEnv reads the Env of the container.
+func ProcessEnv() (*Env, error) {
+	var env Env
+	if err := envconfig.Process("", &env); err != nil {
+		return nil, err
+	}
+	if err := env.Validate(); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// Validate checks that the Env can be used to send events.
+func (e *Env) Validate() error {
+	if e.Sink == "" {
+		return fmt.Errorf("K_SINK is empty")
+	}
+	switch e.OutputFormat {
+	case v1alpha1.OutputFormatBinary, v1alpha1.OutputFormatStructured:
+	default:
+		return fmt.Errorf("invalid K_OUTPUT_FORMAT %q", e.OutputFormat)
+	}
+	if e.DeliveryRetry < 0 {
+		return fmt.Errorf("invalid K_DELIVERY_RETRY %d, must not be negative", e.DeliveryRetry)
+	}
+	if e.DeliveryBackoffDelay <= 0 {
+		return fmt.Errorf("invalid K_DELIVERY_BACKOFF_DELAY %v, must be positive", e.DeliveryBackoffDelay)
+	}
+	if e.DeliveryTimeout <= 0 {
+		return fmt.Errorf("invalid K_DELIVERY_TIMEOUT %v, must be positive", e.DeliveryTimeout)
+	}
+	return nil
+}
+
+type envKey struct{}
+
+// EnvFromContext returns the Env that Run attached to the context, or nil
+// if it has none.
+func EnvFromContext(ctx context.Context) *Env {
+	env, _ := ctx.Value(envKey{}).(*Env)
+	return env
+}
+
+// withEnv attaches the Env to the context.
+func withEnv(ctx context.Context, env *Env) context.Context {
+	return context.WithValue(ctx, envKey{}, env)
+}
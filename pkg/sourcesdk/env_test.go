@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// setEnv sets the environment variables, and returns a function that
+// restores them.
+func setEnv(env map[string]string) func() {
+	old := map[string]*string{}
+	for name, value := range env {
+		if v, ok := os.LookupEnv(name); ok {
+			old[name] = &v
+		} else {
+			old[name] = nil
+		}
+		os.Setenv(name, value)
+	}
+	return func() {
+		for name, value := range old {
+			if value != nil {
+				os.Setenv(name, *value)
+			} else {
+				os.Unsetenv(name)
+			}
+		}
+	}
+}
+
+func TestProcessEnv(t *testing.T) {
+	defer setEnv(map[string]string{
+		"K_SINK":                   "http://sink.example.com/",
+		"K_OUTPUT_FORMAT":          "structured",
+		"K_CE_OVERRIDES":           `{"extensions":{"team":"blue"}}`,
+		"K_CE_SOURCE":              "/apis/v1/namespaces/default/jobsources/steve",
+		"K_SOURCE_KIND":            "JobSource",
+		"K_SOURCE_NAME":            "steve",
+		"K_SOURCE_NAMESPACE":       "default",
+		"K_SOURCE_UID":             "1234",
+		"K_SCHEDULED_TIME":         "2019-08-01T10:00:00Z",
+		"K_DELIVERY_RETRY":         "3",
+		"K_DELIVERY_BACKOFF_DELAY": "100ms",
+		"K_SIDECAR":                "http://127.0.0.1:38080",
+	})()
+
+	got, err := ProcessEnv()
+	if err != nil {
+		t.Fatalf("ProcessEnv() = %v", err)
+	}
+	want := &Env{
+		Sink:                 "http://sink.example.com/",
+		OutputFormat:         "structured",
+		CloudEventOverrides:  CloudEventOverrides{Extensions: map[string]string{"team": "blue"}},
+		CloudEventSource:     "/apis/v1/namespaces/default/jobsources/steve",
+		SourceKind:           "JobSource",
+		SourceName:           "steve",
+		SourceNamespace:      "default",
+		SourceUID:            "1234",
+		ScheduledTime:        time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC),
+		DeliveryRetry:        3,
+		DeliveryBackoffDelay: 100 * time.Millisecond,
+		DeliveryTimeout:      30 * time.Second,
+		Sidecar:              "http://127.0.0.1:38080",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ProcessEnv() (-want, +got) = %s", diff)
+	}
+}
+
+func TestProcessEnvInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"missing sink":    {"K_OUTPUT_FORMAT": "binary"},
+		"invalid format":  {"K_SINK": "http://sink.example.com/", "K_OUTPUT_FORMAT": "messenger_pigeon"},
+		"invalid json":    {"K_SINK": "http://sink.example.com/", "K_OUTPUT_FORMAT": "binary", "K_CE_OVERRIDES": "{"},
+		"negative retry":  {"K_SINK": "http://sink.example.com/", "K_OUTPUT_FORMAT": "binary", "K_DELIVERY_RETRY": "-1"},
+		"zero timeout":    {"K_SINK": "http://sink.example.com/", "K_OUTPUT_FORMAT": "binary", "K_DELIVERY_TIMEOUT": "0s"},
+		"invalid backoff": {"K_SINK": "http://sink.example.com/", "K_OUTPUT_FORMAT": "binary", "K_DELIVERY_BACKOFF_DELAY": "soon"},
+	}
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			all := map[string]string{"K_SINK": "", "K_OUTPUT_FORMAT": ""}
+			for name, value := range env {
+				all[name] = value
+			}
+			defer setEnv(all)()
+			if _, err := ProcessEnv(); err == nil {
+				t.Error("ProcessEnv() = nil, wanted an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// quitTimeout bounds how long the sidecar may take to be told to quit.
+const quitTimeout = 5 * time.Second
+
+// Main runs fn with Run and exits. It exits with 0 only if fn returned nil,
+// so that a JobSource or CronJobSource whose run failed, or was stopped
+// before it finished, is retried by its Job.
+func Main(fn func(context.Context, Sender) error) {
+	if err := Run(context.Background(), fn); err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Run reads the Env of the container and calls fn with a Sender for its
+// sink. The context given to fn has the Env, and is cancelled when the
+// container is asked to stop with SIGTERM or SIGINT; fn should then return
+// soon. A long running source, such as a ServiceSource, returns nil once it
+// has shut down. A source that runs to completion returns an error if it
+// did not finish, so that it is run again.
+//
+// Once fn returns, Run tells the sidecar that was injected into the pod,
+// if any, to quit, so that the pod of a Job can complete.
+func Run(ctx context.Context, fn func(context.Context, Sender) error) error {
+	env, err := ProcessEnv()
+	if err != nil {
+		return err
+	}
+	sender, err := NewSender(env)
+	if err != nil {
+		return err
+	}
+	return run(ctx, env, sender, fn)
+}
+
+// run is Run once the Env and the Sender are made.
+func run(ctx context.Context, env *Env, sender Sender, fn func(context.Context, Sender) error) error {
+	ctx, cancel := context.WithCancel(withEnv(ctx, env))
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case sig := <-signals:
+			log.Printf("Received %v, shutting down", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// The sidecar is told to quit even if fn panics, or the pod would never
+	// complete.
+	defer quitSidecar(env)
+	return fn(ctx, sender)
+}
+
+// quitSidecar tells the sidecar of the pod, if any, to quit.
+func quitSidecar(env *Env) {
+	if env.Sidecar == "" {
+		return
+	}
+	client := &http.Client{Timeout: quitTimeout}
+	resp, err := client.Post(strings.TrimSuffix(env.Sidecar, "/")+"/quitquitquit", "", nil)
+	if err != nil {
+		log.Printf("Failed to tell the sidecar to quit: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Failed to tell the sidecar to quit: %s", resp.Status)
+	}
+}
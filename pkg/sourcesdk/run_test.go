@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		sidecar bool
+	}{
+		{name: "success"},
+		{name: "failure", err: errors.New("failed")},
+		{name: "success with sidecar", sidecar: true},
+		{name: "failure with sidecar", err: errors.New("failed"), sidecar: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quit := 0
+			sidecar := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/quitquitquit" {
+					quit++
+				}
+			}))
+			defer sidecar.Close()
+
+			env := testEnv()
+			if test.sidecar {
+				env.Sidecar = sidecar.URL
+			}
+			client := &fakeClient{}
+
+			err := run(context.Background(), env, client, func(ctx context.Context, s Sender) error {
+				if got := EnvFromContext(ctx); got != env {
+					t.Errorf("EnvFromContext() = %v, want %v", got, env)
+				}
+				if s != client {
+					t.Error("fn was not given the Sender")
+				}
+				if quit != 0 {
+					t.Error("sidecar was told to quit before fn returned")
+				}
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("run() = %v, want %v", err, test.err)
+			}
+
+			want := 0
+			if test.sidecar {
+				want = 1
+			}
+			if quit != want {
+				t.Errorf("sidecar was told to quit %d times, want %d", quit, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+
+	"github.com/n3wscott/sources/pkg/cloudeventclient"
+)
+
+// Sender sends events to the sink of a source. A cloudevents.Client is a
+// Sender.
+type Sender interface {
+	// Send sends the event, and returns the event that the sink responded
+	// with, if any.
+	Send(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, error)
+}
+
+// NewSender returns a Sender that sends events to the sink of env in its
+// output format. It sets the source of events that have none, applies the
+// CloudEventOverrides, and retries as the delivery settings of env say.
+// Like a cloudevents.Client, it may change the context of the events it
+// sends.
+func NewSender(env *Env) (Sender, error) {
+	client, err := cloudeventclient.New(env.OutputFormat, env.Sink)
+	if err != nil {
+		return nil, err
+	}
+	return &sender{client: client, env: env}, nil
+}
+
+type sender struct {
+	client Sender
+	env    *Env
+}
+
+var _ Sender = (*sender)(nil)
+
+// Send implements Sender.
+func (s *sender) Send(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, error) {
+	if event.Context == nil {
+		return nil, fmt.Errorf("event has no context")
+	}
+	if event.Source() == "" && s.env.CloudEventSource != "" {
+		if err := event.Context.SetSource(s.env.CloudEventSource); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range s.env.CloudEventOverrides.Extensions {
+		if err := event.Context.SetExtension(name, value); err != nil {
+			return nil, fmt.Errorf("failed to override extension %q: %v", name, err)
+		}
+	}
+
+	delay := s.env.DeliveryBackoffDelay
+	for retry := 0; ; retry++ {
+		resp, err := s.send(ctx, event)
+		if err == nil || retry >= s.env.DeliveryRetry {
+			return resp, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send makes one attempt at sending the event.
+func (s *sender) send(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.env.DeliveryTimeout)
+	defer cancel()
+	return s.client.Send(ctx, event)
+}
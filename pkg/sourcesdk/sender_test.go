@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourcesdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+)
+
+// fakeClient fails the first failures sends, and records the events sent.
+type fakeClient struct {
+	failures int
+	sent     []cloudevents.Event
+}
+
+func (c *fakeClient) Send(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, error) {
+	c.sent = append(c.sent, event)
+	if len(c.sent) <= c.failures {
+		return nil, errors.New("sink unavailable")
+	}
+	return nil, nil
+}
+
+func testEnv() *Env {
+	return &Env{
+		Sink:                 "http://sink.example.com/",
+		OutputFormat:         "binary",
+		CloudEventSource:     "/apis/v1/namespaces/default/jobsources/steve",
+		CloudEventOverrides:  CloudEventOverrides{Extensions: map[string]string{"team": "blue"}},
+		DeliveryBackoffDelay: time.Millisecond,
+		DeliveryTimeout:      time.Second,
+	}
+}
+
+func TestSenderDefaults(t *testing.T) {
+	client := &fakeClient{}
+	s := &sender{client: client, env: testEnv()}
+
+	event := cloudevents.NewEvent()
+	event.SetType("com.example.hello")
+	event.SetExtension("team", "red")
+	if _, err := s.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+
+	got := client.sent[0]
+	if got, want := got.Source(), "/apis/v1/namespaces/default/jobsources/steve"; got != want {
+		t.Errorf("Source() = %q, want %q", got, want)
+	}
+	var team string
+	if err := got.ExtensionAs("team", &team); err != nil || team != "blue" {
+		t.Errorf("ExtensionAs(team) = %q, %v, want the override blue", team, err)
+	}
+
+	event = cloudevents.NewEvent()
+	event.SetType("com.example.hello")
+	event.SetSource("http://example.com/")
+	if _, err := s.Send(context.Background(), event); err != nil {
+		t.Fatalf("Send() = %v", err)
+	}
+	if got, want := client.sent[1].Source(), "http://example.com/"; got != want {
+		t.Errorf("Source() = %q, want the source of the application %q", got, want)
+	}
+}
+
+func TestSenderRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		retry     int
+		failures  int
+		wantSends int
+		wantErr   bool
+	}{
+		{name: "no retry", retry: 0, failures: 1, wantSends: 1, wantErr: true},
+		{name: "retried", retry: 2, failures: 2, wantSends: 3},
+		{name: "retries exhausted", retry: 2, failures: 5, wantSends: 3, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &fakeClient{failures: test.failures}
+			env := testEnv()
+			env.DeliveryRetry = test.retry
+			s := &sender{client: client, env: env}
+
+			event := cloudevents.NewEvent()
+			event.SetType("com.example.hello")
+			_, err := s.Send(context.Background(), event)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Send() = %v, wantErr %v", err, test.wantErr)
+			}
+			if got := len(client.sent); got != test.wantSends {
+				t.Errorf("sent %d times, want %d", got, test.wantSends)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The conformance command checks a source binary or image against the
+// runtime contract, and writes a JSON report. It exits with 1 if the source
+// fails any required check.
+//
+//	conformance -image gcr.io/example/source -kind job -report report.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/n3wscott/sources/test/conformance"
+)
+
+// envFlags are the repeated -env flags.
+type envFlags map[string]string
+
+func (e envFlags) String() string {
+	var pairs []string
+	for name, value := range e {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (e envFlags) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("want NAME=VALUE, got %q", value)
+	}
+	e[parts[0]] = parts[1]
+	return nil
+}
+
+func main() {
+	var (
+		config  conformance.Config
+		binary  = flag.String("binary", "", "path of the source binary to check")
+		image   = flag.String("image", "", "source image to check")
+		runtime = flag.String("runtime", "docker", "command that runs images")
+		kind    = flag.String("kind", string(conformance.KindJob), "how the source runs: job or service")
+		report  = flag.String("report", "", "file to write the report to, or stdout")
+		env     = envFlags{}
+	)
+	flag.DurationVar(&config.Timeout, "timeout", 0, "how long the source has to send its first event, and a job to exit (default 1m)")
+	flag.DurationVar(&config.StayUp, "stay-up", 0, "how long a service must stay up (default 5s)")
+	flag.DurationVar(&config.GracePeriod, "grace-period", 0, "how long a service may take to exit after SIGTERM (default 10s)")
+	flag.Var(env, "env", "NAME=VALUE to add to the environment of the source; may be repeated")
+	flag.Parse()
+
+	switch {
+	case *binary != "" && *image == "":
+		config.Launcher = conformance.Binary{Path: *binary, Args: flag.Args()}
+	case *image != "" && *binary == "":
+		config.Launcher = conformance.Image{Name: *image, Runtime: *runtime}
+	default:
+		log.Fatal("Exactly one of -binary and -image is required")
+	}
+	switch conformance.Kind(*kind) {
+	case conformance.KindJob, conformance.KindService:
+		config.Kind = conformance.Kind(*kind)
+	default:
+		log.Fatalf("Unknown kind %q, want job or service", *kind)
+	}
+	config.Env = env
+
+	r := conformance.Run(config)
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Fatal("Failed to encode the report: ", err)
+	}
+	b = append(b, '\n')
+	if *report == "" {
+		os.Stdout.Write(b)
+	} else if err := ioutil.WriteFile(*report, b, 0644); err != nil {
+		log.Fatal("Failed to write the report: ", err)
+	}
+
+	if !r.Passed {
+		os.Exit(1)
+	}
+}
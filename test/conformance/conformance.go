@@ -0,0 +1,395 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+// Kind is how a source runs, which decides whether it must exit.
+type Kind string
+
+const (
+	// KindJob is a source that runs to completion, such as a JobSource or
+	// a CronJobSource. It must send its events and exit with 0.
+	KindJob Kind = "job"
+
+	// KindService is a source that runs until it is stopped, such as a
+	// ServiceSource. It must stay up, and exit once it gets SIGTERM.
+	KindService Kind = "service"
+)
+
+const (
+	// Namespace and Name are those of the source that the suite pretends
+	// to run.
+	Namespace = "conformance"
+	Name      = "conformance"
+	// UID is the UID of the source that the suite pretends to run.
+	UID = "00000000-0000-0000-0000-000000000000"
+
+	// OverrideExtension is the extension that the suite asks sources to
+	// set on their events with K_CE_OVERRIDES, with the value
+	// OverrideValue.
+	OverrideExtension = "conformance"
+	OverrideValue     = "honoured"
+
+	// maxOutput is how much of the output of a source the report keeps.
+	maxOutput = 4096
+)
+
+// Formats are the output formats that a source is run with.
+var Formats = []v1alpha1.OutputFormatType{v1alpha1.OutputFormatBinary, v1alpha1.OutputFormatStructured}
+
+// Config says how to run a source under test.
+type Config struct {
+	Launcher Launcher
+	Kind     Kind
+
+	// Timeout is how long the source has to send its first event, and for
+	// a KindJob to exit. One minute by default.
+	Timeout time.Duration
+
+	// StayUp is how long a KindService must stay up. Five seconds by
+	// default.
+	StayUp time.Duration
+
+	// GracePeriod is how long a KindService may take to exit after
+	// SIGTERM. Ten seconds by default.
+	GracePeriod time.Duration
+
+	// Env is added to the environment of the source, such as to configure
+	// it.
+	Env map[string]string
+}
+
+func (c *Config) setDefaults() {
+	if c.Timeout == 0 {
+		c.Timeout = time.Minute
+	}
+	if c.StayUp == 0 {
+		c.StayUp = 5 * time.Second
+	}
+	if c.GracePeriod == 0 {
+		c.GracePeriod = 10 * time.Second
+	}
+}
+
+// Report is the outcome of running a source against the runtime contract.
+type Report struct {
+	// Contract is the document that the source was checked against.
+	Contract string `json:"contract"`
+	Source   string `json:"source"`
+	Kind     Kind   `json:"kind"`
+
+	// Passed is true if every required check passed for every format.
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Result is the outcome of running a source with one output format.
+type Result struct {
+	Format v1alpha1.OutputFormatType `json:"format"`
+	Passed bool                      `json:"passed"`
+	Events int                       `json:"events"`
+	Checks []Check                   `json:"checks"`
+
+	// ExitCode is the exit code of the source, if it exited.
+	ExitCode *int `json:"exitCode,omitempty"`
+
+	// Output is the end of what the source wrote to stdout and stderr.
+	Output string `json:"output,omitempty"`
+}
+
+// Check is the outcome of one rule of the runtime contract. Checks that
+// are not Required are what the contract recommends.
+type Check struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Passed   bool   `json:"passed"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Run runs the source of the Config once with each of the Formats, and
+// reports how it kept to the runtime contract.
+func Run(config Config) *Report {
+	config.setDefaults()
+	report := &Report{
+		Contract: "docs/runtime-contract.md",
+		Source:   config.Launcher.String(),
+		Kind:     config.Kind,
+		Passed:   true,
+	}
+	for _, format := range Formats {
+		result := runFormat(config, format)
+		report.Passed = report.Passed && result.Passed
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// runFormat runs the source once with the given output format.
+func runFormat(config Config, format v1alpha1.OutputFormatType) Result {
+	sink := NewRecordingSink()
+	defer sink.Close()
+
+	result := Result{Format: format}
+	env, err := environment(config, sink.URI(), format)
+	if err != nil {
+		result.Checks = append(result.Checks, Check{Name: "start", Required: true, Message: err.Error()})
+		return result
+	}
+
+	output := &bytes.Buffer{}
+	cmd := config.Launcher.Command(env)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		result.Checks = append(result.Checks, Check{Name: "start", Required: true, Message: err.Error()})
+		return result
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var lifecycle Check
+	switch config.Kind {
+	case KindService:
+		lifecycle = runService(config, cmd, sink, done)
+	default:
+		lifecycle = runJob(config, cmd, done)
+	}
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		code := cmd.ProcessState.ExitCode()
+		result.ExitCode = &code
+	}
+	result.Output = tail(output.String(), maxOutput)
+
+	requests := sink.Requests()
+	result.Events = len(requests)
+	result.Checks = append([]Check{lifecycle}, checkEvents(requests, format, env["K_CE_SOURCE"])...)
+	result.Passed = true
+	for _, check := range result.Checks {
+		if check.Required && !check.Passed {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// environment returns what the controller would give the containers of
+// the source, and the overrides that the suite asks for.
+func environment(config Config, sink string, format v1alpha1.OutputFormatType) (map[string]string, error) {
+	kind := "JobSource"
+	if config.Kind == KindService {
+		kind = "ServiceSource"
+	}
+	env := map[string]string{
+		"K_SINK":             sink,
+		"K_OUTPUT_FORMAT":    string(format),
+		"K_CE_SOURCE":        fmt.Sprintf("/apis/v1/namespaces/%s/%ss/%s", Namespace, strings.ToLower(kind), Name),
+		"K_SOURCE_KIND":      kind,
+		"K_SOURCE_NAME":      Name,
+		"K_SOURCE_NAMESPACE": Namespace,
+		"K_SOURCE_UID":       UID,
+		"K_CE_OVERRIDES":     fmt.Sprintf(`{"extensions":{%q:%q}}`, OverrideExtension, OverrideValue),
+	}
+	if config.Kind == KindService {
+		port, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		env["PORT"] = strconv.Itoa(port)
+	}
+	for name, value := range config.Env {
+		env[name] = value
+	}
+	return env, nil
+}
+
+// runJob waits for a source that runs to completion to exit.
+func runJob(config Config, cmd *exec.Cmd, done <-chan error) Check {
+	check := Check{Name: "lifecycle", Required: true}
+	select {
+	case err := <-done:
+		if err != nil {
+			check.Message = fmt.Sprintf("exited with %v, wanted exit code 0", err)
+			return check
+		}
+		check.Passed = true
+	case <-time.After(config.Timeout):
+		cmd.Process.Kill()
+		<-done
+		check.Message = fmt.Sprintf("did not exit within %v", config.Timeout)
+	}
+	return check
+}
+
+// runService checks that a source that runs until it is stopped stays up,
+// and then stops it. A source that exits before it gets SIGTERM fails, however
+// long it stayed up.
+func runService(config Config, cmd *exec.Cmd, sink *RecordingSink, done <-chan error) Check {
+	check := Check{Name: "lifecycle", Required: true}
+	start := time.Now()
+	exited := func(err error) Check {
+		check.Message = fmt.Sprintf("exited with %v after %v, wanted it to stay up until SIGTERM", err, time.Since(start).Round(time.Millisecond))
+		return check
+	}
+
+	// Give the source time to send its first event before it has to have
+	// stayed up.
+	select {
+	case err := <-done:
+		return exited(err)
+	case <-sink.Received():
+	case <-time.After(config.Timeout):
+	}
+	select {
+	case err := <-done:
+		return exited(err)
+	case <-time.After(config.StayUp):
+	}
+	// The source may have exited just as StayUp passed.
+	select {
+	case err := <-done:
+		return exited(err)
+	default:
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); errors.Is(err, os.ErrProcessDone) {
+		return exited(<-done)
+	} else if err != nil {
+		check.Message = fmt.Sprintf("could not send SIGTERM: %v", err)
+		cmd.Process.Kill()
+		<-done
+		return check
+	}
+	select {
+	case <-done:
+		check.Passed = true
+	case <-time.After(config.GracePeriod):
+		cmd.Process.Kill()
+		<-done
+		check.Message = fmt.Sprintf("did not exit within %v of SIGTERM", config.GracePeriod)
+	}
+	return check
+}
+
+// checkEvents checks the requests that the sink received against the
+// contract.
+func checkEvents(requests []Request, format v1alpha1.OutputFormatType, source string) []Check {
+	events := Check{Name: "events", Required: true}
+	post := Check{Name: "http-post", Required: true}
+	encoding := Check{Name: "encoding", Required: true}
+	attributes := Check{Name: "attributes", Required: true}
+	overrides := Check{Name: "overrides", Required: true}
+	ceSource := Check{Name: "source"}
+	checks := []*Check{&events, &post, &encoding, &attributes, &overrides, &ceSource}
+
+	if len(requests) == 0 {
+		for _, check := range checks {
+			check.Message = "no events were sent to K_SINK"
+		}
+		return dereference(checks)
+	}
+	events.Passed = true
+
+	// fail records the first failure of each check.
+	fail := func(check *Check, i int, format string, a ...interface{}) {
+		if check.Message == "" {
+			check.Message = fmt.Sprintf("request %d: ", i) + fmt.Sprintf(format, a...)
+		}
+	}
+	for i, r := range requests {
+		if r.Method != http.MethodPost {
+			fail(&post, i, "method %s, wanted POST", r.Method)
+		}
+		event, err := ParseEvent(r)
+		if err != nil {
+			fail(&encoding, i, "%v", err)
+			fail(&attributes, i, "%v", err)
+			fail(&overrides, i, "%v", err)
+			fail(&ceSource, i, "%v", err)
+			continue
+		}
+		if event.Encoding != format {
+			fail(&encoding, i, "%s encoding, wanted %s", event.Encoding, format)
+		}
+		for _, name := range requiredAttributes(event) {
+			if event.Attributes[name] == "" {
+				fail(&attributes, i, "missing the %s attribute", name)
+			}
+		}
+		if got := event.Attributes[OverrideExtension]; got != OverrideValue {
+			fail(&overrides, i, "extension %s is %q, wanted %q from K_CE_OVERRIDES", OverrideExtension, got, OverrideValue)
+		}
+		if got := event.Attributes["source"]; got != source {
+			fail(&ceSource, i, "source is %q, K_CE_SOURCE is %q", got, source)
+		}
+	}
+	for _, check := range checks[1:] {
+		check.Passed = check.Message == ""
+	}
+	return dereference(checks)
+}
+
+// requiredAttributes returns the attributes that every event of the spec
+// version of event must have.
+func requiredAttributes(event *Event) []string {
+	if event.Attributes["cloudeventsversion"] != "" {
+		return []string{"cloudeventsversion", "eventid", "eventtype", "source"}
+	}
+	return []string{"specversion", "id", "type", "source"}
+}
+
+func dereference(checks []*Check) []Check {
+	var out []Check
+	for _, check := range checks {
+		out = append(out, *check)
+	}
+	return out
+}
+
+// freePort returns a port on this machine that nothing listens on.
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// tail returns the last max bytes of s.
+func tail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[len(s)-max:]
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+
+	"github.com/n3wscott/sources/pkg/sourcesdk"
+)
+
+// helperEnv makes the test binary run as the source named by its value,
+// rather than run the tests.
+const helperEnv = "CONFORMANCE_TEST_SOURCE"
+
+func TestMain(m *testing.M) {
+	if source, ok := os.LookupEnv(helperEnv); ok {
+		runHelperSource(source)
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runHelperSource runs one of the sources that the tests check.
+func runHelperSource(source string) {
+	send := func(ctx context.Context, sender sourcesdk.Sender) error {
+		event := cloudevents.NewEvent()
+		event.SetType("dev.knative.sources.conformance.test")
+		_, err := sender.Send(ctx, event)
+		return err
+	}
+	switch source {
+	case "job":
+		sourcesdk.Main(send)
+	case "service":
+		sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+			if err := send(ctx, sender); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return nil
+		})
+	case "failing-job":
+		sourcesdk.Main(func(ctx context.Context, sender sourcesdk.Sender) error {
+			if err := send(ctx, sender); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		})
+	case "plain-job":
+		// Sends in the binary encoding whatever K_OUTPUT_FORMAT says, and
+		// with a source of its own.
+		req, _ := http.NewRequest(http.MethodPost, os.Getenv("K_SINK"), nil)
+		req.Header.Set("Ce-Specversion", "0.3")
+		req.Header.Set("Ce-Id", "1")
+		req.Header.Set("Ce-Type", "dev.knative.sources.conformance.test")
+		req.Header.Set("Ce-Source", "http://example.com/")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		resp.Body.Close()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown source %q\n", source)
+		os.Exit(1)
+	}
+}
+
+// helper returns a Launcher for the source of the test binary.
+func helper(source string) Launcher {
+	os.Setenv(helperEnv, source)
+	return Binary{Path: os.Args[0]}
+}
+
+// failed returns the names of the checks that failed in the report.
+func failed(report *Report) map[string]bool {
+	names := map[string]bool{}
+	for _, result := range report.Results {
+		for _, check := range result.Checks {
+			if !check.Passed {
+				names[check.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+func TestRun(t *testing.T) {
+	defer os.Unsetenv(helperEnv)
+
+	tests := []struct {
+		name       string
+		source     string
+		kind       Kind
+		wantPassed bool
+		wantFailed []string
+	}{{
+		name:       "job",
+		source:     "job",
+		kind:       KindJob,
+		wantPassed: true,
+	}, {
+		name:       "service",
+		source:     "service",
+		kind:       KindService,
+		wantPassed: true,
+	}, {
+		name:       "job that exits with 1",
+		source:     "failing-job",
+		kind:       KindJob,
+		wantFailed: []string{"lifecycle"},
+	}, {
+		name:       "job run as a service",
+		source:     "job",
+		kind:       KindService,
+		wantFailed: []string{"lifecycle"},
+	}, {
+		name:       "job that ignores the contract",
+		source:     "plain-job",
+		kind:       KindJob,
+		wantFailed: []string{"encoding", "overrides", "source"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := Run(Config{
+				Launcher:    helper(test.source),
+				Kind:        test.kind,
+				Timeout:     10 * time.Second,
+				StayUp:      2 * time.Second,
+				GracePeriod: 5 * time.Second,
+			})
+
+			if report.Passed != test.wantPassed {
+				t.Errorf("Passed = %v, want %v: %+v", report.Passed, test.wantPassed, report)
+			}
+			if got, want := len(report.Results), len(Formats); got != want {
+				t.Fatalf("len(Results) = %d, want %d", got, want)
+			}
+			got := failed(report)
+			for _, name := range test.wantFailed {
+				if !got[name] {
+					t.Errorf("check %s passed, wanted it to fail: %+v", name, report)
+				}
+				delete(got, name)
+			}
+			for name := range got {
+				t.Errorf("check %s failed: %+v", name, report)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Launcher starts a source under test.
+type Launcher interface {
+	// Command returns the command that runs the source with the given
+	// environment, in addition to that of the suite.
+	Command(env map[string]string) *exec.Cmd
+
+	// String describes the source in the report.
+	String() string
+}
+
+// Binary launches a source that is an executable on this machine.
+type Binary struct {
+	Path string
+	Args []string
+}
+
+var _ Launcher = Binary{}
+
+// Command implements Launcher.
+func (b Binary) Command(env map[string]string) *exec.Cmd {
+	cmd := exec.Command(b.Path, b.Args...)
+	cmd.Env = os.Environ()
+	for _, name := range sortedKeys(env) {
+		cmd.Env = append(cmd.Env, name+"="+env[name])
+	}
+	return cmd
+}
+
+// String implements Launcher.
+func (b Binary) String() string {
+	return strings.Join(append([]string{b.Path}, b.Args...), " ")
+}
+
+// Image launches a source that is a container image, with the network of
+// the host so that it can reach the sink.
+type Image struct {
+	Name string
+
+	// Runtime is the command that runs containers, docker by default. It
+	// must take the arguments of docker run.
+	Runtime string
+}
+
+var _ Launcher = Image{}
+
+// Command implements Launcher.
+func (i Image) Command(env map[string]string) *exec.Cmd {
+	runtime := i.Runtime
+	if runtime == "" {
+		runtime = "docker"
+	}
+	args := []string{"run", "--rm", "--network=host"}
+	for _, name := range sortedKeys(env) {
+		args = append(args, "--env", name+"="+env[name])
+	}
+	return exec.Command(runtime, append(args, i.Name)...)
+}
+
+// String implements Launcher.
+func (i Image) String() string {
+	return fmt.Sprintf("image %s", i.Name)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
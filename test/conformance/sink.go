@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Knative Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/n3wscott/sources/pkg/apis/sources/v1alpha1"
+)
+
+// Request is an HTTP request that a RecordingSink received.
+type Request struct {
+	Method string
+	Header http.Header
+	Body   []byte
+}
+
+// RecordingSink is a sink that records the requests it receives, and
+// accepts them all.
+type RecordingSink struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []Request
+	received chan struct{}
+}
+
+// NewRecordingSink starts a RecordingSink. It must be closed.
+func NewRecordingSink() *RecordingSink {
+	s := &RecordingSink{received: make(chan struct{})}
+	s.server = httptest.NewServer(http.HandlerFunc(s.record))
+	return s
+}
+
+// URI is the URI of the sink.
+func (s *RecordingSink) URI() string {
+	return s.server.URL
+}
+
+// Close stops the sink.
+func (s *RecordingSink) Close() {
+	s.server.Close()
+}
+
+// Received is closed once the sink has received a request.
+func (s *RecordingSink) Received() <-chan struct{} {
+	return s.received
+}
+
+// Requests returns the requests that the sink received, in order.
+func (s *RecordingSink) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *RecordingSink) record(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Header: r.Header, Body: body})
+	if len(s.requests) == 1 {
+		close(s.received)
+	}
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Event is a CloudEvent as it arrived at a RecordingSink.
+type Event struct {
+	// Encoding is the format that the event was sent in.
+	Encoding v1alpha1.OutputFormatType
+
+	// Attributes are the context attributes and extensions of the event,
+	// by lowercase name.
+	Attributes map[string]string
+}
+
+// ParseEvent reads the CloudEvent that a request carries, in either
+// encoding of the HTTP binding.
+func ParseEvent(r Request) (*Event, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "application/cloudevents") {
+		return parseStructured(r)
+	}
+	attributes := map[string]string{}
+	for name, values := range r.Header {
+		if !strings.HasPrefix(strings.ToLower(name), "ce-") || len(values) == 0 {
+			continue
+		}
+		attributes[strings.ToLower(name[len("ce-"):])] = unquote(values[0])
+	}
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("neither a structured nor a binary CloudEvent, with Content-Type %q", r.Header.Get("Content-Type"))
+	}
+	return &Event{Encoding: v1alpha1.OutputFormatBinary, Attributes: attributes}, nil
+}
+
+func parseStructured(r Request) (*Event, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(r.Body, &fields); err != nil {
+		return nil, fmt.Errorf("structured CloudEvent is not a JSON object: %v", err)
+	}
+	attributes := map[string]string{}
+	for name, value := range fields {
+		switch name {
+		case "data", "data_base64":
+			continue
+		case "extensions":
+			// CloudEvents 0.1 nests the extensions.
+			if extensions, ok := value.(map[string]interface{}); ok {
+				for name, value := range extensions {
+					attributes[strings.ToLower(name)] = stringOf(value)
+				}
+				continue
+			}
+		}
+		attributes[strings.ToLower(name)] = stringOf(value)
+	}
+	return &Event{Encoding: v1alpha1.OutputFormatStructured, Attributes: attributes}, nil
+}
+
+// stringOf returns a JSON value as the string that the attribute would be
+// in a header.
+func stringOf(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// unquote returns the string that a header value holds, if it is a JSON
+// string. Some CloudEvents SDKs send extensions that way.
+func unquote(value string) string {
+	var s string
+	if strings.HasPrefix(value, `"`) && json.Unmarshal([]byte(value), &s) == nil {
+		return s
+	}
+	return value
+}